@@ -0,0 +1,320 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ACLUser is a user returned by ACL LIST
+type ACLUser struct {
+	Name  string
+	Rules string
+}
+
+// Enabled check whether the user is enabled by the "on" rule
+func (u ACLUser) Enabled() bool {
+	for _, rule := range SplitACLRules(u.Rules) {
+		if rule == "on" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SplitACLRules split rules by spaces, a selector in parentheses such as "(~key* +get)" is kept as one
+// rule, in the same way Redis merges the arguments of ACL SETUSER
+func SplitACLRules(rules string) []string {
+	result := make([]string, 0)
+	selector := make([]string, 0)
+	for _, field := range strings.Fields(rules) {
+		if len(selector) == 0 && (!strings.HasPrefix(field, "(") || strings.HasSuffix(field, ")")) {
+			result = append(result, field)
+			continue
+		}
+
+		selector = append(selector, field)
+		if strings.HasSuffix(field, ")") {
+			result = append(result, strings.Join(selector, " "))
+			selector = selector[:0]
+		}
+	}
+
+	// an unmatched parenthesis is kept as it is, Redis rejects it
+	if len(selector) > 0 {
+		result = append(result, strings.Join(selector, " "))
+	}
+
+	return result
+}
+
+// ParseACLList parse the reply of ACL LIST, every line is in the form of "user <name> <rules>"
+func ParseACLList(lines []string) []ACLUser {
+	users := make([]ACLUser, 0, len(lines))
+	for _, line := range lines {
+		segs := strings.SplitN(strings.TrimSpace(line), " ", 3)
+		if len(segs) < 2 || segs[0] != "user" {
+			continue
+		}
+
+		user := ACLUser{Name: segs[1]}
+		if len(segs) == 3 {
+			user.Rules = segs[2]
+		}
+
+		users = append(users, user)
+	}
+
+	return users
+}
+
+// ACLField is a field in the reply of ACL GETUSER, lists are joined by spaces
+type ACLField struct {
+	Name  string
+	Value string
+}
+
+// ParseACLGetUser parse the reply of ACL GETUSER, the order of fields is kept
+func ParseACLGetUser(reply interface{}) []ACLField {
+	items, _ := reply.([]interface{})
+	fields := make([]ACLField, 0, len(items)/2)
+	for i := 0; i+1 < len(items); i += 2 {
+		fields = append(fields, ACLField{Name: replyString(items[i]), Value: aclFieldValue(items[i+1])})
+	}
+
+	return fields
+}
+
+// aclFieldValue format a value of ACL GETUSER, nested lists such as selectors are wrapped in parentheses
+func aclFieldValue(value interface{}) string {
+	list, ok := value.([]interface{})
+	if !ok {
+		return replyString(value)
+	}
+
+	values := make([]string, 0, len(list))
+	for _, v := range list {
+		if _, nested := v.([]interface{}); nested {
+			values = append(values, "("+aclSelector(v)+")")
+			continue
+		}
+
+		values = append(values, aclFieldValue(v))
+	}
+
+	return strings.Join(values, " ")
+}
+
+// aclSelector format a selector of ACL GETUSER, which is a list of field-value pairs
+func aclSelector(reply interface{}) string {
+	values := make([]string, 0)
+	for _, f := range ParseACLGetUser(reply) {
+		values = append(values, f.Value)
+	}
+
+	return strings.Join(values, " ")
+}
+
+// ACLLogEntry is an entry of ACL LOG
+type ACLLogEntry struct {
+	Count      int64
+	Reason     string
+	Context    string
+	Object     string
+	Username   string
+	AgeSeconds float64
+	ClientInfo string
+}
+
+// ParseACLLog parse the reply of ACL LOG
+func ParseACLLog(reply interface{}) []ACLLogEntry {
+	items, _ := reply.([]interface{})
+	entries := make([]ACLLogEntry, 0, len(items))
+	for _, item := range items {
+		fields := replyMap(item)
+		entry := ACLLogEntry{
+			Reason:     replyString(fields["reason"]),
+			Context:    replyString(fields["context"]),
+			Object:     replyString(fields["object"]),
+			Username:   replyString(fields["username"]),
+			ClientInfo: replyString(fields["client-info"]),
+		}
+		entry.Count, _ = strconv.ParseInt(replyString(fields["count"]), 10, 64)
+		entry.AgeSeconds, _ = strconv.ParseFloat(replyString(fields["age-seconds"]), 64)
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// ToggleACLCategory cycle the rule of a command category in rules: +@category -> -@category -> removed -> +@category
+func ToggleACLCategory(rules string, category string) string {
+	allow, deny := "+@"+category, "-@"+category
+
+	result := make([]string, 0)
+	found := false
+	for _, rule := range SplitACLRules(rules) {
+		switch rule {
+		case allow:
+			result = append(result, deny)
+			found = true
+		case deny:
+			found = true
+		default:
+			result = append(result, rule)
+		}
+	}
+
+	if !found {
+		result = append(result, allow)
+	}
+
+	return strings.Join(result, " ")
+}
+
+// ACLCategoryRule return the rule of category in rules, it's one of "+", "-" and ""
+func ACLCategoryRule(rules string, category string) string {
+	for _, rule := range SplitACLRules(rules) {
+		if rule == "+@"+category {
+			return "+"
+		}
+		if rule == "-@"+category {
+			return "-"
+		}
+	}
+
+	return ""
+}
+
+// AddACLKeyPattern append a key pattern rule (~pattern) to rules if it's not present
+func AddACLKeyPattern(rules string, pattern string) string {
+	rule := "~" + strings.TrimPrefix(pattern, "~")
+	for _, r := range SplitACLRules(rules) {
+		if r == rule {
+			return rules
+		}
+	}
+
+	return strings.TrimSpace(rules + " " + rule)
+}
+
+// RedisACLList return the users by ACL LIST
+func RedisACLList(client RedisClient) ([]ACLUser, error) {
+	res, err := client.Do("ACL", "LIST").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	items, _ := res.([]interface{})
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, fmt.Sprint(item))
+	}
+
+	return ParseACLList(lines), nil
+}
+
+// RedisACLGetUser return the detail of a user by ACL GETUSER
+func RedisACLGetUser(client RedisClient, name string) ([]ACLField, error) {
+	res, err := client.Do("ACL", "GETUSER", name).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseACLGetUser(res), nil
+}
+
+// RedisACLSetUser create or replace a user by ACL SETUSER, the user is reset before applying rules,
+// so rules is the complete definition of the user. ACLs are not replicated, it's applied on every node
+func RedisACLSetUser(client RedisClient, name string, rules string) error {
+	args := []interface{}{"ACL", "SETUSER", name, "reset"}
+	for _, rule := range SplitACLRules(rules) {
+		args = append(args, rule)
+	}
+
+	return ForEachNode(client, func(addr string, node RedisClient) error {
+		if err := node.Do(args...).Err(); err != nil {
+			return fmt.Errorf("%s: %s", addr, err)
+		}
+
+		return nil
+	})
+}
+
+// RedisACLDelUser delete a user by ACL DELUSER on every node
+func RedisACLDelUser(client RedisClient, name string) error {
+	return ForEachNode(client, func(addr string, node RedisClient) error {
+		if err := node.Do("ACL", "DELUSER", name).Err(); err != nil {
+			return fmt.Errorf("%s: %s", addr, err)
+		}
+
+		return nil
+	})
+}
+
+// RedisACLDryRun check whether the user is able to execute command by ACL DRYRUN (redis 7+),
+// the returned message is the reason when the command is denied
+func RedisACLDryRun(client RedisClient, name string, command string) (bool, string, error) {
+	args := []interface{}{"ACL", "DRYRUN", name}
+	args = append(args, commandArgs(command)...)
+
+	res, err := client.Do(args...).String()
+	if err != nil {
+		return false, "", err
+	}
+
+	return res == "OK", res, nil
+}
+
+// RedisACLCategories return the command categories by ACL CAT
+func RedisACLCategories(client RedisClient) ([]string, error) {
+	res, err := client.Do("ACL", "CAT").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	items, _ := res.([]interface{})
+	categories := make([]string, 0, len(items))
+	for _, item := range items {
+		categories = append(categories, fmt.Sprint(item))
+	}
+
+	return categories, nil
+}
+
+// RedisACLLog return the recent entries of ACL LOG
+func RedisACLLog(client RedisClient, count int) ([]ACLLogEntry, error) {
+	res, err := client.Do("ACL", "LOG", count).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseACLLog(res), nil
+}
+
+// RedisACLLogReset clear the ACL LOG on every node
+func RedisACLLogReset(client RedisClient) error {
+	return redisACLOnNodes(client, "LOG", "RESET")
+}
+
+// RedisACLSave save the ACLs into the acl file by ACL SAVE on every node
+func RedisACLSave(client RedisClient) error {
+	return redisACLOnNodes(client, "SAVE")
+}
+
+// RedisACLLoad reload the ACLs from the acl file by ACL LOAD on every node
+func RedisACLLoad(client RedisClient) error {
+	return redisACLOnNodes(client, "LOAD")
+}
+
+func redisACLOnNodes(client RedisClient, args ...interface{}) error {
+	return ForEachNode(client, func(addr string, node RedisClient) error {
+		if err := node.Do(append([]interface{}{"ACL"}, args...)...).Err(); err != nil {
+			return fmt.Errorf("%s: %s", addr, err)
+		}
+
+		return nil
+	})
+}
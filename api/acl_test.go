@@ -0,0 +1,132 @@
+package api_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestParseACLList(t *testing.T) {
+	users := api.ParseACLList([]string{
+		"user default on nopass ~* &* +@all",
+		"user reader off #5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8 ~cache:* resetchannels -@all +@read",
+		"invalid line",
+	})
+
+	if len(users) != 2 {
+		t.Fatalf("test failed: %+v", users)
+	}
+
+	if users[0].Name != "default" || users[0].Rules != "on nopass ~* &* +@all" || !users[0].Enabled() {
+		t.Errorf("test failed: %+v", users[0])
+	}
+
+	if users[1].Name != "reader" || users[1].Enabled() {
+		t.Errorf("test failed: %+v", users[1])
+	}
+}
+
+func TestSplitACLRules(t *testing.T) {
+	testcases := map[string]string{
+		"on nopass ~* +@all":                   "on|nopass|~*|+@all",
+		"on ~* (~key* +get) (%R~read* +@read)": "on|~*|(~key* +get)|(%R~read* +@read)",
+		"on (+get)  ~cache:*":                  "on|(+get)|~cache:*",
+		"on (~key* +get":                       "on|(~key* +get",
+		"":                                     "",
+	}
+
+	for rules, expect := range testcases {
+		if got := strings.Join(api.SplitACLRules(rules), "|"); got != expect {
+			t.Errorf("test failed: %q, expect %s, got %s", rules, expect, got)
+		}
+	}
+
+	// rules in selectors are not changed
+	if rules := api.ToggleACLCategory("on (~key* +@read)", "read"); rules != "on (~key* +@read) +@read" {
+		t.Errorf("test failed: %s", rules)
+	}
+}
+
+func TestParseACLGetUser(t *testing.T) {
+	reply := []interface{}{
+		"flags", []interface{}{"on", "allkeys"},
+		"passwords", []interface{}{},
+		"commands", "+@all",
+		"keys", "~*",
+		"channels", "&*",
+		"selectors", []interface{}{
+			[]interface{}{"commands", "-@all +get", "keys", "~key*", "channels", ""},
+		},
+	}
+
+	fields := api.ParseACLGetUser(reply)
+	if len(fields) != 6 {
+		t.Fatalf("test failed: %+v", fields)
+	}
+
+	expects := []api.ACLField{
+		{Name: "flags", Value: "on allkeys"},
+		{Name: "passwords", Value: ""},
+		{Name: "commands", Value: "+@all"},
+		{Name: "keys", Value: "~*"},
+		{Name: "channels", Value: "&*"},
+		{Name: "selectors", Value: "(-@all +get ~key* )"},
+	}
+	for i, expect := range expects {
+		if fields[i] != expect {
+			t.Errorf("test failed: expect %+v, got %+v", expect, fields[i])
+		}
+	}
+}
+
+func TestParseACLLog(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{
+			"count", int64(2),
+			"reason", "command",
+			"context", "toplevel",
+			"object", "set",
+			"username", "reader",
+			"age-seconds", "4.096",
+			"client-info", "id=3 addr=127.0.0.1:52555",
+		},
+	}
+
+	entries := api.ParseACLLog(reply)
+	if len(entries) != 1 {
+		t.Fatalf("test failed: %+v", entries)
+	}
+
+	e := entries[0]
+	if e.Count != 2 || e.Reason != "command" || e.Object != "set" || e.Username != "reader" || e.AgeSeconds != 4.096 {
+		t.Errorf("test failed: %+v", e)
+	}
+}
+
+func TestToggleACLCategory(t *testing.T) {
+	rules := "on ~*"
+
+	rules = api.ToggleACLCategory(rules, "read")
+	if rules != "on ~* +@read" || api.ACLCategoryRule(rules, "read") != "+" {
+		t.Errorf("test failed: %s", rules)
+	}
+
+	rules = api.ToggleACLCategory(rules, "read")
+	if rules != "on ~* -@read" || api.ACLCategoryRule(rules, "read") != "-" {
+		t.Errorf("test failed: %s", rules)
+	}
+
+	rules = api.ToggleACLCategory(rules, "read")
+	if rules != "on ~*" || api.ACLCategoryRule(rules, "read") != "" {
+		t.Errorf("test failed: %s", rules)
+	}
+
+	if rules := api.AddACLKeyPattern("on", "cache:*"); rules != "on ~cache:*" {
+		t.Errorf("test failed: %s", rules)
+	}
+
+	if rules := api.AddACLKeyPattern("on ~cache:*", "~cache:*"); rules != "on ~cache:*" {
+		t.Errorf("test failed: %s", rules)
+	}
+}
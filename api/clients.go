@@ -0,0 +1,143 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ClientInfo is a connection in the CLIENT LIST output
+type ClientInfo struct {
+	Node   string
+	ID     int64
+	Addr   string
+	Name   string
+	Age    time.Duration
+	Idle   time.Duration
+	DB     int
+	Cmd    string
+	Flags  string
+	Memory int64
+	Fields map[string]string
+}
+
+// ParseClientList parse the output of CLIENT LIST
+func ParseClientList(node string, text string) []ClientInfo {
+	clients := make([]ClientInfo, 0)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fields := make(map[string]string)
+		for _, kv := range strings.Split(line, " ") {
+			pair := strings.SplitN(kv, "=", 2)
+			if len(pair) == 2 {
+				fields[pair[0]] = pair[1]
+			}
+		}
+
+		number := func(key string) int64 {
+			v, _ := strconv.ParseInt(fields[key], 10, 64)
+			return v
+		}
+
+		// tot-mem is available since redis 6.0, use the buffers for the older versions
+		memory := number("tot-mem")
+		if _, ok := fields["tot-mem"]; !ok {
+			memory = number("qbuf") + number("obl") + number("omem")
+		}
+
+		clients = append(clients, ClientInfo{
+			Node:   node,
+			ID:     number("id"),
+			Addr:   fields["addr"],
+			Name:   fields["name"],
+			Age:    time.Duration(number("age")) * time.Second,
+			Idle:   time.Duration(number("idle")) * time.Second,
+			DB:     int(number("db")),
+			Cmd:    fields["cmd"],
+			Flags:  fields["flags"],
+			Memory: memory,
+			Fields: fields,
+		})
+	}
+
+	return clients
+}
+
+// RedisClientList return the connections of the server, connections of all masters are merged in cluster mode
+func RedisClientList(client RedisClient) ([]ClientInfo, error) {
+	var lock sync.Mutex
+	clients := make([]ClientInfo, 0)
+	err := ForEachMaster(client, func(addr string, node RedisClient) error {
+		res, err := node.Do("CLIENT", "LIST").String()
+		if err != nil {
+			return err
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		clients = append(clients, ParseClientList(addr, res)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(clients, func(i, j int) bool {
+		if clients[i].Node != clients[j].Node {
+			return clients[i].Node < clients[j].Node
+		}
+
+		return clients[i].ID < clients[j].ID
+	})
+
+	return clients, nil
+}
+
+// RedisClientKill close the connection by CLIENT KILL ID on the node it belongs to
+func RedisClientKill(client RedisClient, c ClientInfo) error {
+	found := false
+	err := ForEachMaster(client, func(addr string, node RedisClient) error {
+		if addr != c.Node {
+			return nil
+		}
+
+		found = true
+		return node.Do("CLIENT", "KILL", "ID", c.ID).Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("node %s not found", c.Node)
+	}
+
+	return nil
+}
+
+// RedisClientPause suspend all the clients for the duration, only write commands are suspended when writeOnly is true
+func RedisClientPause(client RedisClient, duration time.Duration, writeOnly bool) error {
+	args := []interface{}{"CLIENT", "PAUSE", int64(duration / time.Millisecond)}
+	if writeOnly {
+		args = append(args, "WRITE")
+	}
+
+	return ForEachMaster(client, func(addr string, node RedisClient) error {
+		return node.Do(args...).Err()
+	})
+}
+
+// RedisClientUnpause resume the clients paused by CLIENT PAUSE
+func RedisClientUnpause(client RedisClient) error {
+	return ForEachMaster(client, func(addr string, node RedisClient) error {
+		return node.Do("CLIENT", "UNPAUSE").Err()
+	})
+}
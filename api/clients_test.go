@@ -0,0 +1,27 @@
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestParseClientList(t *testing.T) {
+	text := "id=3 addr=127.0.0.1:52555 laddr=127.0.0.1:6379 fd=8 name=worker age=855 idle=0 flags=N db=2 sub=0 psub=0 multi=-1 qbuf=26 qbuf-free=32742 obl=0 oll=0 omem=0 tot-mem=61466 events=r cmd=client|list user=default\n" +
+		"id=4 addr=127.0.0.1:52556 fd=9 name= age=10 idle=9 flags=N db=0 sub=0 psub=0 multi=-1 qbuf=10 qbuf-free=32742 obl=5 oll=0 omem=20 events=r cmd=get\n"
+
+	clients := api.ParseClientList("127.0.0.1:6379", text)
+	if len(clients) != 2 {
+		t.Fatalf("test failed: %v", clients)
+	}
+
+	c := clients[0]
+	if c.ID != 3 || c.Name != "worker" || c.DB != 2 || c.Age != 855*time.Second || c.Memory != 61466 || c.Cmd != "client|list" {
+		t.Errorf("test failed: %v", c)
+	}
+
+	if clients[1].Memory != 35 || clients[1].Idle != 9*time.Second {
+		t.Errorf("test failed: %v", clients[1])
+	}
+}
@@ -0,0 +1,335 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ClusterSlots is the count of hash slots in redis cluster
+const ClusterSlots = 16384
+
+var crc16Table = func() [256]uint16 {
+	var table [256]uint16
+	for i := range table {
+		crc := uint16(i) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+
+	return table
+}()
+
+// crc16 is the CRC16-CCITT (XMODEM) checksum used by redis cluster
+func crc16(data string) uint16 {
+	var crc uint16
+	for i := 0; i < len(data); i++ {
+		crc = crc<<8 ^ crc16Table[byte(crc>>8)^data[i]]
+	}
+
+	return crc
+}
+
+// HashTag return the hash tag of the key, the whole key is returned if there is no hash tag
+func HashTag(key string) string {
+	begin := strings.Index(key, "{")
+	if begin < 0 {
+		return key
+	}
+
+	end := strings.Index(key[begin+1:], "}")
+	if end <= 0 {
+		return key
+	}
+
+	return key[begin+1 : begin+1+end]
+}
+
+// KeySlot return the hash slot of the key
+func KeySlot(key string) int {
+	return int(crc16(HashTag(key))) % ClusterSlots
+}
+
+// SlotRange is a range of hash slots served by a node
+type SlotRange struct {
+	Start int
+	End   int
+	Addr  string
+}
+
+// RedisClusterSlots return the slot ranges of masters by CLUSTER SLOTS
+func RedisClusterSlots(client RedisClient) ([]SlotRange, error) {
+	res, err := client.Do("CLUSTER", "SLOTS").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	items, ok := res.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected cluster slots reply: %v", res)
+	}
+
+	ranges := make([]SlotRange, 0, len(items))
+	for _, item := range items {
+		fields, ok := item.([]interface{})
+		if !ok || len(fields) < 3 {
+			continue
+		}
+
+		master, ok := fields[2].([]interface{})
+		if !ok || len(master) < 2 {
+			continue
+		}
+
+		ranges = append(ranges, SlotRange{
+			Start: int(toInt64(fields[0])),
+			End:   int(toInt64(fields[1])),
+			Addr:  fmt.Sprintf("%v:%v", master[0], master[1]),
+		})
+	}
+
+	return ranges, nil
+}
+
+// RedisSlotAddr return the address of master serving the slot
+func RedisSlotAddr(client RedisClient, slot int) (string, error) {
+	ranges, err := RedisClusterSlots(client)
+	if err != nil {
+		return "", err
+	}
+
+	addr, ok := SlotAddr(ranges, slot)
+	if !ok {
+		return "", fmt.Errorf("slot %d is not served by any node", slot)
+	}
+
+	return addr, nil
+}
+
+// SlotAddr find the address of master serving the slot in slot ranges
+func SlotAddr(ranges []SlotRange, slot int) (string, bool) {
+	for _, r := range ranges {
+		if slot >= r.Start && slot <= r.End {
+			return r.Addr, true
+		}
+	}
+
+	return "", false
+}
+
+// ClusterNode is a node in the output of CLUSTER NODES
+type ClusterNode struct {
+	ID          string
+	Addr        string
+	Hostname    string
+	Flags       []string
+	MasterID    string
+	PingSent    int64
+	PongRecv    int64
+	ConfigEpoch int64
+	LinkState   string
+	Slots       []SlotRange
+	// Migrating maps the slots migrating from this node to the target node id
+	Migrating map[int]string
+	// Importing maps the slots importing into this node to the source node id
+	Importing map[int]string
+
+	// Health and ReplicationOffset are only available with CLUSTER SHARDS (redis 7.0+)
+	Health            string
+	ReplicationOffset int64
+}
+
+// HasFlag check whether the node has the flag
+func (n ClusterNode) HasFlag(flag string) bool {
+	for _, f := range n.Flags {
+		if f == flag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsMaster check whether the node is a master
+func (n ClusterNode) IsMaster() bool {
+	return n.HasFlag("master")
+}
+
+// Role return master or replica
+func (n ClusterNode) Role() string {
+	if n.IsMaster() {
+		return "master"
+	}
+
+	return "replica"
+}
+
+// Failing check whether the node is failing or possibly failing
+func (n ClusterNode) Failing() bool {
+	return n.HasFlag("fail") || n.HasFlag("fail?") || n.LinkState == "disconnected"
+}
+
+// SlotCount return the count of slots served by the node
+func (n ClusterNode) SlotCount() int {
+	count := 0
+	for _, r := range n.Slots {
+		count += r.End - r.Start + 1
+	}
+
+	return count
+}
+
+// SlotRanges format the slot ranges, such as 0-5460,6000
+func (n ClusterNode) SlotRanges() string {
+	ranges := make([]string, len(n.Slots))
+	for i, r := range n.Slots {
+		if r.Start == r.End {
+			ranges[i] = strconv.Itoa(r.Start)
+		} else {
+			ranges[i] = fmt.Sprintf("%d-%d", r.Start, r.End)
+		}
+	}
+
+	return strings.Join(ranges, ",")
+}
+
+// ParseClusterNodes parse the output of CLUSTER NODES
+func ParseClusterNodes(text string) ([]ClusterNode, error) {
+	nodes := make([]ClusterNode, 0)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 8 {
+			return nil, fmt.Errorf("invalid cluster node: %s", line)
+		}
+
+		node := ClusterNode{
+			ID:        fields[0],
+			Flags:     strings.Split(fields[2], ","),
+			MasterID:  strings.Trim(fields[3], "-"),
+			LinkState: fields[7],
+			Migrating: make(map[int]string),
+			Importing: make(map[int]string),
+		}
+
+		// address is formatted as ip:port@cport[,hostname]
+		addr := strings.SplitN(fields[1], ",", 2)
+		if len(addr) == 2 {
+			node.Hostname = addr[1]
+		}
+		node.Addr = strings.SplitN(addr[0], "@", 2)[0]
+
+		node.PingSent, _ = strconv.ParseInt(fields[4], 10, 64)
+		node.PongRecv, _ = strconv.ParseInt(fields[5], 10, 64)
+		node.ConfigEpoch, _ = strconv.ParseInt(fields[6], 10, 64)
+
+		for _, slot := range fields[8:] {
+			if strings.HasPrefix(slot, "[") {
+				// migrating slots are formatted as [slot->-target], importing slots as [slot-<-source]
+				slot = strings.Trim(slot, "[]")
+				if pair := strings.SplitN(slot, "->-", 2); len(pair) == 2 {
+					s, _ := strconv.Atoi(pair[0])
+					node.Migrating[s] = pair[1]
+				} else if pair := strings.SplitN(slot, "-<-", 2); len(pair) == 2 {
+					s, _ := strconv.Atoi(pair[0])
+					node.Importing[s] = pair[1]
+				}
+				continue
+			}
+
+			pair := strings.SplitN(slot, "-", 2)
+			start, err := strconv.Atoi(pair[0])
+			if err != nil {
+				continue
+			}
+
+			end := start
+			if len(pair) == 2 {
+				end, _ = strconv.Atoi(pair[1])
+			}
+
+			node.Slots = append(node.Slots, SlotRange{Start: start, End: end, Addr: node.Addr})
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Addr < nodes[j].Addr
+	})
+
+	return nodes, nil
+}
+
+// RedisClusterNodes return the nodes of cluster by CLUSTER NODES, the health and replication offset
+// of nodes are filled by CLUSTER SHARDS if the server supports it
+func RedisClusterNodes(client RedisClient) ([]ClusterNode, error) {
+	res, err := client.Do("CLUSTER", "NODES").String()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := ParseClusterNodes(res)
+	if err != nil {
+		return nil, err
+	}
+
+	shards, err := client.Do("CLUSTER", "SHARDS").Result()
+	if err != nil {
+		// CLUSTER SHARDS is only supported since redis 7.0
+		return nodes, nil
+	}
+
+	shardNodes := parseClusterShardNodes(shards)
+	for i := range nodes {
+		if n, ok := shardNodes[nodes[i].ID]; ok {
+			nodes[i].Health = n["health"]
+			nodes[i].ReplicationOffset, _ = strconv.ParseInt(n["replication-offset"], 10, 64)
+		}
+	}
+
+	return nodes, nil
+}
+
+// parseClusterShardNodes parse the reply of CLUSTER SHARDS, nodes are returned as maps index by node id
+func parseClusterShardNodes(reply interface{}) map[string]map[string]string {
+	nodes := make(map[string]map[string]string)
+
+	shards, _ := reply.([]interface{})
+	for _, shard := range shards {
+		shardFields := replyMap(shard)
+		shardNodes, _ := shardFields["nodes"].([]interface{})
+		for _, n := range shardNodes {
+			fields := make(map[string]string)
+			for k, v := range replyMap(n) {
+				fields[k] = fmt.Sprint(v)
+			}
+
+			nodes[fields["id"]] = fields
+		}
+	}
+
+	return nodes
+}
+
+// replyMap convert a reply formatted as [k1, v1, k2, v2] into a map
+func replyMap(reply interface{}) map[string]interface{} {
+	items, _ := reply.([]interface{})
+	m := make(map[string]interface{}, len(items)/2)
+	for i := 0; i+1 < len(items); i += 2 {
+		m[fmt.Sprint(items[i])] = items[i+1]
+	}
+
+	return m
+}
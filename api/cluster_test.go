@@ -0,0 +1,66 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestKeySlot(t *testing.T) {
+	slots := map[string]int{
+		"foo":       12182,
+		"bar":       5061,
+		"123456789": 12739,
+	}
+
+	for key, slot := range slots {
+		if s := api.KeySlot(key); s != slot {
+			t.Errorf("test failed: key %s, expect %d, got %d", key, slot, s)
+		}
+	}
+
+	if api.KeySlot("{user1000}.following") != api.KeySlot("{user1000}.followers") {
+		t.Error("test failed: keys with the same hash tag should be in the same slot")
+	}
+
+	if api.HashTag("foo{}{bar}") != "foo{}{bar}" || api.HashTag("foo{{bar}}") != "{bar" {
+		t.Error("test failed")
+	}
+}
+
+func TestParseClusterNodes(t *testing.T) {
+	text := `07c37dfeb235213a872192d90877d0cd55635b91 127.0.0.1:30004@31004,host4 slave e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 0 1426238317239 4 connected
+67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1 127.0.0.1:30002@31002 master - 0 1426238316232 2 connected 5461-10922 [5460-<-e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca]
+e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 127.0.0.1:30001@31001 myself,master - 0 0 1 connected 0-5460 [5460->-67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1]
+6ec23923021cf3ffec47632106199cb7f496ce01 127.0.0.1:30005@31005 master,fail - 1426238316232 0 5 disconnected 10923-16383 16380
+`
+
+	nodes, err := api.ParseClusterNodes(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(nodes) != 4 {
+		t.Fatalf("test failed: expect 4 nodes, got %d", len(nodes))
+	}
+
+	if nodes[0].Addr != "127.0.0.1:30001" || !nodes[0].IsMaster() || !nodes[0].HasFlag("myself") || nodes[0].SlotCount() != 5461 {
+		t.Errorf("test failed: %+v", nodes[0])
+	}
+
+	if nodes[0].Migrating[5460] != "67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1" || nodes[1].Importing[5460] != "e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca" {
+		t.Errorf("test failed: migrating %v, importing %v", nodes[0].Migrating, nodes[1].Importing)
+	}
+
+	if nodes[2].Role() != "replica" || nodes[2].Hostname != "host4" || nodes[2].MasterID != "e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca" || nodes[2].ConfigEpoch != 4 {
+		t.Errorf("test failed: %+v", nodes[2])
+	}
+
+	if !nodes[3].Failing() || nodes[3].SlotRanges() != "10923-16383,16380" {
+		t.Errorf("test failed: %+v", nodes[3])
+	}
+
+	if _, err := api.ParseClusterNodes("invalid line"); err == nil {
+		t.Error("test failed: expect error for invalid line")
+	}
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ConfigType is the type of value of a config parameter
+type ConfigType string
+
+// Types of config parameters
+const (
+	ConfigTypeString  ConfigType = "string"
+	ConfigTypeBool    ConfigType = "bool"
+	ConfigTypeInteger ConfigType = "integer"
+	ConfigTypeMemory  ConfigType = "memory"
+	ConfigTypeEnum    ConfigType = "enum"
+)
+
+// ConfigSpec is the type and default value of a config parameter
+type ConfigSpec struct {
+	Type    ConfigType
+	Default string
+	Options []string
+}
+
+// configSpecs are the specs of parameters frequently tuned, the defaults are the ones of redis 7.0
+var configSpecs = map[string]ConfigSpec{
+	"maxmemory":                     {Type: ConfigTypeMemory, Default: "0"},
+	"maxmemory-policy":              {Type: ConfigTypeEnum, Default: "noeviction", Options: []string{"volatile-lru", "allkeys-lru", "volatile-lfu", "allkeys-lfu", "volatile-random", "allkeys-random", "volatile-ttl", "noeviction"}},
+	"maxmemory-samples":             {Type: ConfigTypeInteger, Default: "5"},
+	"maxclients":                    {Type: ConfigTypeInteger, Default: "10000"},
+	"timeout":                       {Type: ConfigTypeInteger, Default: "0"},
+	"tcp-keepalive":                 {Type: ConfigTypeInteger, Default: "300"},
+	"slowlog-log-slower-than":       {Type: ConfigTypeInteger, Default: "10000"},
+	"slowlog-max-len":               {Type: ConfigTypeInteger, Default: "128"},
+	"latency-monitor-threshold":     {Type: ConfigTypeInteger, Default: "0"},
+	"notify-keyspace-events":        {Type: ConfigTypeString, Default: ""},
+	"appendonly":                    {Type: ConfigTypeBool, Default: "no"},
+	"appendfsync":                   {Type: ConfigTypeEnum, Default: "everysec", Options: []string{"always", "everysec", "no"}},
+	"save":                          {Type: ConfigTypeString, Default: "3600 1 300 100 60 10000"},
+	"stop-writes-on-bgsave-error":   {Type: ConfigTypeBool, Default: "yes"},
+	"rdbcompression":                {Type: ConfigTypeBool, Default: "yes"},
+	"hz":                            {Type: ConfigTypeInteger, Default: "10"},
+	"dynamic-hz":                    {Type: ConfigTypeBool, Default: "yes"},
+	"loglevel":                      {Type: ConfigTypeEnum, Default: "notice", Options: []string{"debug", "verbose", "notice", "warning"}},
+	"protected-mode":                {Type: ConfigTypeBool, Default: "yes"},
+	"lazyfree-lazy-eviction":        {Type: ConfigTypeBool, Default: "no"},
+	"lazyfree-lazy-expire":          {Type: ConfigTypeBool, Default: "no"},
+	"lazyfree-lazy-server-del":      {Type: ConfigTypeBool, Default: "no"},
+	"lazyfree-lazy-user-del":        {Type: ConfigTypeBool, Default: "no"},
+	"activedefrag":                  {Type: ConfigTypeBool, Default: "no"},
+	"active-expire-effort":          {Type: ConfigTypeInteger, Default: "1"},
+	"lfu-log-factor":                {Type: ConfigTypeInteger, Default: "10"},
+	"lfu-decay-time":                {Type: ConfigTypeInteger, Default: "1"},
+	"hash-max-listpack-entries":     {Type: ConfigTypeInteger, Default: "128"},
+	"hash-max-listpack-value":       {Type: ConfigTypeInteger, Default: "64"},
+	"list-max-listpack-size":        {Type: ConfigTypeInteger, Default: "-2"},
+	"set-max-intset-entries":        {Type: ConfigTypeInteger, Default: "512"},
+	"zset-max-listpack-entries":     {Type: ConfigTypeInteger, Default: "128"},
+	"zset-max-listpack-value":       {Type: ConfigTypeInteger, Default: "64"},
+	"repl-backlog-size":             {Type: ConfigTypeMemory, Default: "1048576"},
+	"repl-timeout":                  {Type: ConfigTypeInteger, Default: "60"},
+	"repl-diskless-sync":            {Type: ConfigTypeBool, Default: "yes"},
+	"min-replicas-to-write":         {Type: ConfigTypeInteger, Default: "0"},
+	"min-replicas-max-lag":          {Type: ConfigTypeInteger, Default: "10"},
+	"lua-time-limit":                {Type: ConfigTypeInteger, Default: "5000"},
+	"busy-reply-threshold":          {Type: ConfigTypeInteger, Default: "5000"},
+	"proto-max-bulk-len":            {Type: ConfigTypeMemory, Default: "536870912"},
+	"client-query-buffer-limit":     {Type: ConfigTypeMemory, Default: "1073741824"},
+	"cluster-node-timeout":          {Type: ConfigTypeInteger, Default: "15000"},
+	"cluster-require-full-coverage": {Type: ConfigTypeBool, Default: "yes"},
+}
+
+// ConfigParameter is a config parameter returned by CONFIG GET
+type ConfigParameter struct {
+	Name  string
+	Value string
+	Spec  ConfigSpec
+	// Known is false if the parameter has no spec, it's treated as a string without default
+	Known bool
+}
+
+// Modified check whether the value differs from the default
+func (p ConfigParameter) Modified() bool {
+	return p.Known && p.Value != p.Spec.Default
+}
+
+// LookupConfigSpec return the spec of the parameter
+func LookupConfigSpec(name string) (ConfigSpec, bool) {
+	spec, ok := configSpecs[strings.ToLower(name)]
+	if !ok {
+		return ConfigSpec{Type: ConfigTypeString}, false
+	}
+
+	return spec, true
+}
+
+var memoryPattern = regexp.MustCompile(`(?i)^\d+(k|kb|m|mb|g|gb)?$`)
+
+// ValidateConfigValue check whether the value matches the type of the parameter
+func ValidateConfigValue(name string, value string) error {
+	spec, _ := LookupConfigSpec(name)
+	switch spec.Type {
+	case ConfigTypeBool:
+		if value != "yes" && value != "no" {
+			return fmt.Errorf("%s must be yes or no", name)
+		}
+	case ConfigTypeInteger:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("%s must be an integer", name)
+		}
+	case ConfigTypeMemory:
+		if !memoryPattern.MatchString(value) {
+			return fmt.Errorf("%s must be a size in bytes, such as 1024, 100mb or 2gb", name)
+		}
+	case ConfigTypeEnum:
+		for _, o := range spec.Options {
+			if value == o {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%s must be one of %s", name, strings.Join(spec.Options, ", "))
+	}
+
+	return nil
+}
+
+// RedisConfigs return all the config parameters by CONFIG GET *, sorted by name, and the address of
+// the node they are read from. In cluster mode they are read from the master with the smallest address,
+// so that the values don't change between refreshes, other nodes may have different values
+func RedisConfigs(client RedisClient) ([]ConfigParameter, string, error) {
+	var lock sync.Mutex
+	node := ""
+	if err := ForEachMaster(client, func(addr string, _ RedisClient) error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if node == "" || addr < node {
+			node = addr
+		}
+		return nil
+	}); err != nil {
+		return nil, "", err
+	}
+
+	var res interface{}
+	if err := RedisNode(client, node, func(c RedisClient) (err error) {
+		res, err = c.Do("CONFIG", "GET", "*").Result()
+		return err
+	}); err != nil {
+		return nil, node, err
+	}
+
+	values := replyMap(res)
+	params := make([]ConfigParameter, 0, len(values))
+	for name, value := range values {
+		spec, known := LookupConfigSpec(name)
+		params = append(params, ConfigParameter{Name: name, Value: fmt.Sprint(value), Spec: spec, Known: known})
+	}
+
+	sort.Slice(params, func(i, j int) bool {
+		return params[i].Name < params[j].Name
+	})
+
+	return params, node, nil
+}
+
+// RedisConfigSet update a config parameter, it's applied to every node in cluster mode
+func RedisConfigSet(client RedisClient, name string, value string) error {
+	if err := ValidateConfigValue(name, value); err != nil {
+		return err
+	}
+
+	return ForEachNode(client, func(addr string, node RedisClient) error {
+		if err := node.Do("CONFIG", "SET", name, value).Err(); err != nil {
+			return fmt.Errorf("%s: %s", addr, err)
+		}
+
+		return nil
+	})
+}
+
+// RedisConfigRewrite rewrite the config file with current config, it's applied to every node in cluster mode,
+// replicas included, every node rewrites its own file with its own config
+func RedisConfigRewrite(client RedisClient) error {
+	return ForEachNode(client, func(addr string, node RedisClient) error {
+		if err := node.Do("CONFIG", "REWRITE").Err(); err != nil {
+			return fmt.Errorf("%s: %s", addr, err)
+		}
+
+		return nil
+	})
+}
+
+// RedisConfigResetStat reset the statistics reported by INFO, it's applied to every node in cluster mode
+func RedisConfigResetStat(client RedisClient) error {
+	return ForEachNode(client, func(addr string, node RedisClient) error {
+		if err := node.Do("CONFIG", "RESETSTAT").Err(); err != nil {
+			return fmt.Errorf("%s: %s", addr, err)
+		}
+
+		return nil
+	})
+}
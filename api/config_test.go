@@ -0,0 +1,39 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestValidateConfigValue(t *testing.T) {
+	valid := map[string]string{
+		"maxmemory":               "100mb",
+		"maxmemory-policy":        "allkeys-lru",
+		"appendonly":              "yes",
+		"slowlog-log-slower-than": "-1",
+		"notify-keyspace-events":  "KEA",
+		"unknown-parameter":       "anything",
+	}
+	for name, value := range valid {
+		if err := api.ValidateConfigValue(name, value); err != nil {
+			t.Errorf("test failed: %s %s: %s", name, value, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"maxmemory":               "100 mb",
+		"maxmemory-policy":        "lru",
+		"appendonly":              "true",
+		"slowlog-log-slower-than": "1.5",
+	}
+	for name, value := range invalid {
+		if err := api.ValidateConfigValue(name, value); err == nil {
+			t.Errorf("test failed: %s %s should be invalid", name, value)
+		}
+	}
+
+	if !(api.ConfigParameter{Name: "hz", Value: "100", Spec: api.ConfigSpec{Default: "10"}, Known: true}).Modified() {
+		t.Error("test failed: hz should be modified")
+	}
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mylxsw/redis-tui/config"
+)
+
+const (
+	// rawConnTimeout is the timeout of dialing, sending a command and waiting for its reply
+	rawConnTimeout = 5 * time.Second
+	// rawConnPingInterval is the interval of PING sent on streaming connections, see RawConn.Stream
+	rawConnPingInterval = 15 * time.Second
+)
+
+// RedisError is an error reply from server
+type RedisError string
+
+func (e RedisError) Error() string {
+	return string(e)
+}
+
+// RawConn is a dedicated connection speaking RESP, it's used for the commands which keep
+// the connection busy and are not supported by go-redis, such as MONITOR and SSUBSCRIBE
+type RawConn struct {
+	Addr string
+
+	conn   net.Conn
+	reader *bufio.Reader
+	// lock protects writer, commands are sent by PING of streaming connections and subscribers concurrently
+	lock   sync.Mutex
+	writer *bufio.Writer
+
+	// streaming is true after Stream is called, replies are pushed by server without requests then
+	streaming bool
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// DialRawConn create a connection to addr, AUTH and SELECT are sent according to conf
+func DialRawConn(addr string, conf config.Config) (*RawConn, error) {
+	conn, err := net.DialTimeout("tcp", addr, rawConnTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &RawConn{
+		Addr:   addr,
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		writer: bufio.NewWriter(conn),
+		done:   make(chan struct{}),
+	}
+
+	if conf.Username != "" {
+		if _, err := c.Do("AUTH", conf.Username, conf.Password); err != nil {
+			c.Close()
+			return nil, err
+		}
+	} else if conf.Password != "" {
+		if _, err := c.Do("AUTH", conf.Password); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+
+	if conf.DB != 0 && !conf.Cluster {
+		if _, err := c.Do("SELECT", conf.DB); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// Do send a command and wait for its reply
+func (c *RawConn) Do(args ...interface{}) (interface{}, error) {
+	if err := c.Send(args...); err != nil {
+		return nil, err
+	}
+
+	return c.Receive()
+}
+
+// Send write a command to server without waiting for reply
+func (c *RawConn) Send(args ...interface{}) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.conn.SetWriteDeadline(time.Now().Add(rawConnTimeout)); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.writer, "*%d\r\n", len(args))
+	for _, arg := range args {
+		var s string
+		switch v := arg.(type) {
+		case string:
+			s = v
+		case []byte:
+			s = string(v)
+		default:
+			s = fmt.Sprint(v)
+		}
+
+		fmt.Fprintf(c.writer, "$%d\r\n%s\r\n", len(s), s)
+	}
+
+	return c.writer.Flush()
+}
+
+// Receive read a reply from server, error replies are returned as RedisError. It fails if the reply
+// is not received in rawConnTimeout, or nothing is received in 2 PING intervals for streaming connections
+func (c *RawConn) Receive() (interface{}, error) {
+	timeout := rawConnTimeout
+	if c.streaming {
+		timeout = 2 * rawConnPingInterval
+	}
+
+	if err := c.conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	reply, err := readReply(c.reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if e, ok := reply.(RedisError); ok {
+		return nil, e
+	}
+
+	return reply, nil
+}
+
+// Stream switch the connection to streaming mode after MONITOR or SUBSCRIBE is sent. The connection
+// may be idle for a long time, so PING is sent every rawConnPingInterval and Receive fails if nothing,
+// including the replies of PING, is received in 2 intervals. It's called before receiving replies
+func (c *RawConn) Stream() {
+	c.streaming = true
+
+	go func() {
+		ticker := time.NewTicker(rawConnPingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.done:
+				return
+			case <-ticker.C:
+				if err := c.Send("PING"); err != nil {
+					return
+				}
+			}
+		}
+	}()
+}
+
+// LocalAddr return the local address of the connection, such as 127.0.0.1:52555
+func (c *RawConn) LocalAddr() string {
+	return c.conn.LocalAddr().String()
+}
+
+// Close close the connection, it can be called more than once
+func (c *RawConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		err = c.conn.Close()
+	})
+
+	return err
+}
+
+// readReply read a RESP2 or RESP3 reply, bulk and simple strings are returned as string,
+// integers as int64, arrays, sets, maps and pushes as []interface{}
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) < 3 {
+		return nil, fmt.Errorf("invalid reply: %q", line)
+	}
+
+	line = line[:len(line)-2]
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-', '!':
+		return RedisError(line[1:]), nil
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case ',':
+		return strconv.ParseFloat(line[1:], 64)
+	case '#':
+		return line[1:] == "t", nil
+	case '_':
+		return nil, nil
+	case '$', '=':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		if size > importMaxBulkLength {
+			return nil, fmt.Errorf("invalid bulk length: %d", size)
+		}
+
+		// the buffer grows with the data read instead of the length from server
+		var buf bytes.Buffer
+		if _, err := io.CopyN(&buf, r, int64(size)+2); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+
+		return string(buf.Bytes()[:size]), nil
+	case '*', '~', '>', '%':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+
+		if line[0] == '%' {
+			size *= 2
+		}
+
+		// at most 1024 items are allocated in advance, the rest grows with the items read
+		capacity := size
+		if capacity > 1024 {
+			capacity = 1024
+		}
+
+		items := make([]interface{}, 0, capacity)
+		for i := 0; i < size; i++ {
+			item, err := readReply(r)
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, item)
+		}
+
+		return items, nil
+	}
+
+	return nil, fmt.Errorf("unsupported reply: %q", line)
+}
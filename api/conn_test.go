@@ -0,0 +1,85 @@
+package api_test
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/config"
+)
+
+// replyServer start a server which replies every command with the next reply, the connection is
+// closed after all replies are sent
+func replyServer(t *testing.T, replies ...string) (string, func()) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		for _, reply := range replies {
+			// every command is an array of bulk strings
+			var count int
+			if _, err := fmt.Fscanf(r, "*%d\r\n", &count); err != nil {
+				return
+			}
+			for i := 0; i < count*2; i++ {
+				if _, err := r.ReadString('\n'); err != nil {
+					return
+				}
+			}
+
+			if _, err := conn.Write([]byte(reply)); err != nil {
+				return
+			}
+		}
+	}()
+
+	return ln.Addr().String(), func() { ln.Close() }
+}
+
+func TestRawConnDo(t *testing.T) {
+	testcases := []struct {
+		reply  string
+		expect string
+		ok     bool
+	}{
+		{reply: "+OK\r\n", expect: "OK", ok: true},
+		{reply: "$5\r\nhello\r\n", expect: "hello", ok: true},
+		{reply: "*2\r\n:1\r\n$1\r\na\r\n", expect: "[1 a]", ok: true},
+		{reply: "-ERR unknown command\r\n", ok: false},
+		// lengths from server are not trusted
+		{reply: "$9999999999\r\n", ok: false},
+		{reply: "$1000000\r\nabc", ok: false},
+		{reply: "*2147483647\r\n:1\r\n", ok: false},
+	}
+
+	for _, tc := range testcases {
+		addr, stop := replyServer(t, tc.reply)
+
+		conn, err := api.DialRawConn(addr, config.Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := conn.Do("PING")
+		if tc.ok && (err != nil || fmt.Sprint(res) != tc.expect) {
+			t.Errorf("test failed: %q, expect %s, got %v, %v", tc.reply, tc.expect, res, err)
+		}
+		if !tc.ok && err == nil {
+			t.Errorf("test failed: %q should be invalid, got %v", tc.reply, res)
+		}
+
+		conn.Close()
+		stop()
+	}
+}
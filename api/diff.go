@@ -0,0 +1,309 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KeyValue is a snapshot of a key. Elements are keyed by field for hash, member for set and zset,
+// index for list and entry ID for stream, a string has a single element with empty name
+type KeyValue struct {
+	Key      string
+	Type     string
+	TTL      time.Duration
+	Elements map[string]string
+}
+
+// Exists check whether the key exists
+func (v KeyValue) Exists() bool {
+	return v.Type != "" && v.Type != "none"
+}
+
+// RedisKeyValue read the snapshot of a key, the type is "none" if the key doesn't exist
+func RedisKeyValue(client RedisClient, key string) (KeyValue, error) {
+	rec, exists, err := RedisKeyRecord(client, key)
+	if err != nil {
+		return KeyValue{Key: key, Elements: make(map[string]string)}, err
+	}
+	if !exists {
+		return KeyValue{Key: key, Type: "none", Elements: make(map[string]string)}, nil
+	}
+
+	return NewKeyValue(rec), nil
+}
+
+// NewKeyValue create the snapshot of a key from its record
+func NewKeyValue(rec KeyRecord) KeyValue {
+	v := KeyValue{Key: rec.Key, Type: rec.Type, TTL: time.Duration(rec.TTL) * time.Millisecond, Elements: make(map[string]string)}
+	if rec.TTL < 0 {
+		v.TTL = time.Duration(rec.TTL) * time.Second
+	}
+
+	switch value := rec.Value.(type) {
+	case string:
+		v.Elements[""] = value
+	case []string:
+		for i, s := range value {
+			if rec.Type == "set" {
+				v.Elements[s] = ""
+			} else {
+				v.Elements[strconv.Itoa(i)] = s
+			}
+		}
+	case []ZMember:
+		for _, m := range value {
+			v.Elements[m.Member] = strconv.FormatFloat(m.Score, 'f', -1, 64)
+		}
+	case map[string]string:
+		for field, s := range value {
+			v.Elements[field] = s
+		}
+	case []StreamEntry:
+		for _, e := range value {
+			pairs := make([]string, 0, len(e.Fields)/2)
+			for i := 0; i+1 < len(e.Fields); i += 2 {
+				pairs = append(pairs, fmt.Sprintf("%s=%s", e.Fields[i], e.Fields[i+1]))
+			}
+			v.Elements[e.ID] = strings.Join(pairs, " ")
+		}
+	}
+
+	return v
+}
+
+// DiffKind is a kind of difference between two snapshots of a key
+type DiffKind string
+
+// Kinds of differences between two snapshots of a key
+const (
+	DiffOnlyLeft  DiffKind = "only-left"
+	DiffOnlyRight DiffKind = "only-right"
+	DiffType      DiffKind = "type"
+	DiffTTL       DiffKind = "ttl"
+	DiffValue     DiffKind = "value"
+)
+
+// ElementDiff is a difference of an element, Left or Right is nil if the element is absent on that side
+type ElementDiff struct {
+	Element string
+	Left    *string
+	Right   *string
+}
+
+// KeyDiff is the result of comparing a key between two instances
+type KeyDiff struct {
+	Key      string
+	Left     KeyValue
+	Right    KeyValue
+	Kinds    []DiffKind
+	Elements []ElementDiff
+}
+
+// Equal check whether the key is the same on both sides
+func (d KeyDiff) Equal() bool {
+	return len(d.Kinds) == 0
+}
+
+// Has check whether the key has the kind of difference
+func (d KeyDiff) Has(kind DiffKind) bool {
+	for _, k := range d.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CompareKeyValues compare two snapshots of a key, TTLs differing no more than ttlTolerance are
+// considered equal, because time passes between the reading of the two sides
+func CompareKeyValues(left, right KeyValue, ttlTolerance time.Duration) KeyDiff {
+	d := KeyDiff{Key: left.Key, Left: left, Right: right}
+	if d.Key == "" {
+		d.Key = right.Key
+	}
+
+	switch {
+	case !left.Exists() && !right.Exists():
+		return d
+	case !right.Exists():
+		d.Kinds = append(d.Kinds, DiffOnlyLeft)
+		return d
+	case !left.Exists():
+		d.Kinds = append(d.Kinds, DiffOnlyRight)
+		return d
+	case left.Type != right.Type:
+		d.Kinds = append(d.Kinds, DiffType)
+		return d
+	}
+
+	if ttlDiffers(left.TTL, right.TTL, ttlTolerance) {
+		d.Kinds = append(d.Kinds, DiffTTL)
+	}
+
+	for element, l := range left.Elements {
+		l := l
+		if r, ok := right.Elements[element]; !ok {
+			d.Elements = append(d.Elements, ElementDiff{Element: element, Left: &l})
+		} else if r != l {
+			d.Elements = append(d.Elements, ElementDiff{Element: element, Left: &l, Right: &r})
+		}
+	}
+
+	for element, r := range right.Elements {
+		r := r
+		if _, ok := left.Elements[element]; !ok {
+			d.Elements = append(d.Elements, ElementDiff{Element: element, Right: &r})
+		}
+	}
+
+	if len(d.Elements) > 0 {
+		d.Kinds = append(d.Kinds, DiffValue)
+	}
+
+	sort.Slice(d.Elements, func(i, j int) bool {
+		a, b := d.Elements[i].Element, d.Elements[j].Element
+		// list indexes are sorted by number
+		if ai, err := strconv.Atoi(a); err == nil && left.Type == "list" {
+			if bi, err := strconv.Atoi(b); err == nil {
+				return ai < bi
+			}
+		}
+
+		return a < b
+	})
+
+	return d
+}
+
+// ttlDiffers check whether two TTLs are different, a key without expiration has negative TTL
+func ttlDiffers(left, right time.Duration, tolerance time.Duration) bool {
+	if left < 0 || right < 0 {
+		return (left < 0) != (right < 0)
+	}
+
+	diff := left - right
+	if diff < 0 {
+		diff = -diff
+	}
+
+	return diff > tolerance
+}
+
+// DiffSummary is the count of keys for each kind of differences, a key may be counted in both TTL and Value
+type DiffSummary struct {
+	Total     int
+	Equal     int
+	OnlyLeft  int
+	OnlyRight int
+	Type      int
+	TTL       int
+	Value     int
+}
+
+// String format the summary in a line
+func (s DiffSummary) String() string {
+	return fmt.Sprintf("total=%d, equal=%d, only-left=%d, only-right=%d, type=%d, ttl=%d, value=%d",
+		s.Total, s.Equal, s.OnlyLeft, s.OnlyRight, s.Type, s.TTL, s.Value)
+}
+
+// SummarizeKeyDiffs count the keys for each kind of differences
+func SummarizeKeyDiffs(diffs []KeyDiff) DiffSummary {
+	s := DiffSummary{Total: len(diffs)}
+	for _, d := range diffs {
+		if d.Equal() {
+			s.Equal++
+		}
+
+		for _, k := range d.Kinds {
+			switch k {
+			case DiffOnlyLeft:
+				s.OnlyLeft++
+			case DiffOnlyRight:
+				s.OnlyRight++
+			case DiffType:
+				s.Type++
+			case DiffTTL:
+				s.TTL++
+			case DiffValue:
+				s.Value++
+			}
+		}
+	}
+
+	return s
+}
+
+// KeyDiffOptions is the options of DiffKeys
+type KeyDiffOptions struct {
+	Pattern string
+	// TTLTolerance is the max difference of TTLs considered equal
+	TTLTolerance time.Duration
+	// Limit is the max count of keys compared, 0 means unlimited
+	Limit int
+}
+
+// DiffKeys compare the keys matching pattern between left and right. progress is called with the
+// count of keys compared, the comparing is stopped when stop is closed
+func DiffKeys(left, right RedisClient, opts KeyDiffOptions, progress func(compared, total int), stop <-chan struct{}) ([]KeyDiff, error) {
+	keys := make(map[string]bool)
+	for _, client := range []RedisClient{left, right} {
+		scanner := NewKeyScanner(client, opts.Pattern)
+		for !scanner.Done() {
+			select {
+			case <-stop:
+				return nil, errors.New("diff stopped")
+			default:
+			}
+
+			batch, err := scanner.Next(1000)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, k := range batch {
+				keys[k.Key] = true
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	if opts.Limit > 0 && len(sorted) > opts.Limit {
+		sorted = sorted[:opts.Limit]
+	}
+
+	diffs := make([]KeyDiff, 0, len(sorted))
+	for i, key := range sorted {
+		select {
+		case <-stop:
+			return diffs, errors.New("diff stopped")
+		default:
+		}
+
+		l, err := RedisKeyValue(left, key)
+		if err != nil {
+			return diffs, fmt.Errorf("%s: %s", key, err)
+		}
+
+		r, err := RedisKeyValue(right, key)
+		if err != nil {
+			return diffs, fmt.Errorf("%s: %s", key, err)
+		}
+
+		diffs = append(diffs, CompareKeyValues(l, r, opts.TTLTolerance))
+		if progress != nil && (i%100 == 0 || i == len(sorted)-1) {
+			progress(i+1, len(sorted))
+		}
+	}
+
+	return diffs, nil
+}
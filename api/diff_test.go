@@ -0,0 +1,80 @@
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestCompareKeyValues(t *testing.T) {
+	hash := func(ttl time.Duration, elements map[string]string) api.KeyValue {
+		return api.KeyValue{Key: "user:1", Type: "hash", TTL: ttl, Elements: elements}
+	}
+
+	d := api.CompareKeyValues(
+		hash(100*time.Second, map[string]string{"name": "tom", "age": "18"}),
+		hash(99*time.Second, map[string]string{"name": "tom", "age": "18"}),
+		2*time.Second,
+	)
+	if !d.Equal() {
+		t.Errorf("test failed: %+v", d.Kinds)
+	}
+
+	d = api.CompareKeyValues(
+		hash(-time.Second, map[string]string{"name": "tom", "age": "18", "city": "beijing"}),
+		hash(100*time.Second, map[string]string{"name": "jerry", "age": "18", "email": "jerry@example.com"}),
+		2*time.Second,
+	)
+	if !d.Has(api.DiffTTL) || !d.Has(api.DiffValue) || len(d.Elements) != 3 {
+		t.Fatalf("test failed: %+v", d)
+	}
+
+	// elements are sorted: city (only left), email (only right), name (changed)
+	if e := d.Elements[0]; e.Element != "city" || e.Left == nil || e.Right != nil {
+		t.Errorf("test failed: %+v", e)
+	}
+	if e := d.Elements[1]; e.Element != "email" || e.Left != nil || *e.Right != "jerry@example.com" {
+		t.Errorf("test failed: %+v", e)
+	}
+	if e := d.Elements[2]; e.Element != "name" || *e.Left != "tom" || *e.Right != "jerry" {
+		t.Errorf("test failed: %+v", e)
+	}
+
+	missing := api.KeyValue{Key: "user:1", Type: "none"}
+	if d := api.CompareKeyValues(hash(-time.Second, nil), missing, 0); !d.Has(api.DiffOnlyLeft) {
+		t.Errorf("test failed: %+v", d.Kinds)
+	}
+	if d := api.CompareKeyValues(missing, hash(-time.Second, nil), 0); !d.Has(api.DiffOnlyRight) || d.Key != "user:1" {
+		t.Errorf("test failed: %+v", d)
+	}
+
+	str := api.KeyValue{Key: "user:1", Type: "string", TTL: -time.Second, Elements: map[string]string{"": "tom"}}
+	if d := api.CompareKeyValues(hash(-time.Second, nil), str, 0); len(d.Kinds) != 1 || !d.Has(api.DiffType) {
+		t.Errorf("test failed: %+v", d.Kinds)
+	}
+
+	list := func(values ...string) api.KeyValue {
+		v := api.KeyValue{Key: "queue", Type: "list", TTL: -time.Second, Elements: map[string]string{}}
+		for i, value := range values {
+			v.Elements[string(rune('0'+i))] = value
+		}
+		return v
+	}
+	if d := api.CompareKeyValues(list("a", "b"), list("a", "c", "d"), 0); len(d.Elements) != 2 || d.Elements[0].Element != "1" {
+		t.Errorf("test failed: %+v", d.Elements)
+	}
+}
+
+func TestSummarizeKeyDiffs(t *testing.T) {
+	summary := api.SummarizeKeyDiffs([]api.KeyDiff{
+		{Key: "a"},
+		{Key: "b", Kinds: []api.DiffKind{api.DiffOnlyLeft}},
+		{Key: "c", Kinds: []api.DiffKind{api.DiffTTL, api.DiffValue}},
+	})
+
+	expect := api.DiffSummary{Total: 3, Equal: 1, OnlyLeft: 1, TTL: 1, Value: 1}
+	if summary != expect {
+		t.Errorf("test failed: %s", summary)
+	}
+}
@@ -0,0 +1,256 @@
+package api
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ExportFormat is the file format of exported keys
+type ExportFormat string
+
+// Formats of exported keys
+const (
+	// FormatJSONLines writes a KeyRecord in JSON per line
+	FormatJSONLines ExportFormat = "jsonl"
+	// FormatCSV writes a row per element in columns key, type, ttl, field, value and encoding,
+	// only flat types (string, list, set, zset and hash) are supported
+	FormatCSV ExportFormat = "csv"
+	// FormatCommands writes the redis commands creating the keys, which can be replayed by redis-cli
+	FormatCommands ExportFormat = "commands"
+)
+
+// FormatByPath decide the format of file by its extension, .jsonl (.json) and .csv are supported,
+// commands are written for other extensions
+func FormatByPath(path string) ExportFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".json":
+		return FormatJSONLines
+	case ".csv":
+		return FormatCSV
+	}
+
+	return FormatCommands
+}
+
+// csvHeader is the header of exported csv files
+var csvHeader = []string{"key", "type", "ttl", "field", "value", "encoding"}
+
+// QuoteArg quote an argument of command like redis-cli, arguments with spaces, quotes or
+// non-printable bytes are double quoted with escapes such as \n and \xff
+func QuoteArg(arg string) string {
+	if arg != "" && strings.IndexFunc(arg, func(r rune) bool {
+		return r <= ' ' || r == '"' || r == '\'' || r == '\\' || r == utf8.RuneError || r > '~'
+	}) < 0 {
+		return arg
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(arg); i++ {
+		switch c := arg[i]; c {
+		case '\\', '"':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString("\\n")
+		case '\r':
+			b.WriteString("\\r")
+		case '\t':
+			b.WriteString("\\t")
+		default:
+			if c < ' ' || c > '~' {
+				fmt.Fprintf(&b, "\\x%02x", c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// KeyExporter writes key records into a file in one of the export formats
+type KeyExporter struct {
+	format ExportFormat
+	writer *bufio.Writer
+	csv    *csv.Writer
+}
+
+// NewKeyExporter create a KeyExporter writing to w, the header is written for csv
+func NewKeyExporter(w io.Writer, format ExportFormat) (*KeyExporter, error) {
+	e := &KeyExporter{format: format, writer: bufio.NewWriter(w)}
+	if format == FormatCSV {
+		e.csv = csv.NewWriter(e.writer)
+		if err := e.csv.Write(csvHeader); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// Write write a record, ErrNotFlat is returned for types not supported by csv
+func (e *KeyExporter) Write(rec KeyRecord) error {
+	switch e.format {
+	case FormatJSONLines:
+		if rec.Binary() {
+			rec = rec.Base64()
+		}
+
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.writer.Write(append(data, '\n'))
+		return err
+	case FormatCSV:
+		return e.writeCSV(rec)
+	}
+
+	commands, err := rec.Commands(true)
+	if err != nil {
+		return err
+	}
+
+	for _, cmd := range commands {
+		args := make([]string, len(cmd))
+		for i, arg := range cmd {
+			args[i] = QuoteArg(arg)
+		}
+
+		if _, err := e.writer.WriteString(strings.Join(args, " ") + "\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ErrNotFlat is returned when a record of stream is written into csv
+var ErrNotFlat = errors.New("only string, list, set, zset and hash can be exported to csv")
+
+func (e *KeyExporter) writeCSV(rec KeyRecord) error {
+	if rec.Type == "stream" {
+		return ErrNotFlat
+	}
+
+	if rec.Binary() {
+		rec = rec.Base64()
+	}
+
+	ttl := strconv.FormatInt(rec.TTL, 10)
+	row := func(field, value string) error {
+		return e.csv.Write([]string{rec.Key, rec.Type, ttl, field, value, rec.Encoding})
+	}
+
+	var err error
+	switch v := rec.Value.(type) {
+	case string:
+		err = row("", v)
+	case []string:
+		for i, value := range v {
+			if rec.Type == "set" {
+				err = row(value, "")
+			} else {
+				err = row(strconv.Itoa(i), value)
+			}
+			if err != nil {
+				break
+			}
+		}
+	case []ZMember:
+		for _, m := range v {
+			if err = row(m.Member, strconv.FormatFloat(m.Score, 'f', -1, 64)); err != nil {
+				break
+			}
+		}
+	case map[string]string:
+		for _, field := range sortedKeys(v) {
+			if err = row(field, v[field]); err != nil {
+				break
+			}
+		}
+	}
+
+	return err
+}
+
+// Flush write the buffered data into the underlying writer
+func (e *KeyExporter) Flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+
+	return e.writer.Flush()
+}
+
+// ExportResult is the statistics of an export
+type ExportResult struct {
+	Exported int
+	// Skipped is the count of keys not exist or not supported by the format
+	Skipped int
+}
+
+// ExportKeys write the keys returned by scanner into w one by one, so that large key sets are not
+// loaded into memory at once. Keys returned more than once by SCAN are exported once, and progress
+// is called after every batch of keys
+func ExportKeys(client RedisClient, scanner *KeyScanner, w io.Writer, format ExportFormat, progress func(res ExportResult)) (ExportResult, error) {
+	var res ExportResult
+
+	exporter, err := NewKeyExporter(w, format)
+	if err != nil {
+		return res, err
+	}
+
+	seen := make(map[string]bool)
+	for !scanner.Done() {
+		batch, err := scanner.Next(1000)
+		if err != nil {
+			return res, err
+		}
+
+		for _, k := range batch {
+			if seen[k.Key] {
+				continue
+			}
+			seen[k.Key] = true
+
+			rec, exists, err := RedisKeyRecord(client, k.Key)
+			if err != nil {
+				return res, fmt.Errorf("%s: %s", k.Key, err)
+			}
+
+			if !exists {
+				res.Skipped++
+				continue
+			}
+
+			if err := exporter.Write(rec); err == ErrNotFlat {
+				res.Skipped++
+				continue
+			} else if err != nil {
+				return res, fmt.Errorf("%s: %s", k.Key, err)
+			}
+			res.Exported++
+		}
+
+		if progress != nil {
+			progress(res)
+		}
+	}
+
+	return res, exporter.Flush()
+}
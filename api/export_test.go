@@ -0,0 +1,133 @@
+package api_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestQuoteArg(t *testing.T) {
+	testcases := map[string]string{
+		"user:1":      "user:1",
+		"":            `""`,
+		"hello world": `"hello world"`,
+		"say \"hi\"":  `"say \"hi\""`,
+		"a\nb":        `"a\nb"`,
+		"\xff\x00":    `"\xff\x00"`,
+		"中文":          `"\xe4\xb8\xad\xe6\x96\x87"`,
+	}
+
+	for arg, expect := range testcases {
+		if quoted := api.QuoteArg(arg); quoted != expect {
+			t.Errorf("test failed: %q, expect %s, got %s", arg, expect, quoted)
+		}
+	}
+}
+
+func TestFormatByPath(t *testing.T) {
+	testcases := map[string]api.ExportFormat{
+		"keys.jsonl":   api.FormatJSONLines,
+		"keys.JSON":    api.FormatJSONLines,
+		"/tmp/out.csv": api.FormatCSV,
+		"keys.redis":   api.FormatCommands,
+		"keys":         api.FormatCommands,
+	}
+
+	for path, expect := range testcases {
+		if format := api.FormatByPath(path); format != expect {
+			t.Errorf("test failed: %s, expect %s, got %s", path, expect, format)
+		}
+	}
+}
+
+func TestKeyExporter(t *testing.T) {
+	records := []api.KeyRecord{
+		{Key: "name", Type: "string", TTL: -1, Value: "Tom"},
+		{Key: "rank", Type: "zset", TTL: 5000, Value: []api.ZMember{{Member: "tom", Score: 1.5}}},
+		{Key: "user:1", Type: "hash", TTL: -1, Value: map[string]string{"name": "Tom", "age": "18"}},
+		{Key: "bin", Type: "string", TTL: -1, Value: "\xff\x01"},
+		{Key: "events", Type: "stream", TTL: -1, Value: []api.StreamEntry{{ID: "1-1", Fields: []string{"a", "1"}}}},
+	}
+
+	export := func(format api.ExportFormat) string {
+		var buf bytes.Buffer
+		exporter, err := api.NewKeyExporter(&buf, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, rec := range records {
+			if err := exporter.Write(rec); err != nil && err != api.ErrNotFlat {
+				t.Fatal(err)
+			}
+		}
+
+		if err := exporter.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		return buf.String()
+	}
+
+	lines := strings.Split(strings.TrimSpace(export(api.FormatJSONLines)), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("test failed: %v", lines)
+	}
+	if lines[0] != `{"key":"name","type":"string","ttl":-1,"value":"Tom"}` {
+		t.Errorf("test failed: %s", lines[0])
+	}
+	if lines[3] != `{"key":"Ymlu","type":"string","ttl":-1,"encoding":"base64","value":"/wE="}` {
+		t.Errorf("test failed: %s", lines[3])
+	}
+
+	expectCSV := "key,type,ttl,field,value,encoding\n" +
+		"name,string,-1,,Tom,\n" +
+		"rank,zset,5000,tom,1.5,\n" +
+		"user:1,hash,-1,age,18,\n" +
+		"user:1,hash,-1,name,Tom,\n" +
+		"Ymlu,string,-1,,/wE=,base64\n"
+	if csv := export(api.FormatCSV); csv != expectCSV {
+		t.Errorf("test failed: %s", csv)
+	}
+
+	expectCommands := "DEL name\nSET name Tom\n" +
+		"DEL rank\nZADD rank 1.5 tom\nPEXPIRE rank 5000\n" +
+		"DEL user:1\nHSET user:1 age 18 name Tom\n" +
+		"DEL bin\nSET bin \"\\xff\\x01\"\n" +
+		"DEL events\nXADD events 1-1 a 1\n"
+	if commands := export(api.FormatCommands); commands != expectCommands {
+		t.Errorf("test failed: %s", commands)
+	}
+}
+
+func TestExportKeys(t *testing.T) {
+	path, remove := testRDBFile(t)
+	defer remove()
+
+	client := api.NewRDBClient(path, 0)
+
+	var buf bytes.Buffer
+	res, err := api.ExportKeys(client, api.NewKeyScanner(client, "user:*"), &buf, api.FormatCommands, nil)
+	if err != nil || res.Exported != 2 || res.Skipped != 0 {
+		t.Fatalf("test failed: %+v, %v", res, err)
+	}
+
+	expect := "DEL user:1\nHSET user:1 age 18 name Tom\n" +
+		"DEL user:2\nHSET user:2 level 3 name Amy\n"
+	if buf.String() != expect {
+		t.Errorf("test failed: %s", buf.String())
+	}
+
+	// streams are skipped in csv
+	buf.Reset()
+	batches := 0
+	res, err = api.ExportKeys(client, api.NewKeyScanner(client, "*"), &buf, api.FormatCSV, func(api.ExportResult) { batches++ })
+	if err != nil || res.Exported != 10 || res.Skipped != 1 || batches != 1 {
+		t.Errorf("test failed: %+v, %d, %v", res, batches, err)
+	}
+	if !strings.Contains(buf.String(), "session,string,60000,,abc,\n") {
+		t.Errorf("test failed: %s", buf.String())
+	}
+}
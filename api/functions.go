@@ -0,0 +1,162 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// FunctionInfo is a function in a library
+type FunctionInfo struct {
+	Name        string
+	Description string
+	Flags       []string
+}
+
+// ReadOnly check whether the function can be called by FCALL_RO
+func (f FunctionInfo) ReadOnly() bool {
+	for _, flag := range f.Flags {
+		if flag == "no-writes" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FunctionLibrary is a library loaded by FUNCTION LOAD
+type FunctionLibrary struct {
+	Name      string
+	Engine    string
+	Functions []FunctionInfo
+	Code      string
+}
+
+// ParseFunctionList parse the reply of FUNCTION LIST [WITHCODE]
+func ParseFunctionList(reply interface{}) []FunctionLibrary {
+	items, _ := reply.([]interface{})
+	libraries := make([]FunctionLibrary, 0, len(items))
+	for _, item := range items {
+		fields := replyMap(item)
+		lib := FunctionLibrary{
+			Name:   replyString(fields["library_name"]),
+			Engine: replyString(fields["engine"]),
+			Code:   replyString(fields["library_code"]),
+		}
+
+		functions, _ := fields["functions"].([]interface{})
+		for _, f := range functions {
+			ff := replyMap(f)
+			info := FunctionInfo{Name: replyString(ff["name"]), Description: replyString(ff["description"])}
+			flags, _ := ff["flags"].([]interface{})
+			for _, flag := range flags {
+				info.Flags = append(info.Flags, fmt.Sprint(flag))
+			}
+
+			lib.Functions = append(lib.Functions, info)
+		}
+
+		libraries = append(libraries, lib)
+	}
+
+	return libraries
+}
+
+// replyString convert a reply into string, nil is converted into an empty string
+func replyString(reply interface{}) string {
+	if reply == nil {
+		return ""
+	}
+
+	return fmt.Sprint(reply)
+}
+
+var libraryNamePattern = regexp.MustCompile(`^#!\w+\s+name=(\w+)`)
+
+// FunctionLibraryName return the library name declared in the shebang line of code, such as #!lua name=mylib
+func FunctionLibraryName(code string) (string, error) {
+	matches := libraryNamePattern.FindStringSubmatch(code)
+	if len(matches) != 2 {
+		return "", errors.New("the code must start with a shebang line such as #!lua name=mylib")
+	}
+
+	return matches[1], nil
+}
+
+// RedisFunctionList return the libraries with their code by FUNCTION LIST WITHCODE
+func RedisFunctionList(client RedisClient) ([]FunctionLibrary, error) {
+	res, err := client.Do("FUNCTION", "LIST", "WITHCODE").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseFunctionList(res), nil
+}
+
+// RedisFunctionLoad load a library, it's loaded on every master in cluster mode
+func RedisFunctionLoad(client RedisClient, code string, replace bool) (string, error) {
+	name, err := FunctionLibraryName(code)
+	if err != nil {
+		return "", err
+	}
+
+	args := []interface{}{"FUNCTION", "LOAD"}
+	if replace {
+		args = append(args, "REPLACE")
+	}
+	args = append(args, code)
+
+	return name, ForEachMaster(client, func(addr string, node RedisClient) error {
+		if err := node.Do(args...).Err(); err != nil {
+			return fmt.Errorf("%s: %s", addr, err)
+		}
+
+		return nil
+	})
+}
+
+// RedisFunctionDelete delete a library, it's deleted from every master in cluster mode
+func RedisFunctionDelete(client RedisClient, name string) error {
+	return ForEachMaster(client, func(addr string, node RedisClient) error {
+		if err := node.Do("FUNCTION", "DELETE", name).Err(); err != nil {
+			return fmt.Errorf("%s: %s", addr, err)
+		}
+
+		return nil
+	})
+}
+
+// RedisFCall call a function by FCALL, or by FCALL_RO if readOnly is true
+func RedisFCall(client RedisClient, name string, keys []string, args []string, readOnly bool) (interface{}, error) {
+	command := "FCALL"
+	if readOnly {
+		command = "FCALL_RO"
+	}
+
+	cmd := []interface{}{command, name, len(keys)}
+	for _, k := range keys {
+		cmd = append(cmd, k)
+	}
+	for _, a := range args {
+		cmd = append(cmd, a)
+	}
+
+	return nilReply(client.Do(cmd...).Result())
+}
+
+// RedisFunctionDump return the serialized payload of all libraries by FUNCTION DUMP
+func RedisFunctionDump(client RedisClient) (string, error) {
+	return client.Do("FUNCTION", "DUMP").String()
+}
+
+// RedisFunctionRestore restore libraries from the payload of FUNCTION DUMP, policy is one of APPEND,
+// REPLACE and FLUSH. It's restored on every master in cluster mode
+func RedisFunctionRestore(client RedisClient, payload string, policy string) error {
+	return ForEachMaster(client, func(addr string, node RedisClient) error {
+		if err := node.Do("FUNCTION", "RESTORE", payload, policy).Err(); err != nil {
+			return fmt.Errorf("%s: %s", addr, err)
+		}
+
+		return nil
+	})
+}
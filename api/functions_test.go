@@ -0,0 +1,42 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestParseFunctionList(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{
+			"library_name", "mylib",
+			"engine", "LUA",
+			"functions", []interface{}{
+				[]interface{}{"name", "myget", "description", nil, "flags", []interface{}{"no-writes"}},
+				[]interface{}{"name", "myset", "description", "set a key", "flags", []interface{}{}},
+			},
+			"library_code", "#!lua name=mylib\nredis.register_function(...)",
+		},
+	}
+
+	libraries := api.ParseFunctionList(reply)
+	if len(libraries) != 1 || libraries[0].Name != "mylib" || libraries[0].Engine != "LUA" || len(libraries[0].Functions) != 2 {
+		t.Fatalf("test failed: %+v", libraries)
+	}
+
+	if f := libraries[0].Functions[0]; !f.ReadOnly() || f.Description != "" {
+		t.Errorf("test failed: %+v", f)
+	}
+
+	if f := libraries[0].Functions[1]; f.ReadOnly() || f.Description != "set a key" {
+		t.Errorf("test failed: %+v", f)
+	}
+
+	if name, err := api.FunctionLibraryName(libraries[0].Code); err != nil || name != "mylib" {
+		t.Errorf("test failed: %s, %v", name, err)
+	}
+
+	if _, err := api.FunctionLibraryName("redis.register_function(...)"); err == nil {
+		t.Error("test failed: code without shebang should be invalid")
+	}
+}
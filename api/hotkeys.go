@@ -0,0 +1,122 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// HotKeyMetric is the metric used for ranking hot keys, it depends on the maxmemory-policy of server
+type HotKeyMetric int
+
+const (
+	// HotKeyMetricNone means the server policy can not be used for hot key detection
+	HotKeyMetricNone HotKeyMetric = iota
+	// HotKeyMetricFreq ranks keys by OBJECT FREQ, available for LFU policies
+	HotKeyMetricFreq
+	// HotKeyMetricIdle ranks keys by OBJECT IDLETIME, available for LRU policies
+	HotKeyMetricIdle
+)
+
+// String return the column name for the metric
+func (m HotKeyMetric) String() string {
+	switch m {
+	case HotKeyMetricFreq:
+		return "Frequency"
+	case HotKeyMetricIdle:
+		return "Idle (s)"
+	}
+
+	return "-"
+}
+
+// HotKey is a key with its access metric
+type HotKey struct {
+	Key   string
+	Value int64
+}
+
+// HotKeysUnsupportedError is returned when the maxmemory-policy doesn't keep access statistics for keys
+type HotKeysUnsupportedError struct {
+	Policy string
+}
+
+func (e HotKeysUnsupportedError) Error() string {
+	return fmt.Sprintf(
+		"maxmemory-policy is %s, hot keys can only be detected with LFU policies (OBJECT FREQ) or LRU policies (OBJECT IDLETIME), "+
+			"try CONFIG SET maxmemory-policy allkeys-lfu",
+		e.Policy,
+	)
+}
+
+// HotKeyMetricForPolicy return the metric can be used for the maxmemory-policy
+func HotKeyMetricForPolicy(policy string) HotKeyMetric {
+	policy = strings.ToLower(policy)
+	switch {
+	case strings.HasSuffix(policy, "-lfu"):
+		return HotKeyMetricFreq
+	case strings.HasSuffix(policy, "-lru"):
+		return HotKeyMetricIdle
+	}
+
+	return HotKeyMetricNone
+}
+
+// RedisConfigGet return the value of a config parameter
+func RedisConfigGet(client RedisClient, parameter string) (string, error) {
+	res, err := client.Do("CONFIG", "GET", parameter).Result()
+	if err != nil {
+		return "", err
+	}
+
+	values, ok := res.([]interface{})
+	if !ok || len(values) < 2 {
+		return "", fmt.Errorf("config %s not found", parameter)
+	}
+
+	return fmt.Sprintf("%v", values[1]), nil
+}
+
+// HotKeys scan keys matched the pattern and rank them by their access metric, the hottest key comes first
+func HotKeys(client RedisClient, pattern string, maxScanCount int) (HotKeyMetric, []HotKey, error) {
+	policy, err := RedisConfigGet(client, "maxmemory-policy")
+	if err != nil {
+		return HotKeyMetricNone, nil, err
+	}
+
+	metric := HotKeyMetricForPolicy(policy)
+	if metric == HotKeyMetricNone {
+		return metric, nil, HotKeysUnsupportedError{Policy: policy}
+	}
+
+	keys, err := KeysWithLimit(client, pattern, maxScanCount)
+	if err != nil {
+		return metric, nil, err
+	}
+
+	subCommand := "FREQ"
+	if metric == HotKeyMetricIdle {
+		subCommand = "IDLETIME"
+	}
+
+	hotKeys := make([]HotKey, 0, len(keys))
+	for _, k := range keys {
+		val, err := client.Do("OBJECT", subCommand, k).Int64()
+		if err != nil {
+			// the key may be expired or deleted during scanning
+			continue
+		}
+
+		hotKeys = append(hotKeys, HotKey{Key: k, Value: val})
+	}
+
+	sort.SliceStable(hotKeys, func(i, j int) bool {
+		if metric == HotKeyMetricIdle {
+			return hotKeys[i].Value < hotKeys[j].Value
+		}
+
+		return hotKeys[i].Value > hotKeys[j].Value
+	})
+
+	return metric, hotKeys, nil
+}
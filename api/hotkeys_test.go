@@ -0,0 +1,24 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestHotKeyMetricForPolicy(t *testing.T) {
+	policies := map[string]api.HotKeyMetric{
+		"allkeys-lfu":  api.HotKeyMetricFreq,
+		"volatile-lfu": api.HotKeyMetricFreq,
+		"allkeys-lru":  api.HotKeyMetricIdle,
+		"volatile-lru": api.HotKeyMetricIdle,
+		"noeviction":   api.HotKeyMetricNone,
+		"volatile-ttl": api.HotKeyMetricNone,
+	}
+
+	for policy, expect := range policies {
+		if metric := api.HotKeyMetricForPolicy(policy); metric != expect {
+			t.Errorf("test failed: policy %s, expect %v, got %v", policy, expect, metric)
+		}
+	}
+}
@@ -0,0 +1,622 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis"
+)
+
+// ImportOptions is the options of importing a file of keys
+type ImportOptions struct {
+	// Format is the format of the file, it's the same as the exported files
+	Format ExportFormat
+	// Replace deletes the keys before writing their values, it's ignored for files of commands
+	Replace bool
+	// IgnoreTTL imports the keys without expiration, it's ignored for files of commands
+	IgnoreTTL bool
+	// Pipeline is the max count of commands sent in a round trip
+	Pipeline int
+	// DryRun only parses and validates the file, nothing is written
+	DryRun bool
+}
+
+// importMaxErrors is the max count of errors kept in ImportResult
+const importMaxErrors = 100
+
+// importMaxBulkLength is the max length of bulk strings in redis protocol, same as proto-max-bulk-len of server
+const importMaxBulkLength = 512 * 1024 * 1024
+
+// ImportCommand is a command parsed from the imported file, Line is the line of command for JSON
+// Lines and commands, and the row for csv
+type ImportCommand struct {
+	Line int
+	Args []string
+}
+
+// String format the command like redis-cli
+func (c ImportCommand) String() string {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = QuoteArg(arg)
+	}
+
+	return strings.Join(args, " ")
+}
+
+// ImportError is an error of a line in the imported file
+type ImportError struct {
+	Line int
+	Err  string
+}
+
+func (e ImportError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+// ImportResult is the statistics of an import
+type ImportResult struct {
+	// Records is the count of valid records (lines of JSON Lines and commands, rows of csv)
+	Records  int
+	Commands int
+	// Failed is the count of invalid records and failed commands
+	Failed int
+	// Errors are the first errors of import
+	Errors []ImportError
+}
+
+// String format the result in a line
+func (r ImportResult) String() string {
+	return fmt.Sprintf("records=%d, commands=%d, failed=%d", r.Records, r.Commands, r.Failed)
+}
+
+func (r *ImportResult) fail(err ImportError) {
+	r.Failed++
+	if len(r.Errors) < importMaxErrors {
+		r.Errors = append(r.Errors, err)
+	}
+}
+
+// ImportReader parses a file of keys into commands record by record
+type ImportReader struct {
+	opts   ImportOptions
+	reader *bufio.Reader
+	line   int
+
+	csv     *csv.Reader
+	columns map[string]int
+	// seen is the keys already read from csv, the key is only deleted and expired at the first row
+	seen map[string]bool
+}
+
+// NewImportReader create an ImportReader reading from r
+func NewImportReader(r io.Reader, opts ImportOptions) *ImportReader {
+	ir := &ImportReader{opts: opts, reader: bufio.NewReader(r)}
+	if opts.Format == FormatCSV {
+		ir.csv = csv.NewReader(ir.reader)
+		ir.csv.FieldsPerRecord = -1
+		ir.seen = make(map[string]bool)
+	}
+
+	return ir
+}
+
+// Next return the commands of next record, io.EOF is returned at the end of file, the record is
+// skipped if an ImportError is returned and the reading can be continued
+func (ir *ImportReader) Next() ([]ImportCommand, error) {
+	switch ir.opts.Format {
+	case FormatJSONLines:
+		return ir.nextJSON()
+	case FormatCSV:
+		return ir.nextCSV()
+	}
+
+	return ir.nextCommand()
+}
+
+// readLine read a line without the line ending, empty lines are skipped
+func (ir *ImportReader) readLine() (string, error) {
+	for {
+		line, err := ir.reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", err
+		}
+
+		ir.line++
+		if line = strings.TrimRight(line, "\r\n"); strings.TrimSpace(line) != "" {
+			return line, nil
+		}
+	}
+}
+
+func (ir *ImportReader) nextJSON() ([]ImportCommand, error) {
+	line, err := ir.readLine()
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := ParseKeyRecord([]byte(line))
+	if err != nil {
+		return nil, ImportError{Line: ir.line, Err: err.Error()}
+	}
+
+	commands, err := rec.Commands(false)
+	if err != nil {
+		return nil, ImportError{Line: ir.line, Err: err.Error()}
+	}
+
+	return ir.commands(commands, rec.Key, rec.TTL, true), nil
+}
+
+// commands attach the line number to commands, DEL is prepended if replace is needed and PEXPIRE
+// is removed if TTL is ignored
+func (ir *ImportReader) commands(commands [][]string, key string, ttl int64, first bool) []ImportCommand {
+	res := make([]ImportCommand, 0, len(commands)+1)
+	if ir.opts.Replace && first {
+		res = append(res, ImportCommand{Line: ir.line, Args: []string{"DEL", key}})
+	}
+
+	for _, cmd := range commands {
+		if cmd[0] == "PEXPIRE" {
+			continue
+		}
+		res = append(res, ImportCommand{Line: ir.line, Args: cmd})
+	}
+
+	if !ir.opts.IgnoreTTL && ttl > 0 && first {
+		res = append(res, ImportCommand{Line: ir.line, Args: []string{"PEXPIRE", key, strconv.FormatInt(ttl, 10)}})
+	}
+
+	return res
+}
+
+// ParseKeyRecord parse a line of exported JSON Lines, base64 encoded strings are decoded
+func ParseKeyRecord(data []byte) (KeyRecord, error) {
+	var raw struct {
+		Key      string          `json:"key"`
+		Type     string          `json:"type"`
+		TTL      *int64          `json:"ttl"`
+		Encoding string          `json:"encoding"`
+		Value    json.RawMessage `json:"value"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return KeyRecord{}, err
+	}
+
+	rec := KeyRecord{Key: raw.Key, Type: raw.Type, TTL: -1}
+	if raw.TTL != nil && *raw.TTL > 0 {
+		rec.TTL = *raw.TTL
+	}
+
+	if rec.Key == "" {
+		return rec, errors.New("key is missing")
+	}
+	if len(raw.Value) == 0 || string(raw.Value) == "null" {
+		return rec, errors.New("value is missing")
+	}
+
+	var value interface{}
+	switch rec.Type {
+	case "string":
+		value = new(string)
+	case "list", "set":
+		value = new([]string)
+	case "zset":
+		value = new([]ZMember)
+	case "hash":
+		value = new(map[string]string)
+	case "stream":
+		value = new([]StreamEntry)
+	default:
+		return rec, fmt.Errorf("type %q is not supported", rec.Type)
+	}
+
+	err := json.Unmarshal(raw.Value, value)
+	if err != nil {
+		return rec, fmt.Errorf("invalid value of %s: %s", rec.Type, err)
+	}
+	rec.Value = reflect.ValueOf(value).Elem().Interface()
+
+	switch raw.Encoding {
+	case "":
+	case "base64":
+		rec.strings(func(s string) string {
+			decoded, e := base64.StdEncoding.DecodeString(s)
+			if e != nil && err == nil {
+				err = fmt.Errorf("invalid base64 string %q", s)
+			}
+			return string(decoded)
+		})
+	default:
+		err = fmt.Errorf("encoding %q is not supported", raw.Encoding)
+	}
+
+	return rec, err
+}
+
+func (ir *ImportReader) nextCSV() ([]ImportCommand, error) {
+	if ir.columns == nil {
+		header, err := ir.csv.Read()
+		if err != nil {
+			return nil, err
+		}
+		ir.line++
+
+		ir.columns = make(map[string]int)
+		for i, name := range header {
+			ir.columns[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+
+		if _, ok := ir.columns["key"]; !ok {
+			return nil, errors.New("column key is missing in the header of csv")
+		}
+		if _, ok := ir.columns["value"]; !ok {
+			return nil, errors.New("column value is missing in the header of csv")
+		}
+	}
+
+	row, err := ir.csv.Read()
+	if err != nil {
+		if _, ok := err.(*csv.ParseError); ok {
+			ir.line++
+			return nil, ImportError{Line: ir.line, Err: err.Error()}
+		}
+		return nil, err
+	}
+	ir.line++
+
+	column := func(name string) string {
+		if i, ok := ir.columns[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	key, keyType, field, value := column("key"), strings.ToLower(column("type")), column("field"), column("value")
+	if keyType == "" {
+		keyType = "string"
+		if field != "" {
+			keyType = "hash"
+		}
+	}
+
+	switch encoding := column("encoding"); encoding {
+	case "":
+	case "base64":
+		for _, s := range []*string{&key, &field, &value} {
+			// scores of sorted sets are not encoded
+			if s == &value && keyType == "zset" {
+				continue
+			}
+
+			decoded, err := base64.StdEncoding.DecodeString(*s)
+			if err != nil {
+				return nil, ImportError{Line: ir.line, Err: fmt.Sprintf("invalid base64 string %q", *s)}
+			}
+			*s = string(decoded)
+		}
+	default:
+		return nil, ImportError{Line: ir.line, Err: fmt.Sprintf("encoding %q is not supported", encoding)}
+	}
+
+	if key == "" {
+		return nil, ImportError{Line: ir.line, Err: "key is empty"}
+	}
+
+	ttl := int64(-1)
+	if text := strings.TrimSpace(column("ttl")); text != "" {
+		if ttl, err = strconv.ParseInt(text, 10, 64); err != nil {
+			return nil, ImportError{Line: ir.line, Err: fmt.Sprintf("invalid ttl %q", text)}
+		}
+	}
+
+	var cmd []string
+	switch keyType {
+	case "string":
+		cmd = []string{"SET", key, value}
+	case "list":
+		cmd = []string{"RPUSH", key, value}
+	case "set":
+		// members of sets are exported in column field
+		if field == "" {
+			field = value
+		}
+		cmd = []string{"SADD", key, field}
+	case "zset":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return nil, ImportError{Line: ir.line, Err: fmt.Sprintf("invalid score %q", value)}
+		}
+		cmd = []string{"ZADD", key, value, field}
+	case "hash":
+		cmd = []string{"HSET", key, field, value}
+	default:
+		return nil, ImportError{Line: ir.line, Err: fmt.Sprintf("type %q is not supported in csv", keyType)}
+	}
+
+	first := !ir.seen[key]
+	ir.seen[key] = true
+
+	return ir.commands([][]string{cmd}, key, ttl, first), nil
+}
+
+func (ir *ImportReader) nextCommand() ([]ImportCommand, error) {
+	for {
+		line, err := ir.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		// lines starting with # are comments
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "*") {
+			return ir.nextRESP(line)
+		}
+
+		args, err := SplitArgs(line)
+		if err != nil {
+			return nil, ImportError{Line: ir.line, Err: err.Error()}
+		}
+
+		return []ImportCommand{{Line: ir.line, Args: args}}, nil
+	}
+}
+
+// nextRESP read a command in redis protocol starting with the header line, which is the input
+// format of redis-cli --pipe
+func (ir *ImportReader) nextRESP(header string) ([]ImportCommand, error) {
+	start := ir.line
+	readLine := func() (string, error) {
+		line, err := ir.reader.ReadString('\n')
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		ir.line++
+		return strings.TrimRight(line, "\r\n"), err
+	}
+
+	count, err := strconv.Atoi(header[1:])
+	if err != nil || count <= 0 {
+		return nil, ImportError{Line: start, Err: fmt.Sprintf("invalid multibulk length %q", header)}
+	}
+
+	// the count is not trusted for allocation, arguments are appended as read
+	args := make([]string, 0)
+	for i := 0; i < count; i++ {
+		line, err := readLine()
+		if err != nil {
+			return nil, ImportError{Line: start, Err: err.Error()}
+		}
+
+		size, err := strconv.Atoi(strings.TrimPrefix(line, "$"))
+		if !strings.HasPrefix(line, "$") || err != nil || size < 0 || size > importMaxBulkLength {
+			return nil, ImportError{Line: ir.line, Err: fmt.Sprintf("invalid bulk length %q", line)}
+		}
+
+		// the bulk string may contain line endings, the buffer grows with the data read so that
+		// a truncated file doesn't allocate the whole length
+		var buf bytes.Buffer
+		if _, err := io.CopyN(&buf, ir.reader, int64(size)+2); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, ImportError{Line: ir.line, Err: err.Error()}
+		}
+
+		data := buf.Bytes()
+		ir.line += bytes.Count(data, []byte("\n"))
+		if !bytes.HasSuffix(data, []byte("\r\n")) {
+			return nil, ImportError{Line: ir.line, Err: "bulk string is not terminated by CRLF"}
+		}
+		args = append(args, string(data[:size]))
+	}
+
+	return []ImportCommand{{Line: start, Args: args}}, nil
+}
+
+// SplitArgs split a line into arguments like redis-cli, arguments can be double quoted with escapes
+// such as \n and \xff, or single quoted
+func SplitArgs(line string) ([]string, error) {
+	args := make([]string, 0)
+	for i := 0; i < len(line); {
+		if line[i] == ' ' || line[i] == '\t' {
+			i++
+			continue
+		}
+
+		var arg strings.Builder
+		switch quote := line[i]; quote {
+		case '"', '\'':
+			i++
+			closed := false
+			for i < len(line) && !closed {
+				c := line[i]
+				switch {
+				case c == quote:
+					closed = true
+				case c == '\\' && i+1 < len(line) && quote == '\'':
+					if line[i+1] == '\'' {
+						i++
+						c = '\''
+					}
+					arg.WriteByte(c)
+				case c == '\\' && i+1 < len(line):
+					i++
+					switch e := line[i]; e {
+					case 'n':
+						arg.WriteByte('\n')
+					case 'r':
+						arg.WriteByte('\r')
+					case 't':
+						arg.WriteByte('\t')
+					case 'b':
+						arg.WriteByte('\b')
+					case 'a':
+						arg.WriteByte('\a')
+					case 'x':
+						if i+2 < len(line) {
+							if v, err := strconv.ParseUint(line[i+1:i+3], 16, 8); err == nil {
+								arg.WriteByte(byte(v))
+								i += 2
+								break
+							}
+						}
+						arg.WriteByte(e)
+					default:
+						arg.WriteByte(e)
+					}
+				default:
+					arg.WriteByte(c)
+				}
+				i++
+			}
+
+			if !closed {
+				return nil, errors.New("unbalanced quotes")
+			}
+			if i < len(line) && line[i] != ' ' && line[i] != '\t' {
+				return nil, errors.New("closing quote must be followed by a space")
+			}
+		default:
+			for i < len(line) && line[i] != ' ' && line[i] != '\t' {
+				arg.WriteByte(line[i])
+				i++
+			}
+		}
+
+		args = append(args, arg.String())
+	}
+
+	return args, nil
+}
+
+// pipeliner is implemented by clients supporting pipelining
+type pipeliner interface {
+	Pipeline() redis.Pipeliner
+}
+
+// Import read the records from r and write them, the commands are sent in pipelines. progress is
+// called every 1000 records and the import is stopped when stop is closed
+func Import(client RedisClient, r io.Reader, opts ImportOptions, progress func(res ImportResult), stop <-chan struct{}) (ImportResult, error) {
+	if opts.Pipeline <= 0 {
+		opts.Pipeline = 1000
+	}
+
+	var res ImportResult
+	batch := make([]ImportCommand, 0, opts.Pipeline)
+	flush := func() {
+		defer func() { batch = batch[:0] }()
+		if opts.DryRun || len(batch) == 0 {
+			return
+		}
+
+		cmds := make([]*redis.Cmd, len(batch))
+		if p, ok := client.(pipeliner); ok {
+			pipe := p.Pipeline()
+			for i, c := range batch {
+				cmds[i] = pipe.Do(stringArgs(c.Args)...)
+			}
+			_, _ = pipe.Exec()
+			_ = pipe.Close()
+		} else {
+			for i, c := range batch {
+				cmds[i] = client.Do(stringArgs(c.Args)...)
+			}
+		}
+
+		for i, cmd := range cmds {
+			if err := cmd.Err(); err != nil && err != redis.Nil {
+				res.fail(ImportError{Line: batch[i].Line, Err: err.Error()})
+			}
+		}
+	}
+
+	reader := NewImportReader(r, opts)
+	for {
+		select {
+		case <-stop:
+			flush()
+			return res, errors.New("import stopped")
+		default:
+		}
+
+		commands, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if e, ok := err.(ImportError); ok {
+				res.fail(e)
+				continue
+			}
+
+			flush()
+			return res, err
+		}
+
+		res.Records++
+		res.Commands += len(commands)
+		batch = append(batch, commands...)
+		if len(batch) >= opts.Pipeline {
+			flush()
+		}
+
+		if progress != nil && res.Records%1000 == 0 {
+			progress(res)
+		}
+	}
+
+	flush()
+	return res, nil
+}
+
+// PreviewImport parse and validate the records from r without writing, the first limit commands are returned
+func PreviewImport(r io.Reader, opts ImportOptions, limit int) ([]ImportCommand, ImportResult, error) {
+	preview := make([]ImportCommand, 0, limit)
+	var res ImportResult
+
+	reader := NewImportReader(r, opts)
+	for {
+		commands, err := reader.Next()
+		if err == io.EOF {
+			return preview, res, nil
+		}
+		if err != nil {
+			if e, ok := err.(ImportError); ok {
+				res.fail(e)
+				continue
+			}
+			return preview, res, err
+		}
+
+		res.Records++
+		res.Commands += len(commands)
+		for _, c := range commands {
+			if len(preview) < limit {
+				preview = append(preview, c)
+			}
+		}
+	}
+}
+
+// stringArgs convert the arguments for Do
+func stringArgs(args []string) []interface{} {
+	res := make([]interface{}, len(args))
+	for i, arg := range args {
+		res[i] = arg
+	}
+
+	return res
+}
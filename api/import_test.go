@@ -0,0 +1,165 @@
+package api_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestSplitArgs(t *testing.T) {
+	testcases := map[string][]string{
+		"SET name Tom":                   {"SET", "name", "Tom"},
+		`  SET  "hello world"  'a b' `:   {"SET", "hello world", "a b"},
+		`SET bin "\xff\x01\n"`:           {"SET", "bin", "\xff\x01\n"},
+		`SET quote "say \"hi\"" 'it\'s'`: {"SET", "quote", `say "hi"`, "it's"},
+		`SET empty ""`:                   {"SET", "empty", ""},
+	}
+
+	for line, expect := range testcases {
+		args, err := api.SplitArgs(line)
+		if err != nil {
+			t.Errorf("test failed: %s, %s", line, err)
+			continue
+		}
+
+		if fmt.Sprintf("%q", args) != fmt.Sprintf("%q", expect) {
+			t.Errorf("test failed: %s, expect %q, got %q", line, expect, args)
+		}
+	}
+
+	for _, line := range []string{`SET "unclosed`, `SET "a"b`} {
+		if _, err := api.SplitArgs(line); err == nil {
+			t.Errorf("test failed: %s should be invalid", line)
+		}
+	}
+
+	// quoted arguments are split back into the same arguments
+	for _, arg := range []string{"a b", "\xff\x00\"'\\", "中文"} {
+		args, err := api.SplitArgs("SET k " + api.QuoteArg(arg))
+		if err != nil || len(args) != 3 || args[2] != arg {
+			t.Errorf("test failed: %q, got %q, %v", arg, args, err)
+		}
+	}
+}
+
+// previewCommands parse the content and return the commands line by line
+func previewCommands(t *testing.T, content string, opts api.ImportOptions) ([]string, api.ImportResult) {
+	commands, res, err := api.PreviewImport(strings.NewReader(content), opts, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := make([]string, len(commands))
+	for i, c := range commands {
+		lines[i] = fmt.Sprintf("%d: %s", c.Line, c)
+	}
+
+	return lines, res
+}
+
+func TestImportJSONLines(t *testing.T) {
+	records := []api.KeyRecord{
+		{Key: "rank", Type: "zset", TTL: 5000, Value: []api.ZMember{{Member: "tom", Score: 1.5}}},
+		{Key: "bin", Type: "string", TTL: -1, Value: "\xff\x01"},
+	}
+
+	var buf bytes.Buffer
+	exporter, _ := api.NewKeyExporter(&buf, api.FormatJSONLines)
+	for _, rec := range records {
+		if err := exporter.Write(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = exporter.Flush()
+
+	content := buf.String() + "\n" + `{"key":"k","type":"list","value":"not a list"}` + "\n" + `{"key":"k","type":"bitmap","value":""}` + "\n"
+	lines, res := previewCommands(t, content, api.ImportOptions{Format: api.FormatJSONLines, Replace: true})
+
+	expect := []string{
+		"1: DEL rank",
+		"1: ZADD rank 1.5 tom",
+		"1: PEXPIRE rank 5000",
+		`2: DEL bin`,
+		`2: SET bin "\xff\x01"`,
+	}
+	if strings.Join(lines, "\n") != strings.Join(expect, "\n") {
+		t.Errorf("test failed: %q", lines)
+	}
+
+	if res.Records != 2 || res.Commands != 5 || res.Failed != 2 || res.Errors[0].Line != 4 || res.Errors[1].Line != 5 {
+		t.Errorf("test failed: %+v", res)
+	}
+
+	lines, _ = previewCommands(t, buf.String(), api.ImportOptions{Format: api.FormatJSONLines, IgnoreTTL: true})
+	if len(lines) != 2 || lines[0] != "1: ZADD rank 1.5 tom" {
+		t.Errorf("test failed: %q", lines)
+	}
+}
+
+func TestImportCSV(t *testing.T) {
+	content := "Key,Field,Value,TTL\n" +
+		"user:1,name,Tom,5000\n" +
+		"user:1,age,18,5000\n" +
+		"greeting,,hello,\n" +
+		"bad,,x,soon\n"
+
+	lines, res := previewCommands(t, content, api.ImportOptions{Format: api.FormatCSV, Replace: true})
+	expect := []string{
+		"2: DEL user:1",
+		"2: HSET user:1 name Tom",
+		"2: PEXPIRE user:1 5000",
+		"3: HSET user:1 age 18",
+		"4: DEL greeting",
+		"4: SET greeting hello",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expect, "\n") {
+		t.Errorf("test failed: %q", lines)
+	}
+
+	if res.Records != 3 || res.Failed != 1 || res.Errors[0].Line != 5 {
+		t.Errorf("test failed: %+v", res)
+	}
+
+	if _, _, err := api.PreviewImport(strings.NewReader("name,value\n"), api.ImportOptions{Format: api.FormatCSV}, 10); err == nil {
+		t.Error("test failed: column key is missing")
+	}
+}
+
+func TestImportCommands(t *testing.T) {
+	content := "# comment\n" +
+		"SET name \"Tom Cat\"\n" +
+		"\n" +
+		"*3\r\n$3\r\nSET\r\n$3\r\nbin\r\n$4\r\na\r\nb\r\n" +
+		"SET \"broken\n" +
+		"PEXPIRE name 5000\n"
+
+	lines, res := previewCommands(t, content, api.ImportOptions{})
+	expect := []string{
+		`2: SET name "Tom Cat"`,
+		`4: SET bin "a\r\nb"`,
+		`13: PEXPIRE name 5000`,
+	}
+	if strings.Join(lines, "\n") != strings.Join(expect, "\n") {
+		t.Errorf("test failed: %q", lines)
+	}
+
+	if res.Records != 3 || res.Failed != 1 || res.Errors[0].Line != 12 {
+		t.Errorf("test failed: %+v", res)
+	}
+
+	// corrupted lengths are reported without allocating them
+	for _, content := range []string{
+		"*2\r\n$9999999999\r\n",
+		"*2\r\n$3\r\nGET\r\n$100000000\r\nab\r\n",
+		"*99999999999\r\n$3\r\nGET\r\n",
+		"*1\r\n$3\r\nGETXX\r\n",
+	} {
+		_, res := previewCommands(t, content, api.ImportOptions{})
+		if res.Commands != 0 || res.Failed == 0 {
+			t.Errorf("test failed: %q, %+v", content, res)
+		}
+	}
+}
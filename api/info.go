@@ -0,0 +1,479 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// InfoField is a key value pair in the INFO command output
+type InfoField struct {
+	Key   string
+	Value string
+}
+
+// InfoSection is a section in the INFO command output, such as Server, Memory or Keyspace
+type InfoSection struct {
+	Name   string
+	Fields []InfoField
+}
+
+// Get return the value of a field in the section
+func (s InfoSection) Get(key string) (string, bool) {
+	for _, f := range s.Fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// KeyspaceInfo is the statistics of a database in the Keyspace section
+type KeyspaceInfo struct {
+	DB      int
+	Keys    int64
+	Expires int64
+	AvgTTL  int64
+}
+
+// CommandStat is the statistics of a command in the Commandstats section
+type CommandStat struct {
+	Command       string
+	Calls         int64
+	Usec          int64
+	UsecPerCall   float64
+	RejectedCalls int64
+	FailedCalls   int64
+}
+
+// NodeServerInfo is the server info of a node
+type NodeServerInfo struct {
+	Addr string
+	Info *ServerInfo
+}
+
+// ServerInfo is the parsed INFO command output
+type ServerInfo struct {
+	Sections []InfoSection
+	// Nodes contains the server info of every master when the info is aggregated from a cluster
+	Nodes []NodeServerInfo
+}
+
+// ParseInfo parse the INFO command output, the order of sections and fields is kept
+func ParseInfo(text string) *ServerInfo {
+	info := &ServerInfo{Sections: make([]InfoSection, 0)}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			info.Sections = append(info.Sections, InfoSection{Name: strings.TrimSpace(strings.TrimPrefix(line, "#"))})
+			continue
+		}
+
+		pair := strings.SplitN(line, ":", 2)
+		if len(pair) != 2 {
+			continue
+		}
+
+		if len(info.Sections) == 0 {
+			info.Sections = append(info.Sections, InfoSection{Name: "Default"})
+		}
+
+		current := &info.Sections[len(info.Sections)-1]
+		current.Fields = append(current.Fields, InfoField{Key: pair[0], Value: pair[1]})
+	}
+
+	return info
+}
+
+// Section return the section with the name, case insensitive
+func (info *ServerInfo) Section(name string) (InfoSection, bool) {
+	for _, s := range info.Sections {
+		if strings.EqualFold(s.Name, name) {
+			return s, true
+		}
+	}
+
+	return InfoSection{}, false
+}
+
+// Get return the value of a field in any section
+func (info *ServerInfo) Get(key string) (string, bool) {
+	for _, s := range info.Sections {
+		if v, ok := s.Get(key); ok {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// String return the value of a field, empty string will be returned if it's missing
+func (info *ServerInfo) String(key string) string {
+	v, _ := info.Get(key)
+	return v
+}
+
+// Int64 return the value of a numeric field, 0 will be returned if it's missing or not an integer
+func (info *ServerInfo) Int64(key string) int64 {
+	v, _ := strconv.ParseInt(info.String(key), 10, 64)
+	return v
+}
+
+// Float64 return the value of a numeric field, 0 will be returned if it's missing or not a number
+func (info *ServerInfo) Float64(key string) float64 {
+	v, _ := strconv.ParseFloat(info.String(key), 64)
+	return v
+}
+
+// Keyspace return the statistics of all databases in the Keyspace section
+func (info *ServerInfo) Keyspace() []KeyspaceInfo {
+	section, ok := info.Section("Keyspace")
+	if !ok {
+		return nil
+	}
+
+	keyspace := make([]KeyspaceInfo, 0, len(section.Fields))
+	for _, f := range section.Fields {
+		db, err := strconv.Atoi(strings.TrimPrefix(f.Key, "db"))
+		if err != nil {
+			continue
+		}
+
+		values := parseInfoValues(f.Value)
+		keyspace = append(keyspace, KeyspaceInfo{
+			DB:      db,
+			Keys:    int64(values["keys"]),
+			Expires: int64(values["expires"]),
+			AvgTTL:  int64(values["avg_ttl"]),
+		})
+	}
+
+	return keyspace
+}
+
+// DBKeyspace return the statistics of a database, the second return value is false if the database is empty
+func (info *ServerInfo) DBKeyspace(db int) (KeyspaceInfo, bool) {
+	for _, ks := range info.Keyspace() {
+		if ks.DB == db {
+			return ks, true
+		}
+	}
+
+	return KeyspaceInfo{DB: db}, false
+}
+
+// DatabaseKeyspaces return the statistics of databases from 0 to databases-1, empty databases are included.
+// Databases beyond the count are appended if they are present in the Keyspace section
+func (info *ServerInfo) DatabaseKeyspaces(databases int) []KeyspaceInfo {
+	keyspace := make([]KeyspaceInfo, 0, databases)
+	for db := 0; db < databases; db++ {
+		ks, _ := info.DBKeyspace(db)
+		keyspace = append(keyspace, ks)
+	}
+
+	for _, ks := range info.Keyspace() {
+		if ks.DB >= databases {
+			keyspace = append(keyspace, ks)
+		}
+	}
+
+	return keyspace
+}
+
+// CommandStats return the statistics of commands in the Commandstats section, sorted by calls desc
+func (info *ServerInfo) CommandStats() []CommandStat {
+	section, ok := info.Section("Commandstats")
+	if !ok {
+		return nil
+	}
+
+	stats := make([]CommandStat, 0, len(section.Fields))
+	for _, f := range section.Fields {
+		values := parseInfoValues(f.Value)
+		stats = append(stats, CommandStat{
+			Command:       strings.TrimPrefix(f.Key, "cmdstat_"),
+			Calls:         int64(values["calls"]),
+			Usec:          int64(values["usec"]),
+			UsecPerCall:   values["usec_per_call"],
+			RejectedCalls: int64(values["rejected_calls"]),
+			FailedCalls:   int64(values["failed_calls"]),
+		})
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Calls > stats[j].Calls
+	})
+
+	return stats
+}
+
+// parseInfoValues parse a value formatted as k1=v1,k2=v2 into a map of numbers
+func parseInfoValues(value string) map[string]float64 {
+	values := make(map[string]float64)
+	for _, kv := range strings.Split(value, ",") {
+		pair := strings.SplitN(kv, "=", 2)
+		if len(pair) != 2 {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(pair[1], 64)
+		if err != nil {
+			continue
+		}
+
+		values[pair[0]] = v
+	}
+
+	return values
+}
+
+// isInfoValues check whether the value is formatted as k1=v1,k2=v2
+func isInfoValues(value string) bool {
+	return strings.Contains(value, "=") && len(parseInfoValues(value)) == len(strings.Split(value, ","))
+}
+
+// formatInfoValues format values into k1=v1,k2=v2 with the order of keys
+func formatInfoValues(keys []string, values map[string]float64) string {
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+strconv.FormatFloat(values[k], 'f', -1, 64))
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+// summableSections are sections whose numeric fields can be summed when aggregating nodes
+var summableSections = map[string]bool{
+	"clients":      true,
+	"memory":       true,
+	"stats":        true,
+	"cpu":          true,
+	"keyspace":     true,
+	"commandstats": true,
+	"errorstats":   true,
+}
+
+// maxInfoFields are fields of the host or limits of a node, they take the max value of nodes instead of the sum
+var maxInfoFields = map[string]bool{
+	"total_system_memory": true,
+	"maxmemory":           true,
+}
+
+// AggregateServerInfo merge the server info of nodes into one, numeric fields in summableSections
+// are summed except ratios which are averaged over the nodes reporting them, and maxInfoFields which
+// take the max value, other fields take the value of the first node
+func AggregateServerInfo(nodes []NodeServerInfo) *ServerInfo {
+	if len(nodes) == 1 {
+		return &ServerInfo{Sections: nodes[0].Info.Sections, Nodes: nodes}
+	}
+
+	aggregated := &ServerInfo{Sections: make([]InfoSection, 0), Nodes: nodes}
+	// reported is the count of nodes reporting a field, keyed by section and field
+	reported := make(map[string]int)
+	for _, node := range nodes {
+		for _, section := range node.Info.Sections {
+			var target *InfoSection
+			for i := range aggregated.Sections {
+				if aggregated.Sections[i].Name == section.Name {
+					target = &aggregated.Sections[i]
+					break
+				}
+			}
+
+			if target == nil {
+				aggregated.Sections = append(aggregated.Sections, InfoSection{Name: section.Name})
+				target = &aggregated.Sections[len(aggregated.Sections)-1]
+			}
+
+			sum := summableSections[strings.ToLower(section.Name)]
+			for _, f := range section.Fields {
+				reported[section.Name+"\x00"+f.Key]++
+				target.Fields = mergeInfoField(target.Fields, f, sum)
+			}
+		}
+	}
+
+	for i := range aggregated.Sections {
+		section := &aggregated.Sections[i]
+		if !summableSections[strings.ToLower(section.Name)] {
+			continue
+		}
+
+		for j, f := range section.Fields {
+			switch {
+			case strings.HasSuffix(f.Key, "_ratio"):
+				v, _ := strconv.ParseFloat(f.Value, 64)
+				section.Fields[j].Value = strconv.FormatFloat(v/float64(reported[section.Name+"\x00"+f.Key]), 'f', 2, 64)
+			case strings.HasSuffix(f.Key, "_human"):
+				if v, ok := section.Get(strings.TrimSuffix(f.Key, "_human")); ok {
+					if bytes, err := strconv.ParseInt(v, 10, 64); err == nil {
+						section.Fields[j].Value = humanBytes(bytes)
+					}
+				}
+			}
+		}
+	}
+
+	return aggregated
+}
+
+// mergeInfoField merge a field into fields, the values are summed only when sum is true
+func mergeInfoField(fields []InfoField, field InfoField, sum bool) []InfoField {
+	for i, f := range fields {
+		if f.Key != field.Key {
+			continue
+		}
+
+		if !sum {
+			return fields
+		}
+
+		if maxInfoFields[f.Key] {
+			if a, err := strconv.ParseInt(f.Value, 10, 64); err == nil {
+				if b, err := strconv.ParseInt(field.Value, 10, 64); err == nil && b > a {
+					fields[i].Value = field.Value
+				}
+			}
+			return fields
+		}
+
+		if a, err := strconv.ParseInt(f.Value, 10, 64); err == nil {
+			if b, err := strconv.ParseInt(field.Value, 10, 64); err == nil {
+				fields[i].Value = strconv.FormatInt(a+b, 10)
+			}
+			return fields
+		}
+
+		if a, err := strconv.ParseFloat(f.Value, 64); err == nil {
+			if b, err := strconv.ParseFloat(field.Value, 64); err == nil {
+				fields[i].Value = strconv.FormatFloat(a+b, 'f', -1, 64)
+			}
+			return fields
+		}
+
+		if isInfoValues(f.Value) && isInfoValues(field.Value) {
+			fields[i].Value = mergeInfoValues(f.Value, field.Value)
+		}
+
+		return fields
+	}
+
+	return append(fields, field)
+}
+
+// mergeInfoValues merge two values formatted as k1=v1,k2=v2, averages are weighted by their counts
+func mergeInfoValues(a, b string) string {
+	keys := make([]string, 0)
+	for _, kv := range strings.Split(a, ",") {
+		keys = append(keys, strings.SplitN(kv, "=", 2)[0])
+	}
+
+	av, bv := parseInfoValues(a), parseInfoValues(b)
+	merged := make(map[string]float64)
+	for _, k := range keys {
+		merged[k] = av[k] + bv[k]
+	}
+
+	if _, ok := merged["avg_ttl"]; ok && merged["expires"] > 0 {
+		merged["avg_ttl"] = float64(int64((av["avg_ttl"]*av["expires"] + bv["avg_ttl"]*bv["expires"]) / merged["expires"]))
+	}
+
+	if _, ok := merged["usec_per_call"]; ok && merged["calls"] > 0 {
+		merged["usec_per_call"] = float64(int64(merged["usec"]/merged["calls"]*100)) / 100
+	}
+
+	return formatInfoValues(keys, merged)
+}
+
+// humanBytes format bytes in the same way as redis does for *_human fields
+func humanBytes(bytes int64) string {
+	units := []string{"B", "K", "M", "G", "T", "P"}
+	v := float64(bytes)
+	i := 0
+	for v >= 1024 && i < len(units)-1 {
+		v /= 1024
+		i++
+	}
+
+	if i == 0 {
+		return strconv.FormatInt(bytes, 10) + units[i]
+	}
+
+	return strconv.FormatFloat(v, 'f', 2, 64) + units[i]
+}
+
+// RedisNodeServerInfos query INFO from every master in cluster mode, or from the server in standalone mode
+func RedisNodeServerInfos(client RedisClient, section ...string) ([]NodeServerInfo, error) {
+	return redisNodeServerInfos(ForEachMaster, client, section...)
+}
+
+// RedisAllNodeServerInfos query INFO from every node including replicas in cluster mode, or from the server in standalone mode
+func RedisAllNodeServerInfos(client RedisClient, section ...string) ([]NodeServerInfo, error) {
+	return redisNodeServerInfos(ForEachNode, client, section...)
+}
+
+func redisNodeServerInfos(forEach func(RedisClient, func(string, RedisClient) error) error, client RedisClient, section ...string) ([]NodeServerInfo, error) {
+	var lock sync.Mutex
+	nodes := make([]NodeServerInfo, 0)
+	err := forEach(client, func(addr string, node RedisClient) error {
+		res, err := node.Info(section...).Result()
+		if err != nil {
+			return err
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		nodes = append(nodes, NodeServerInfo{Addr: addr, Info: ParseInfo(res)})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Addr < nodes[j].Addr
+	})
+
+	return nodes, nil
+}
+
+// RedisServerInfo query INFO from the server, the info of all masters are aggregated in cluster mode
+func RedisServerInfo(client RedisClient, section ...string) (*ServerInfo, error) {
+	nodes, err := RedisNodeServerInfos(client, section...)
+	if err != nil {
+		return nil, err
+	}
+
+	return AggregateServerInfo(nodes), nil
+}
+
+// defaultDatabases is the default value of config databases, which is used when CONFIG GET is not allowed
+const defaultDatabases = 16
+
+// RedisDatabases return the statistics of all databases with their key counts from the Keyspace section,
+// the count of databases is queried by CONFIG GET databases
+func RedisDatabases(client RedisClient) ([]KeyspaceInfo, error) {
+	info, err := RedisServerInfo(client, "keyspace")
+	if err != nil {
+		return nil, err
+	}
+
+	databases := defaultDatabases
+	if res, err := client.Do("CONFIG", "GET", "databases").Result(); err == nil {
+		if items, ok := res.([]interface{}); ok && len(items) == 2 {
+			if n, err := strconv.Atoi(fmt.Sprint(items[1])); err == nil && n > 0 {
+				databases = n
+			}
+		}
+	}
+
+	return info.DatabaseKeyspaces(databases), nil
+}
@@ -0,0 +1,88 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+var infoText = "# Server\r\nredis_version:7.0.11\r\ntcp_port:6379\r\n\r\n" +
+	"# Memory\r\nused_memory:1048576\r\nused_memory_human:1.00M\r\nmem_fragmentation_ratio:1.50\r\n\r\n" +
+	"# Stats\r\ninstantaneous_ops_per_sec:10\r\n\r\n" +
+	"# Commandstats\r\ncmdstat_get:calls=10,usec=100,usec_per_call=10.00,rejected_calls=0,failed_calls=1\r\n" +
+	"cmdstat_set:calls=20,usec=100,usec_per_call=5.00,rejected_calls=0,failed_calls=0\r\n\r\n" +
+	"# Keyspace\r\ndb0:keys=100,expires=10,avg_ttl=1000\r\ndb3:keys=5,expires=0,avg_ttl=0\r\n"
+
+func TestParseInfo(t *testing.T) {
+	info := api.ParseInfo(infoText)
+	if len(info.Sections) != 5 {
+		t.Fatalf("test failed: expect 5 sections, got %d", len(info.Sections))
+	}
+
+	if info.String("redis_version") != "7.0.11" || info.Int64("used_memory") != 1048576 {
+		t.Error("test failed")
+	}
+
+	ks, ok := info.DBKeyspace(3)
+	if !ok || ks.Keys != 5 {
+		t.Errorf("test failed: %v", ks)
+	}
+
+	if _, ok := info.DBKeyspace(1); ok {
+		t.Error("test failed")
+	}
+
+	databases := info.DatabaseKeyspaces(2)
+	if len(databases) != 3 || databases[0].Keys != 100 || databases[1].DB != 1 || databases[1].Keys != 0 || databases[2].DB != 3 {
+		t.Errorf("test failed: %v", databases)
+	}
+
+	stats := info.CommandStats()
+	if len(stats) != 2 || stats[0].Command != "set" || stats[1].FailedCalls != 1 {
+		t.Errorf("test failed: %v", stats)
+	}
+}
+
+func TestAggregateServerInfo(t *testing.T) {
+	info := api.AggregateServerInfo([]api.NodeServerInfo{
+		{Addr: "127.0.0.1:7000", Info: api.ParseInfo(infoText)},
+		{Addr: "127.0.0.1:7001", Info: api.ParseInfo(infoText)},
+	})
+
+	if info.Int64("tcp_port") != 6379 {
+		t.Error("test failed: fields in Server section should not be summed")
+	}
+
+	if info.Int64("used_memory") != 2097152 || info.String("used_memory_human") != "2.00M" {
+		t.Errorf("test failed: %s", info.String("used_memory_human"))
+	}
+
+	if info.Float64("mem_fragmentation_ratio") != 1.5 {
+		t.Errorf("test failed: %s", info.String("mem_fragmentation_ratio"))
+	}
+
+	ks, _ := info.DBKeyspace(0)
+	if ks.Keys != 200 || ks.Expires != 20 || ks.AvgTTL != 1000 {
+		t.Errorf("test failed: %v", ks)
+	}
+
+	stats := info.CommandStats()
+	if stats[1].Command != "get" || stats[1].Calls != 20 || stats[1].UsecPerCall != 10 {
+		t.Errorf("test failed: %v", stats)
+	}
+
+	// ratios are averaged over the nodes reporting them, limits of nodes and hosts are not summed
+	info = api.AggregateServerInfo([]api.NodeServerInfo{
+		{Addr: "127.0.0.1:7000", Info: api.ParseInfo("# Memory\r\nused_memory:100\r\nmaxmemory:1024\r\ntotal_system_memory:4096\r\nmem_fragmentation_ratio:1.20\r\n")},
+		{Addr: "127.0.0.1:7001", Info: api.ParseInfo("# Memory\r\nused_memory:200\r\nmaxmemory:2048\r\ntotal_system_memory:4096\r\nmem_fragmentation_ratio:1.80\r\n")},
+		{Addr: "127.0.0.1:7002", Info: api.ParseInfo("# Memory\r\nused_memory:300\r\nmaxmemory:0\r\n")},
+	})
+
+	if info.Float64("mem_fragmentation_ratio") != 1.5 || info.Int64("used_memory") != 600 {
+		t.Errorf("test failed: %s, %s", info.String("mem_fragmentation_ratio"), info.String("used_memory"))
+	}
+
+	if info.Int64("maxmemory") != 2048 || info.Int64("total_system_memory") != 4096 {
+		t.Errorf("test failed: %s, %s", info.String("maxmemory"), info.String("total_system_memory"))
+	}
+}
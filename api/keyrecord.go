@@ -0,0 +1,242 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/go-redis/redis"
+)
+
+// ZMember is a member of sorted set with its score
+type ZMember struct {
+	Member string  `json:"member"`
+	Score  float64 `json:"score"`
+}
+
+// StreamEntry is an entry of stream, Fields are field value pairs
+type StreamEntry struct {
+	ID     string   `json:"id"`
+	Fields []string `json:"fields"`
+}
+
+// KeyRecord is a key with its full value. Value is a string for string, []string for list and set,
+// []ZMember for zset, map[string]string for hash and []StreamEntry for stream. All the strings
+// including key are base64 encoded if any of them is not valid UTF-8, and Encoding is "base64"
+type KeyRecord struct {
+	Key      string      `json:"key"`
+	Type     string      `json:"type"`
+	TTL      int64       `json:"ttl"`
+	Encoding string      `json:"encoding,omitempty"`
+	Value    interface{} `json:"value"`
+}
+
+// RedisKeyRecord read a key with its value and TTL in milliseconds (-1 means no expiration), the
+// second return value is false if the key doesn't exist
+func RedisKeyRecord(client RedisClient, key string) (KeyRecord, bool, error) {
+	rec := KeyRecord{Key: key, TTL: -1}
+
+	keyType, err := client.Type(key).Result()
+	if err != nil || keyType == "none" {
+		return rec, false, err
+	}
+	rec.Type = keyType
+
+	if rec.TTL, err = client.Do("PTTL", key).Int64(); err != nil {
+		return rec, false, err
+	}
+	if rec.TTL == -2 {
+		return rec, false, nil
+	}
+
+	switch keyType {
+	case "string":
+		value, err := client.Get(key).Result()
+		if err == redis.Nil {
+			return rec, false, nil
+		}
+		rec.Value = value
+		if err != nil {
+			return rec, false, err
+		}
+	case "list":
+		rec.Value, err = client.LRange(key, 0, -1).Result()
+	case "set":
+		rec.Value, err = client.SMembers(key).Result()
+	case "zset":
+		var members []redis.Z
+		if members, err = client.ZRangeWithScores(key, 0, -1).Result(); err == nil {
+			zmembers := make([]ZMember, 0, len(members))
+			for _, m := range members {
+				zmembers = append(zmembers, ZMember{Member: fmt.Sprint(m.Member), Score: m.Score})
+			}
+			rec.Value = zmembers
+		}
+	case "hash":
+		rec.Value, err = RedisHashValues(client, key)
+	case "stream":
+		var res interface{}
+		if res, err = client.Do("XRANGE", key, "-", "+").Result(); err == nil {
+			rec.Value = parseStreamEntries(res)
+		}
+	default:
+		return rec, false, fmt.Errorf("type %s is not supported", keyType)
+	}
+
+	return rec, err == nil, err
+}
+
+// parseStreamEntries parse the reply of XRANGE
+func parseStreamEntries(reply interface{}) []StreamEntry {
+	items, _ := reply.([]interface{})
+	entries := make([]StreamEntry, 0, len(items))
+	for _, item := range items {
+		pair, _ := item.([]interface{})
+		if len(pair) != 2 {
+			continue
+		}
+
+		fields, _ := pair[1].([]interface{})
+		entry := StreamEntry{ID: fmt.Sprint(pair[0]), Fields: make([]string, 0, len(fields))}
+		for _, f := range fields {
+			entry.Fields = append(entry.Fields, fmt.Sprint(f))
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// strings call fn for every string in the record, the strings are replaced by the return values of fn
+func (rec *KeyRecord) strings(fn func(s string) string) {
+	rec.Key = fn(rec.Key)
+	switch v := rec.Value.(type) {
+	case string:
+		rec.Value = fn(v)
+	case []string:
+		values := make([]string, len(v))
+		for i, s := range v {
+			values[i] = fn(s)
+		}
+		rec.Value = values
+	case []ZMember:
+		members := make([]ZMember, len(v))
+		for i, m := range v {
+			members[i] = ZMember{Member: fn(m.Member), Score: m.Score}
+		}
+		rec.Value = members
+	case map[string]string:
+		values := make(map[string]string, len(v))
+		for field, value := range v {
+			values[fn(field)] = fn(value)
+		}
+		rec.Value = values
+	case []StreamEntry:
+		entries := make([]StreamEntry, len(v))
+		for i, e := range v {
+			entries[i] = StreamEntry{ID: e.ID, Fields: make([]string, len(e.Fields))}
+			for j, f := range e.Fields {
+				entries[i].Fields[j] = fn(f)
+			}
+		}
+		rec.Value = entries
+	}
+}
+
+// Binary check whether any string in the record is not valid UTF-8
+func (rec KeyRecord) Binary() bool {
+	binary := false
+	rec.strings(func(s string) string {
+		if !utf8.ValidString(s) {
+			binary = true
+		}
+		return s
+	})
+
+	return binary
+}
+
+// Base64 return the record with all strings base64 encoded
+func (rec KeyRecord) Base64() KeyRecord {
+	rec.strings(func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	})
+	rec.Encoding = "base64"
+
+	return rec
+}
+
+// keyValueWriteBatch is the max count of elements written by a command
+const keyValueWriteBatch = 500
+
+// Commands return the commands creating the key, the key is deleted first if replace is true, and
+// expiration is set if TTL is greater than 0. Elements are written in batches of keyValueWriteBatch
+func (rec KeyRecord) Commands(replace bool) ([][]string, error) {
+	commands := make([][]string, 0)
+	if replace {
+		commands = append(commands, []string{"DEL", rec.Key})
+	}
+
+	switch v := rec.Value.(type) {
+	case string:
+		commands = append(commands, []string{"SET", rec.Key, v})
+	case []string:
+		command := "RPUSH"
+		if rec.Type == "set" {
+			command = "SADD"
+		}
+		commands = append(commands, batchArgs([]string{command, rec.Key}, len(v), func(i int) []string {
+			return []string{v[i]}
+		})...)
+	case []ZMember:
+		commands = append(commands, batchArgs([]string{"ZADD", rec.Key}, len(v), func(i int) []string {
+			return []string{strconv.FormatFloat(v[i].Score, 'f', -1, 64), v[i].Member}
+		})...)
+	case map[string]string:
+		fields := sortedKeys(v)
+		commands = append(commands, batchArgs([]string{"HSET", rec.Key}, len(fields), func(i int) []string {
+			return []string{fields[i], v[fields[i]]}
+		})...)
+	case []StreamEntry:
+		for _, e := range v {
+			commands = append(commands, append([]string{"XADD", rec.Key, e.ID}, e.Fields...))
+		}
+	default:
+		return nil, fmt.Errorf("%s: value of type %s is invalid", rec.Key, rec.Type)
+	}
+
+	if rec.TTL > 0 {
+		commands = append(commands, []string{"PEXPIRE", rec.Key, strconv.FormatInt(rec.TTL, 10)})
+	}
+
+	return commands, nil
+}
+
+// batchArgs split the arguments of count elements into commands prefixed by prefix, every command
+// has at most keyValueWriteBatch elements
+func batchArgs(prefix []string, count int, args func(i int) []string) [][]string {
+	commands := make([][]string, 0)
+	cmd := append([]string{}, prefix...)
+	for i := 0; i < count; i++ {
+		cmd = append(cmd, args(i)...)
+		if (i+1)%keyValueWriteBatch == 0 || i == count-1 {
+			commands = append(commands, cmd)
+			cmd = append([]string{}, prefix...)
+		}
+	}
+
+	return commands
+}
+
+// sortedKeys return the keys of map in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,70 @@
+package api_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestKeyRecordCommands(t *testing.T) {
+	list := api.KeyRecord{Key: "queue", Type: "list", TTL: 5000}
+	values := make([]string, 0)
+	for i := 0; i < 502; i++ {
+		values = append(values, fmt.Sprintf("v%d", i))
+	}
+	list.Value = values
+
+	commands, err := list.Commands(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// elements are written in batches of 500, the order of list is kept
+	if len(commands) != 4 || commands[0][0] != "DEL" || commands[3][0] != "PEXPIRE" || len(commands[1]) != 502 || commands[2][2] != "v500" {
+		t.Fatalf("test failed: %d commands", len(commands))
+	}
+
+	testcases := []struct {
+		rec    api.KeyRecord
+		expect string
+	}{
+		{api.KeyRecord{Key: "rank", Type: "zset", TTL: -1, Value: []api.ZMember{{Member: "tom", Score: 1.5}}}, "[[ZADD rank 1.5 tom]]"},
+		{api.KeyRecord{Key: "user", Type: "hash", TTL: -1, Value: map[string]string{"name": "Tom", "age": "18"}}, "[[HSET user age 18 name Tom]]"},
+		{api.KeyRecord{Key: "events", Type: "stream", TTL: -1, Value: []api.StreamEntry{{ID: "1-0", Fields: []string{"f", "v"}}}}, "[[XADD events 1-0 f v]]"},
+	}
+
+	for _, tc := range testcases {
+		commands, err := tc.rec.Commands(false)
+		if err != nil || fmt.Sprint(commands) != tc.expect {
+			t.Errorf("test failed: %s, expect %s, got %v, %v", tc.rec.Key, tc.expect, commands, err)
+		}
+	}
+
+	if _, err := (api.KeyRecord{Key: "k", Type: "list", Value: 1}).Commands(false); err == nil {
+		t.Error("test failed: value should be invalid")
+	}
+}
+
+func TestNewKeyValue(t *testing.T) {
+	testcases := []struct {
+		rec    api.KeyRecord
+		expect string
+	}{
+		{api.KeyRecord{Key: "queue", Type: "list", TTL: 1500, Value: []string{"a", "b"}}, "1.5s map[0:a 1:b]"},
+		{api.KeyRecord{Key: "tags", Type: "set", TTL: -1, Value: []string{"a"}}, "-1s map[a:]"},
+		{api.KeyRecord{Key: "events", Type: "stream", TTL: -1, Value: []api.StreamEntry{{ID: "1-0", Fields: []string{"f", "v", "g", "w"}}}}, "-1s map[1-0:f=v g=w]"},
+	}
+
+	for _, tc := range testcases {
+		v := api.NewKeyValue(tc.rec)
+		if got := fmt.Sprintf("%s %v", v.TTL, v.Elements); got != tc.expect || v.Type != tc.rec.Type {
+			t.Errorf("test failed: %s, expect %s, got %s", tc.rec.Key, tc.expect, got)
+		}
+	}
+
+	if v := api.NewKeyValue(api.KeyRecord{TTL: -1}); v.TTL != -time.Second {
+		t.Errorf("test failed: %s", v.TTL)
+	}
+}
@@ -0,0 +1,310 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SlotMigration moves hash slots from a master to another by CLUSTER SETSLOT and MIGRATE.
+// It can be run again with the same slots after an interruption, slots already owned by the
+// target are skipped, and the slots in migrating state are continued
+type SlotMigration struct {
+	Source string
+	Target string
+	Slots  []int
+
+	// BatchSize is the count of keys moved by a MIGRATE command
+	BatchSize int
+	// Throttle is the pause between two batches
+	Throttle time.Duration
+	// Timeout is the timeout of MIGRATE command
+	Timeout time.Duration
+	// Username and Password are used for authenticating to the target by MIGRATE
+	Username string
+	Password string
+	// DryRun only counts the keys to be moved, nothing is changed
+	DryRun bool
+}
+
+// SlotMigrationProgress is the progress reported after each batch of a slot migration
+type SlotMigrationProgress struct {
+	Slot       int
+	SlotsDone  int
+	SlotsTotal int
+	KeysMoved  int64
+	Message    string
+}
+
+// ParseSlots parse slots formatted as 0-100,200,300-400
+func ParseSlots(text string) ([]int, error) {
+	slots := make([]int, 0)
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		pair := strings.SplitN(part, "-", 2)
+		start, err := strconv.Atoi(strings.TrimSpace(pair[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid slot %s", part)
+		}
+
+		end := start
+		if len(pair) == 2 {
+			if end, err = strconv.Atoi(strings.TrimSpace(pair[1])); err != nil {
+				return nil, fmt.Errorf("invalid slot %s", part)
+			}
+		}
+
+		if start < 0 || end >= ClusterSlots || start > end {
+			return nil, fmt.Errorf("invalid slot range %s", part)
+		}
+
+		for s := start; s <= end; s++ {
+			if !seen[s] {
+				seen[s] = true
+				slots = append(slots, s)
+			}
+		}
+	}
+
+	if len(slots) == 0 {
+		return nil, errors.New("no slot specified")
+	}
+
+	return slots, nil
+}
+
+// Run execute the migration, progress is called after every batch. The migration is stopped
+// before next batch once stop is closed
+func (m SlotMigration) Run(client RedisClient, progress func(p SlotMigrationProgress), stop <-chan struct{}) error {
+	if m.BatchSize <= 0 {
+		m.BatchSize = 100
+	}
+	if m.Timeout <= 0 {
+		m.Timeout = 5 * time.Second
+	}
+
+	nodes, err := RedisClusterNodes(client)
+	if err != nil {
+		return err
+	}
+
+	source, target, err := migrationNodes(nodes, m.Source, m.Target)
+	if err != nil {
+		return err
+	}
+
+	host, port, err := net.SplitHostPort(target.Addr)
+	if err != nil {
+		return err
+	}
+
+	p := SlotMigrationProgress{SlotsTotal: len(m.Slots)}
+	report := func(slot int, format string, args ...interface{}) {
+		p.Slot = slot
+		p.Message = fmt.Sprintf(format, args...)
+		progress(p)
+	}
+
+	for _, slot := range m.Slots {
+		select {
+		case <-stop:
+			return errors.New("migration stopped, run it again to resume")
+		default:
+		}
+
+		if nodeServesSlot(target, slot) && target.Importing[slot] == "" {
+			// the migration was stopped after the slot was assigned on target, but before on source
+			if source.Migrating[slot] != "" && !m.DryRun {
+				if err := assignSlot(client, nodes, source, target, slot); err != nil {
+					return err
+				}
+
+				p.SlotsDone++
+				report(slot, "slot %d is already served by %s, migrating state of %s is cleared", slot, target.Addr, source.Addr)
+				continue
+			}
+
+			p.SlotsDone++
+			report(slot, "slot %d is already served by %s, skipped", slot, target.Addr)
+			continue
+		}
+
+		if !nodeServesSlot(source, slot) {
+			return fmt.Errorf("slot %d is not served by %s", slot, source.Addr)
+		}
+
+		if m.DryRun {
+			var count int64
+			err := RedisNode(client, source.Addr, func(node RedisClient) (err error) {
+				count, err = node.Do("CLUSTER", "COUNTKEYSINSLOT", slot).Int64()
+				return
+			})
+			if err != nil {
+				return err
+			}
+
+			p.SlotsDone++
+			p.KeysMoved += count
+			report(slot, "[dry-run] slot %d: %d keys would be moved from %s to %s", slot, count, source.Addr, target.Addr)
+			continue
+		}
+
+		// the importing state must be set before migrating, otherwise clients may be redirected
+		// to the target which doesn't accept the slot yet
+		if target.Importing[slot] != source.ID {
+			if err := RedisNode(client, target.Addr, func(node RedisClient) error {
+				return node.Do("CLUSTER", "SETSLOT", slot, "IMPORTING", source.ID).Err()
+			}); err != nil {
+				return err
+			}
+		}
+
+		if source.Migrating[slot] != target.ID {
+			if err := RedisNode(client, source.Addr, func(node RedisClient) error {
+				return node.Do("CLUSTER", "SETSLOT", slot, "MIGRATING", target.ID).Err()
+			}); err != nil {
+				return err
+			}
+		}
+
+		for {
+			var keys []string
+			err := RedisNode(client, source.Addr, func(node RedisClient) (err error) {
+				res, err := node.Do("CLUSTER", "GETKEYSINSLOT", slot, m.BatchSize).Result()
+				if err != nil {
+					return
+				}
+
+				items, _ := res.([]interface{})
+				for _, k := range items {
+					keys = append(keys, fmt.Sprint(k))
+				}
+
+				if len(keys) == 0 {
+					return
+				}
+
+				args := []interface{}{"MIGRATE", host, port, "", 0, int64(m.Timeout / time.Millisecond)}
+				if m.Username != "" {
+					args = append(args, "AUTH2", m.Username, m.Password)
+				} else if m.Password != "" {
+					args = append(args, "AUTH", m.Password)
+				}
+				args = append(args, "KEYS")
+				for _, k := range keys {
+					args = append(args, k)
+				}
+
+				return node.Do(args...).Err()
+			})
+			if err != nil {
+				return fmt.Errorf("migrate slot %d failed: %s", slot, err)
+			}
+
+			if len(keys) == 0 {
+				break
+			}
+
+			p.KeysMoved += int64(len(keys))
+			report(slot, "slot %d: %d keys moved", slot, len(keys))
+
+			select {
+			case <-stop:
+				return fmt.Errorf("migration stopped at slot %d, run it again to resume", slot)
+			case <-time.After(m.Throttle):
+			}
+		}
+
+		if err := assignSlot(client, nodes, source, target, slot); err != nil {
+			return err
+		}
+
+		p.SlotsDone++
+		report(slot, "slot %d is moved to %s", slot, target.Addr)
+	}
+
+	return nil
+}
+
+// assignSlot assign the slot to target on target first, then on source, and finally on other masters
+// so that the new owner is propagated quickly
+func assignSlot(client RedisClient, nodes []ClusterNode, source, target ClusterNode, slot int) error {
+	for _, addr := range append([]string{target.Addr, source.Addr}, otherMasters(nodes, source, target)...) {
+		err := RedisNode(client, addr, func(node RedisClient) error {
+			return node.Do("CLUSTER", "SETSLOT", slot, "NODE", target.ID).Err()
+		})
+		if err != nil && addr != source.Addr && addr != target.Addr {
+			// other masters learn the new owner by gossip anyway
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrationNodes find the source and target masters by their address or id
+func migrationNodes(nodes []ClusterNode, source, target string) (ClusterNode, ClusterNode, error) {
+	find := func(id string) (ClusterNode, error) {
+		for _, n := range nodes {
+			if n.Addr == id || n.ID == id {
+				if !n.IsMaster() {
+					return n, fmt.Errorf("%s is not a master", id)
+				}
+
+				return n, nil
+			}
+		}
+
+		return ClusterNode{}, fmt.Errorf("node %s not found", id)
+	}
+
+	s, err := find(source)
+	if err != nil {
+		return s, s, err
+	}
+
+	t, err := find(target)
+	if err != nil {
+		return s, t, err
+	}
+
+	if s.ID == t.ID {
+		return s, t, errors.New("source and target are the same node")
+	}
+
+	return s, t, nil
+}
+
+// nodeServesSlot check whether the slot is assigned to the node
+func nodeServesSlot(node ClusterNode, slot int) bool {
+	for _, r := range node.Slots {
+		if slot >= r.Start && slot <= r.End {
+			return true
+		}
+	}
+
+	return false
+}
+
+// otherMasters return the address of masters except source and target
+func otherMasters(nodes []ClusterNode, source, target ClusterNode) []string {
+	addrs := make([]string, 0)
+	for _, n := range nodes {
+		if n.IsMaster() && n.ID != source.ID && n.ID != target.ID && !n.Failing() {
+			addrs = append(addrs, n.Addr)
+		}
+	}
+
+	return addrs
+}
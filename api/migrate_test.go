@@ -0,0 +1,25 @@
+package api_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestParseSlots(t *testing.T) {
+	slots, err := api.ParseSlots("0-3, 10,2,16383")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(slots, []int{0, 1, 2, 3, 10, 16383}) {
+		t.Errorf("test failed: got %v", slots)
+	}
+
+	for _, text := range []string{"", "a", "10-5", "16384", "-1", "1-b"} {
+		if _, err := api.ParseSlots(text); err == nil {
+			t.Errorf("test failed: %q should be invalid", text)
+		}
+	}
+}
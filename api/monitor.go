@@ -0,0 +1,251 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mylxsw/redis-tui/config"
+)
+
+// MonitorEvent is a command received by MONITOR
+type MonitorEvent struct {
+	Node       string
+	Time       time.Time
+	DB         int
+	ClientAddr string
+	Args       []string
+}
+
+// Command return the command name in upper case
+func (e MonitorEvent) Command() string {
+	if len(e.Args) == 0 {
+		return ""
+	}
+
+	return strings.ToUpper(e.Args[0])
+}
+
+// String format the event as a line, which is similar to the output of redis-cli monitor
+func (e MonitorEvent) String() string {
+	return fmt.Sprintf("%s [%d %s] %s", e.Time.Format("15:04:05.000000"), e.DB, e.ClientAddr, JoinArgs(e.Args))
+}
+
+// ParseMonitorLine parse a line of MONITOR output, such as
+//
+//	1339518083.107412 [0 127.0.0.1:60866] "keys" "*"
+func ParseMonitorLine(node string, line string) (MonitorEvent, error) {
+	event := MonitorEvent{Node: node}
+
+	begin, end := strings.Index(line, "["), strings.Index(line, "]")
+	if begin < 0 || end < begin {
+		return event, fmt.Errorf("invalid monitor line: %s", line)
+	}
+
+	ts, err := strconv.ParseFloat(strings.TrimSpace(line[:begin]), 64)
+	if err != nil {
+		return event, fmt.Errorf("invalid monitor line: %s", line)
+	}
+	event.Time = time.Unix(0, int64(ts*1e6)*int64(time.Microsecond))
+
+	source := strings.SplitN(line[begin+1:end], " ", 2)
+	event.DB, _ = strconv.Atoi(source[0])
+	if len(source) == 2 {
+		event.ClientAddr = source[1]
+	}
+
+	event.Args = parseQuotedArgs(line[end+1:])
+	return event, nil
+}
+
+// parseQuotedArgs parse arguments quoted by redis, such as "set" "k" "\x00\xff"
+func parseQuotedArgs(text string) []string {
+	args := make([]string, 0)
+	for i := 0; i < len(text); i++ {
+		if text[i] != '"' {
+			continue
+		}
+
+		// find the closing quote, skip the escaped characters
+		j := i + 1
+		for ; j < len(text); j++ {
+			if text[j] == '\\' {
+				j++
+				continue
+			}
+
+			if text[j] == '"' {
+				break
+			}
+		}
+		if j >= len(text) {
+			j = len(text) - 1
+		}
+
+		quoted := text[i : j+1]
+		if arg, err := strconv.Unquote(quoted); err == nil {
+			args = append(args, arg)
+		} else {
+			args = append(args, strings.Trim(quoted, `"`))
+		}
+
+		i = j
+	}
+
+	return args
+}
+
+// Monitor streams the commands processed by server, all masters are monitored in cluster mode
+type Monitor struct {
+	Events chan MonitorEvent
+	Errors chan error
+
+	conns []*RawConn
+	once  sync.Once
+	done  chan struct{}
+}
+
+// StartMonitor open dedicated connections and send MONITOR
+func StartMonitor(client RedisClient, conf config.Config) (*Monitor, error) {
+	var lock sync.Mutex
+	addrs := make([]string, 0)
+	if err := ForEachMaster(client, func(addr string, node RedisClient) error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		addrs = append(addrs, addr)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	m := &Monitor{
+		Events: make(chan MonitorEvent, 1000),
+		Errors: make(chan error, len(addrs)),
+		done:   make(chan struct{}),
+	}
+
+	for _, addr := range addrs {
+		conn, err := DialRawConn(addr, conf)
+		if err != nil {
+			m.Stop()
+			return nil, err
+		}
+
+		if _, err := conn.Do("MONITOR"); err != nil {
+			conn.Close()
+			m.Stop()
+			return nil, err
+		}
+
+		conn.Stream()
+		m.conns = append(m.conns, conn)
+	}
+
+	for _, conn := range m.conns {
+		go m.receive(conn)
+	}
+
+	return m, nil
+}
+
+// receive read lines from the connection until it's closed
+func (m *Monitor) receive(conn *RawConn) {
+	for {
+		reply, err := conn.Receive()
+		if err != nil {
+			m.Errors <- err
+			return
+		}
+
+		line, ok := reply.(string)
+		if !ok {
+			continue
+		}
+
+		event, err := ParseMonitorLine(conn.Addr, line)
+		if err != nil {
+			continue
+		}
+
+		// PING sent by the connection itself to keep it alive is monitored too
+		if event.ClientAddr == conn.LocalAddr() && event.Command() == "PING" {
+			continue
+		}
+
+		select {
+		case m.Events <- event:
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// Stop close all the connections
+func (m *Monitor) Stop() {
+	m.once.Do(func() {
+		close(m.done)
+		for _, conn := range m.conns {
+			conn.Close()
+		}
+	})
+}
+
+// MonitorBuffer is a ring buffer keeps the latest monitor events
+type MonitorBuffer struct {
+	lock   sync.RWMutex
+	events []MonitorEvent
+	start  int
+	size   int
+}
+
+// NewMonitorBuffer create a MonitorBuffer keeps at most capacity events
+func NewMonitorBuffer(capacity int) *MonitorBuffer {
+	return &MonitorBuffer{events: make([]MonitorEvent, capacity)}
+}
+
+// Push add an event into buffer, the oldest event is dropped when it is full
+func (b *MonitorBuffer) Push(event MonitorEvent) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.size < len(b.events) {
+		b.events[(b.start+b.size)%len(b.events)] = event
+		b.size++
+		return
+	}
+
+	b.events[b.start] = event
+	b.start = (b.start + 1) % len(b.events)
+}
+
+// Events return all events in the buffer, the oldest comes first
+func (b *MonitorBuffer) Events() []MonitorEvent {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	events := make([]MonitorEvent, b.size)
+	for i := 0; i < b.size; i++ {
+		events[i] = b.events[(b.start+i)%len(b.events)]
+	}
+
+	return events
+}
+
+// Len return the count of events in buffer
+func (b *MonitorBuffer) Len() int {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	return b.size
+}
+
+// Clear remove all events in buffer
+func (b *MonitorBuffer) Clear() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.start, b.size = 0, 0
+}
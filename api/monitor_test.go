@@ -0,0 +1,42 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestParseMonitorLine(t *testing.T) {
+	event, err := api.ParseMonitorLine("127.0.0.1:6379", `1339518083.107412 [2 127.0.0.1:60866] "set" "key with \"quotes\"" "\x00\xff"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if event.DB != 2 || event.ClientAddr != "127.0.0.1:60866" || event.Command() != "SET" {
+		t.Errorf("test failed: %v", event)
+	}
+
+	if len(event.Args) != 3 || event.Args[1] != `key with "quotes"` || event.Args[2] != "\x00\xff" {
+		t.Errorf("test failed: %q", event.Args)
+	}
+
+	if event.Time.Unix() != 1339518083 {
+		t.Errorf("test failed: %v", event.Time)
+	}
+
+	if _, err := api.ParseMonitorLine("", "OK"); err == nil {
+		t.Error("test failed")
+	}
+}
+
+func TestMonitorBuffer(t *testing.T) {
+	buffer := api.NewMonitorBuffer(3)
+	for i := 0; i < 5; i++ {
+		buffer.Push(api.MonitorEvent{DB: i})
+	}
+
+	events := buffer.Events()
+	if len(events) != 3 || events[0].DB != 2 || events[2].DB != 4 {
+		t.Errorf("test failed: %v", events)
+	}
+}
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis"
+)
+
+// Command targets for executing a command on multiple nodes, a node id (or its prefix) or
+// address can also be used as target, such as @07c37dfe or @127.0.0.1:30001
+const (
+	TargetAll      = "@all"
+	TargetMasters  = "@masters"
+	TargetReplicas = "@replicas"
+)
+
+// NodeResult is the result of a command executed on a node
+type NodeResult struct {
+	Addr   string
+	Result interface{}
+	Err    error
+}
+
+// ParseCommandTarget split the target from command text, the target is empty if the command
+// text doesn't start with @
+func ParseCommandTarget(command string) (target string, rest string) {
+	command = strings.TrimSpace(command)
+	if !strings.HasPrefix(command, "@") {
+		return "", command
+	}
+
+	pair := strings.SplitN(command, " ", 2)
+	if len(pair) == 1 {
+		return pair[0], ""
+	}
+
+	return pair[0], strings.TrimSpace(pair[1])
+}
+
+// RedisExecuteOnNodes execute the command on all the nodes matching the target, results are sorted by node address
+func RedisExecuteOnNodes(client RedisClient, target string, command string) ([]NodeResult, error) {
+	if command == "" {
+		return nil, errors.New("command is empty")
+	}
+
+	forEach, err := targetNodes(client, target)
+	if err != nil {
+		return nil, err
+	}
+
+	args := commandArgs(command)
+
+	var lock sync.Mutex
+	results := make([]NodeResult, 0)
+	err = forEach(func(addr string, node RedisClient) error {
+		res, err := node.Do(args...).Result()
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		results = append(results, NodeResult{Addr: addr, Result: res, Err: err})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no node matches %s", target)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Addr < results[j].Addr
+	})
+
+	return results, nil
+}
+
+// SumNodeResults return the sum of integer results, the second return value is false if any
+// successful result is not an integer, or there is no successful result
+func SumNodeResults(results []NodeResult) (int64, bool) {
+	var total int64
+	succeeded := 0
+	for _, r := range results {
+		if r.Err != nil {
+			continue
+		}
+
+		v, ok := r.Result.(int64)
+		if !ok {
+			return 0, false
+		}
+
+		total += v
+		succeeded++
+	}
+
+	return total, succeeded > 0
+}
+
+// targetNodes return a function iterating the nodes matching the target
+func targetNodes(client RedisClient, target string) (func(fn func(addr string, node RedisClient) error) error, error) {
+	clusterClient, isCluster := client.(*redis.ClusterClient)
+
+	switch target {
+	case TargetAll:
+		return func(fn func(addr string, node RedisClient) error) error {
+			return ForEachNode(client, fn)
+		}, nil
+	case TargetMasters:
+		return func(fn func(addr string, node RedisClient) error) error {
+			return ForEachMaster(client, fn)
+		}, nil
+	case TargetReplicas:
+		if !isCluster {
+			return nil, errors.New("@replicas is only supported in cluster mode")
+		}
+
+		return func(fn func(addr string, node RedisClient) error) error {
+			return clusterClient.ForEachSlave(func(node *redis.Client) error {
+				return fn(node.Options().Addr, node)
+			})
+		}, nil
+	}
+
+	if !isCluster {
+		return nil, fmt.Errorf("unknown target %s, node targets are only supported in cluster mode", target)
+	}
+
+	addr, err := resolveNodeAddr(client, strings.TrimPrefix(target, "@"))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(fn func(addr string, node RedisClient) error) error {
+		return ForEachNode(client, func(nodeAddr string, node RedisClient) error {
+			if nodeAddr != addr {
+				return nil
+			}
+
+			return fn(nodeAddr, node)
+		})
+	}, nil
+}
+
+// RedisNode call fn with the client of node at the address, the client itself is used in standalone mode
+func RedisNode(client RedisClient, addr string, fn func(node RedisClient) error) error {
+	if _, ok := client.(*redis.ClusterClient); !ok {
+		return fn(client)
+	}
+
+	found := false
+	err := ForEachNode(client, func(nodeAddr string, node RedisClient) error {
+		if nodeAddr != addr {
+			return nil
+		}
+
+		found = true
+		return fn(node)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("node %s is not known by client", addr)
+	}
+
+	return nil
+}
+
+// resolveNodeAddr find the address of node by its id, id prefix or address
+func resolveNodeAddr(client RedisClient, id string) (string, error) {
+	if id == "" {
+		return "", errors.New("node id is empty")
+	}
+
+	nodes, err := RedisClusterNodes(client)
+	if err != nil {
+		return "", err
+	}
+
+	matched := make([]ClusterNode, 0)
+	for _, n := range nodes {
+		if n.Addr == id || n.ID == id {
+			return n.Addr, nil
+		}
+
+		if strings.HasPrefix(n.ID, id) {
+			matched = append(matched, n)
+		}
+	}
+
+	switch len(matched) {
+	case 0:
+		return "", fmt.Errorf("node %s not found", id)
+	case 1:
+		return matched[0].Addr, nil
+	}
+
+	return "", fmt.Errorf("node id prefix %s is ambiguous", id)
+}
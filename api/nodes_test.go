@@ -0,0 +1,47 @@
+package api_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestParseCommandTarget(t *testing.T) {
+	cases := map[string][2]string{
+		"get foo":                  {"", "get foo"},
+		"@all info memory":         {"@all", "info memory"},
+		"  @masters   dbsize":      {"@masters", "dbsize"},
+		"@07c37dfe":                {"@07c37dfe", ""},
+		"@127.0.0.1:30001 slowlog": {"@127.0.0.1:30001", "slowlog"},
+	}
+
+	for text, expect := range cases {
+		target, command := api.ParseCommandTarget(text)
+		if target != expect[0] || command != expect[1] {
+			t.Errorf("test failed: %q, expect %q, got %q %q", text, expect, target, command)
+		}
+	}
+}
+
+func TestSumNodeResults(t *testing.T) {
+	results := []api.NodeResult{
+		{Addr: "a", Result: int64(3)},
+		{Addr: "b", Result: int64(4)},
+		{Addr: "c", Err: errors.New("connection refused")},
+	}
+
+	if total, ok := api.SumNodeResults(results); !ok || total != 7 {
+		t.Errorf("test failed: got %d, %v", total, ok)
+	}
+
+	results = append(results, api.NodeResult{Addr: "d", Result: "OK"})
+	if _, ok := api.SumNodeResults(results); ok {
+		t.Error("test failed: non-integer results can not be summed")
+	}
+
+	failed := []api.NodeResult{{Addr: "a", Err: errors.New("connection refused")}}
+	if _, ok := api.SumNodeResults(failed); ok {
+		t.Error("test failed: there is no result to be summed")
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// KeyspaceEvent is a keyspace notification, which is published to __keyspace@<db>__:<key>
+type KeyspaceEvent struct {
+	DB    int
+	Key   string
+	Event string
+}
+
+// removedEvents are the events after which the key doesn't exist any more
+var removedEvents = map[string]bool{
+	"del":         true,
+	"expired":     true,
+	"evicted":     true,
+	"rename_from": true,
+	"move_from":   true,
+}
+
+// Removed check whether the key is removed by the event
+func (e KeyspaceEvent) Removed() bool {
+	return removedEvents[e.Event]
+}
+
+// KeyspaceChannelPattern return the pattern for subscribing keyspace notifications of a database
+func KeyspaceChannelPattern(db int) string {
+	return fmt.Sprintf("__keyspace@%d__:*", db)
+}
+
+// ParseKeyspaceEvent parse a keyspace notification, the second return value is false if the
+// channel is not a keyspace channel
+func ParseKeyspaceEvent(channel string, payload string) (KeyspaceEvent, bool) {
+	const prefix = "__keyspace@"
+	if !strings.HasPrefix(channel, prefix) {
+		return KeyspaceEvent{}, false
+	}
+
+	pair := strings.SplitN(channel[len(prefix):], "__:", 2)
+	if len(pair) != 2 {
+		return KeyspaceEvent{}, false
+	}
+
+	db, err := strconv.Atoi(pair[0])
+	if err != nil {
+		return KeyspaceEvent{}, false
+	}
+
+	return KeyspaceEvent{DB: db, Key: pair[1], Event: payload}, true
+}
+
+// KeyspaceEventsFlags return the notify-keyspace-events flags needed for receiving all keyspace
+// notifications based on the current flags, the second return value is false if nothing need to be changed
+func KeyspaceEventsFlags(current string) (string, bool) {
+	flags := current
+	if !strings.Contains(flags, "K") {
+		flags += "K"
+	}
+
+	// A is an alias for g$lshzxet, it doesn't include key-miss and new key events
+	if !strings.Contains(flags, "A") {
+		flags += "A"
+	}
+
+	return flags, flags != current
+}
+
+// RedisKeyspaceEventsFlags return the notify-keyspace-events config of the server
+func RedisKeyspaceEventsFlags(client RedisClient) (string, error) {
+	return RedisConfigGet(client, "notify-keyspace-events")
+}
+
+// RedisSetKeyspaceEventsFlags update notify-keyspace-events config, it's applied to all masters in cluster mode
+func RedisSetKeyspaceEventsFlags(client RedisClient, flags string) error {
+	return ForEachMaster(client, func(addr string, node RedisClient) error {
+		return node.Do("CONFIG", "SET", "notify-keyspace-events", flags).Err()
+	})
+}
@@ -0,0 +1,32 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestParseKeyspaceEvent(t *testing.T) {
+	e, ok := api.ParseKeyspaceEvent("__keyspace@3__:user:1:__meta", "expired")
+	if !ok || e.DB != 3 || e.Key != "user:1:__meta" || !e.Removed() {
+		t.Errorf("test failed: %v", e)
+	}
+
+	if _, ok := api.ParseKeyspaceEvent("__keyevent@0__:set", "foo"); ok {
+		t.Error("test failed")
+	}
+}
+
+func TestKeyspaceEventsFlags(t *testing.T) {
+	if flags, changed := api.KeyspaceEventsFlags(""); !changed || flags != "KA" {
+		t.Errorf("test failed: %s", flags)
+	}
+
+	if flags, changed := api.KeyspaceEventsFlags("Ex"); !changed || flags != "ExKA" {
+		t.Errorf("test failed: %s", flags)
+	}
+
+	if _, changed := api.KeyspaceEventsFlags("AKE"); changed {
+		t.Error("test failed")
+	}
+}
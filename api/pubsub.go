@@ -0,0 +1,328 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-redis/redis"
+	"github.com/mylxsw/redis-tui/config"
+)
+
+// Subscription kinds, they are also the commands used for subscribing
+const (
+	SubscribeChannel = "SUBSCRIBE"
+	SubscribePattern = "PSUBSCRIBE"
+	SubscribeShard   = "SSUBSCRIBE"
+)
+
+// unsubscribeCommands maps subscription kinds to the commands used for unsubscribing
+var unsubscribeCommands = map[string]string{
+	SubscribeChannel: "UNSUBSCRIBE",
+	SubscribePattern: "PUNSUBSCRIBE",
+	SubscribeShard:   "SUNSUBSCRIBE",
+}
+
+// PubSubMessage is a message received from a subscription
+type PubSubMessage struct {
+	Kind    string
+	Pattern string
+	Channel string
+	Payload string
+	Time    time.Time
+}
+
+// Subscription return the channel or pattern which the message is subscribed by
+func (m PubSubMessage) Subscription() string {
+	if m.Kind == SubscribePattern {
+		return m.Pattern
+	}
+
+	return m.Channel
+}
+
+// PrintablePayload return the payload as it is if it's valid UTF-8 text, or a quoted string otherwise
+func PrintablePayload(payload string) string {
+	if utf8.ValidString(payload) {
+		return payload
+	}
+
+	return strconv.Quote(payload)
+}
+
+// Subscriber subscribes channels on dedicated connections, subscriptions of sharded channels
+// are sent to the master serving the channel in cluster mode
+type Subscriber struct {
+	Messages chan PubSubMessage
+	Errors   chan error
+
+	client RedisClient
+	conf   config.Config
+
+	lock  sync.Mutex
+	conns map[string]*RawConn
+	done  chan struct{}
+}
+
+// NewSubscriber create a Subscriber, connections are created when subscribing
+func NewSubscriber(client RedisClient, conf config.Config) *Subscriber {
+	return &Subscriber{
+		Messages: make(chan PubSubMessage, 1000),
+		Errors:   make(chan error, 10),
+		client:   client,
+		conf:     conf,
+		conns:    make(map[string]*RawConn),
+		done:     make(chan struct{}),
+	}
+}
+
+// Subscribe subscribe a channel, pattern or sharded channel according to kind
+func (s *Subscriber) Subscribe(kind string, channel string) error {
+	conn, err := s.conn(kind, channel)
+	if err != nil {
+		return err
+	}
+
+	return conn.Send(kind, channel)
+}
+
+// Unsubscribe cancel a subscription
+func (s *Subscriber) Unsubscribe(kind string, channel string) error {
+	conn, err := s.conn(kind, channel)
+	if err != nil {
+		return err
+	}
+
+	return conn.Send(unsubscribeCommands[kind], channel)
+}
+
+// SubscribeMasters subscribe on every master in cluster mode, it's used for the messages only published
+// locally on nodes, such as keyspace notifications
+func (s *Subscriber) SubscribeMasters(kind string, channel string) error {
+	return ForEachMaster(s.client, func(addr string, node RedisClient) error {
+		if _, ok := s.client.(*redis.ClusterClient); !ok {
+			addr = s.defaultAddr()
+		}
+
+		conn, err := s.connTo(addr)
+		if err != nil {
+			return err
+		}
+
+		return conn.Send(kind, channel)
+	})
+}
+
+// Close close all the connections
+func (s *Subscriber) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	select {
+	case <-s.done:
+		return
+	default:
+		close(s.done)
+	}
+
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// defaultAddr return the address of the server in config
+func (s *Subscriber) defaultAddr() string {
+	return fmt.Sprintf("%s:%d", s.conf.Host, s.conf.Port)
+}
+
+// conn return the connection for the subscription
+func (s *Subscriber) conn(kind string, channel string) (*RawConn, error) {
+	addr := s.defaultAddr()
+	if _, ok := s.client.(*redis.ClusterClient); ok && kind == SubscribeShard {
+		slotAddr, err := RedisSlotAddr(s.client, KeySlot(channel))
+		if err != nil {
+			return nil, err
+		}
+
+		addr = slotAddr
+	}
+
+	return s.connTo(addr)
+}
+
+// connTo return the connection to addr, a new connection is created if it doesn't exist
+func (s *Subscriber) connTo(addr string) (*RawConn, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if conn, ok := s.conns[addr]; ok {
+		return conn, nil
+	}
+
+	conn, err := DialRawConn(addr, s.conf)
+	if err != nil {
+		return nil, err
+	}
+
+	s.conns[addr] = conn
+	conn.Stream()
+	go s.receive(conn)
+
+	return conn, nil
+}
+
+// receive read messages from the connection until it's closed
+func (s *Subscriber) receive(conn *RawConn) {
+	for {
+		reply, err := conn.Receive()
+		if err != nil {
+			// the connection is removed before reporting, so that subscribing again creates a new one
+			s.lock.Lock()
+			delete(s.conns, conn.Addr)
+			s.lock.Unlock()
+			conn.Close()
+
+			select {
+			case s.Errors <- err:
+			case <-s.done:
+			}
+			return
+		}
+
+		items, ok := reply.([]interface{})
+		if !ok || len(items) < 3 {
+			continue
+		}
+
+		var message PubSubMessage
+		switch fmt.Sprint(items[0]) {
+		case "message":
+			message = PubSubMessage{Kind: SubscribeChannel, Channel: fmt.Sprint(items[1]), Payload: fmt.Sprint(items[2])}
+		case "smessage":
+			message = PubSubMessage{Kind: SubscribeShard, Channel: fmt.Sprint(items[1]), Payload: fmt.Sprint(items[2])}
+		case "pmessage":
+			if len(items) < 4 {
+				continue
+			}
+			message = PubSubMessage{Kind: SubscribePattern, Pattern: fmt.Sprint(items[1]), Channel: fmt.Sprint(items[2]), Payload: fmt.Sprint(items[3])}
+		default:
+			// confirmations of subscribe and unsubscribe
+			continue
+		}
+
+		message.Time = time.Now()
+		select {
+		case s.Messages <- message:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// RedisPublish publish a message to the channel, SPUBLISH is used for sharded channels
+func RedisPublish(client RedisClient, channel string, message string, sharded bool) (int64, error) {
+	if !sharded {
+		return client.Do("PUBLISH", channel, message).Int64()
+	}
+
+	if _, ok := client.(*redis.ClusterClient); !ok {
+		return client.Do("SPUBLISH", channel, message).Int64()
+	}
+
+	addr, err := RedisSlotAddr(client, KeySlot(channel))
+	if err != nil {
+		return 0, err
+	}
+
+	var receivers int64
+	err = ForEachMaster(client, func(nodeAddr string, node RedisClient) error {
+		if nodeAddr != addr {
+			return nil
+		}
+
+		n, err := node.Do("SPUBLISH", channel, message).Int64()
+		receivers = n
+		return err
+	})
+
+	return receivers, err
+}
+
+// PubSubChannel is an active channel with the count of its subscribers
+type PubSubChannel struct {
+	Channel     string
+	Sharded     bool
+	Subscribers int64
+}
+
+// RedisPubSubChannels list active channels matched the pattern by PUBSUB CHANNELS and PUBSUB SHARDCHANNELS,
+// the subscribers are counted by PUBSUB NUMSUB and PUBSUB SHARDNUMSUB and summed across masters in cluster mode
+func RedisPubSubChannels(client RedisClient, pattern string) ([]PubSubChannel, error) {
+	if pattern == "" {
+		pattern = "*"
+	}
+
+	var lock sync.Mutex
+	channels := make(map[string]*PubSubChannel)
+	list := func(node RedisClient, listCmd, numSubCmd string, sharded bool) error {
+		names, err := node.Do("PUBSUB", listCmd, pattern).Result()
+		if err != nil {
+			return err
+		}
+
+		items, _ := names.([]interface{})
+		if len(items) == 0 {
+			return nil
+		}
+
+		args := []interface{}{"PUBSUB", numSubCmd}
+		args = append(args, items...)
+		counts, err := node.Do(args...).Result()
+		if err != nil {
+			return err
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		pairs, _ := counts.([]interface{})
+		for i := 0; i+1 < len(pairs); i += 2 {
+			name := fmt.Sprint(pairs[i])
+			id := fmt.Sprintf("%v:%s", sharded, name)
+			if _, ok := channels[id]; !ok {
+				channels[id] = &PubSubChannel{Channel: name, Sharded: sharded}
+			}
+
+			channels[id].Subscribers += toInt64(pairs[i+1])
+		}
+
+		return nil
+	}
+
+	err := ForEachMaster(client, func(addr string, node RedisClient) error {
+		if err := list(node, "CHANNELS", "NUMSUB", false); err != nil {
+			return err
+		}
+
+		// sharded channels are only supported since redis 7.0, errors are ignored for the older versions
+		_ = list(node, "SHARDCHANNELS", "SHARDNUMSUB", true)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PubSubChannel, 0, len(channels))
+	for _, c := range channels {
+		result = append(result, *c)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Channel < result[j].Channel
+	})
+
+	return result, nil
+}
@@ -0,0 +1,1520 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// opcodes of RDB file
+const (
+	rdbOpcodeSlotInfo      = 0xF4
+	rdbOpcodeFunction2     = 0xF5
+	rdbOpcodeFunctionPreGA = 0xF6
+	rdbOpcodeModuleAux     = 0xF7
+	rdbOpcodeIdle          = 0xF8
+	rdbOpcodeFreq          = 0xF9
+	rdbOpcodeAux           = 0xFA
+	rdbOpcodeResizeDB      = 0xFB
+	rdbOpcodeExpireTimeMs  = 0xFC
+	rdbOpcodeExpireTime    = 0xFD
+	rdbOpcodeSelectDB      = 0xFE
+	rdbOpcodeEOF           = 0xFF
+)
+
+// types of values in RDB file
+const (
+	rdbTypeString           = 0
+	rdbTypeList             = 1
+	rdbTypeSet              = 2
+	rdbTypeZSet             = 3
+	rdbTypeHash             = 4
+	rdbTypeZSet2            = 5
+	rdbTypeModule2          = 7
+	rdbTypeHashZipmap       = 9
+	rdbTypeListZiplist      = 10
+	rdbTypeSetIntset        = 11
+	rdbTypeZSetZiplist      = 12
+	rdbTypeHashZiplist      = 13
+	rdbTypeListQuicklist    = 14
+	rdbTypeStreamListpacks  = 15
+	rdbTypeHashListpack     = 16
+	rdbTypeZSetListpack     = 17
+	rdbTypeListQuicklist2   = 18
+	rdbTypeStreamListpacks2 = 19
+	rdbTypeSetListpack      = 20
+	rdbTypeStreamListpacks3 = 21
+	rdbTypeHashMetadata     = 24
+	rdbTypeHashListpackEx   = 25
+)
+
+// opcodes of values saved by modules
+const (
+	rdbModuleOpcodeEOF    = 0
+	rdbModuleOpcodeSInt   = 1
+	rdbModuleOpcodeUInt   = 2
+	rdbModuleOpcodeFloat  = 3
+	rdbModuleOpcodeDouble = 4
+	rdbModuleOpcodeString = 5
+)
+
+// errRDBModule is returned when a key of module type is read, it's skipped since the value is opaque
+var errRDBModule = errors.New("values of modules are not supported")
+
+// RDB is a snapshot loaded from RDB file
+type RDB struct {
+	Version int
+	// Aux is the auxiliary fields, such as redis-ver and ctime
+	Aux map[string]string
+	// CreatedAt is the time when the snapshot was created, TTLs are relative to it
+	CreatedAt time.Time
+	Databases map[int]*RDBDatabase
+	// Skipped is the count of keys which can't be loaded, such as keys of modules
+	Skipped int
+}
+
+// RDBDatabase is a database in RDB file
+type RDBDatabase struct {
+	Entries map[string]*RDBEntry
+	// Keys are the keys in order
+	Keys []string
+	// Expires is the count of keys with expiration
+	Expires int
+}
+
+// RDBEntry is a key with its value loaded from RDB file
+type RDBEntry struct {
+	Key  string
+	Type string
+	// Encoding is the encoding saved in the file, such as listpack, ziplist, intset and hashtable
+	Encoding string
+	// ExpireAt is the expiration in unix milliseconds, 0 means no expiration
+	ExpireAt int64
+	// Value is the same as KeyRecord.Value, elements of zset are ordered by score and fields of hash are unordered
+	Value interface{}
+	// Idle is the idle time in seconds for LRU policies and Freq is the counter for LFU policies, -1 if not saved
+	Idle int64
+	Freq int
+	// Memory is the estimated memory usage in bytes including the key
+	Memory int64
+
+	// blobs are the sizes of compact encoded blobs, such as listpacks and ziplists
+	blobs []int
+}
+
+// Len return the count of elements, or the length of string
+func (e *RDBEntry) Len() int {
+	switch v := e.Value.(type) {
+	case string:
+		return len(v)
+	case []string:
+		return len(v)
+	case []ZMember:
+		return len(v)
+	case map[string]string:
+		return len(v)
+	case []StreamEntry:
+		return len(v)
+	}
+
+	return 0
+}
+
+// LoadRDB load the RDB file at path, the modification time of file is used if the creation time is not saved
+func LoadRDB(path string) (*RDB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	rdb, err := parseRDB(f, stat.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	if rdb.CreatedAt.IsZero() {
+		rdb.CreatedAt = stat.ModTime()
+	}
+
+	return rdb, nil
+}
+
+// countingReader counts the bytes read from r
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.n += int64(n)
+	return n, err
+}
+
+// rdbParser reads the primitives of RDB file, lengths read from the file are never trusted for
+// allocations: they are checked against the bytes left if the size is known, and buffers grow
+// with the data actually read otherwise
+type rdbParser struct {
+	r    *bufio.Reader
+	src  *countingReader
+	size int64
+}
+
+// ParseRDB parse the RDB file from r, the size of r is used to reject corrupted lengths if r has
+// method Len, such as bytes.Reader
+func ParseRDB(r io.Reader) (*RDB, error) {
+	size := int64(-1)
+	if l, ok := r.(interface{ Len() int }); ok {
+		size = int64(l.Len())
+	}
+
+	return parseRDB(r, size)
+}
+
+// parseRDB parse the RDB file of size bytes from r, size is -1 if unknown
+func parseRDB(r io.Reader, size int64) (*RDB, error) {
+	src := &countingReader{r: r}
+	p := &rdbParser{r: bufio.NewReaderSize(src, 64*1024), src: src, size: size}
+
+	header, err := p.read(9)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RDB file: %s", err)
+	}
+	if string(header[:5]) != "REDIS" {
+		return nil, errors.New("invalid RDB file: magic string REDIS is missing")
+	}
+
+	rdb := &RDB{Aux: make(map[string]string), Databases: make(map[int]*RDBDatabase)}
+	if rdb.Version, err = strconv.Atoi(string(header[5:])); err != nil {
+		return nil, fmt.Errorf("invalid RDB version %q", header[5:])
+	}
+
+	db, expireAt, idle, freq := 0, int64(0), int64(-1), -1
+	for {
+		opcode, err := p.r.ReadByte()
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+
+		switch opcode {
+		case rdbOpcodeEOF:
+			// the checksum is not verified, the file is only browsed
+			for _, d := range rdb.Databases {
+				for key := range d.Entries {
+					d.Keys = append(d.Keys, key)
+				}
+				sort.Strings(d.Keys)
+			}
+
+			if ctime, err := strconv.ParseInt(rdb.Aux["ctime"], 10, 64); err == nil {
+				rdb.CreatedAt = time.Unix(ctime, 0)
+			}
+
+			return rdb, nil
+		case rdbOpcodeSelectDB:
+			n, err := p.readLength()
+			if err != nil {
+				return nil, err
+			}
+			db = int(n)
+		case rdbOpcodeResizeDB:
+			if _, err := p.readLength(); err != nil {
+				return nil, err
+			}
+			if _, err := p.readLength(); err != nil {
+				return nil, err
+			}
+		case rdbOpcodeSlotInfo:
+			for i := 0; i < 3; i++ {
+				if _, err := p.readLength(); err != nil {
+					return nil, err
+				}
+			}
+		case rdbOpcodeAux:
+			key, err := p.readString()
+			if err != nil {
+				return nil, err
+			}
+			if rdb.Aux[key], err = p.readString(); err != nil {
+				return nil, err
+			}
+		case rdbOpcodeExpireTimeMs:
+			if expireAt, err = p.readMillisecondTime(); err != nil {
+				return nil, err
+			}
+		case rdbOpcodeExpireTime:
+			data, err := p.read(4)
+			if err != nil {
+				return nil, err
+			}
+			expireAt = int64(binary.LittleEndian.Uint32(data)) * 1000
+		case rdbOpcodeFreq:
+			b, err := p.r.ReadByte()
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			freq = int(b)
+		case rdbOpcodeIdle:
+			n, err := p.readLength()
+			if err != nil {
+				return nil, err
+			}
+			idle = int64(n)
+		case rdbOpcodeModuleAux:
+			// module id, when opcode and when
+			for i := 0; i < 3; i++ {
+				if _, err := p.readLength(); err != nil {
+					return nil, err
+				}
+			}
+			if err := p.skipModuleValue(); err != nil {
+				return nil, err
+			}
+		case rdbOpcodeFunction2:
+			if _, err := p.readString(); err != nil {
+				return nil, err
+			}
+		case rdbOpcodeFunctionPreGA:
+			return nil, errors.New("functions saved by pre-release versions are not supported")
+		default:
+			key, err := p.readString()
+			if err != nil {
+				return nil, err
+			}
+
+			entry, err := p.readObject(opcode)
+			if err == errRDBModule {
+				rdb.Skipped++
+			} else if err != nil {
+				return nil, fmt.Errorf("key %q: %s", key, err)
+			} else {
+				entry.Key, entry.ExpireAt, entry.Idle, entry.Freq = key, expireAt, idle, freq
+				entry.Memory = estimateMemory(entry)
+
+				d, ok := rdb.Databases[db]
+				if !ok {
+					d = &RDBDatabase{Entries: make(map[string]*RDBEntry)}
+					rdb.Databases[db] = d
+				}
+				d.Entries[key] = entry
+				if expireAt > 0 {
+					d.Expires++
+				}
+			}
+
+			expireAt, idle, freq = 0, -1, -1
+		}
+	}
+}
+
+// unexpectedEOF convert io.EOF to io.ErrUnexpectedEOF, since the file always ends with opcode EOF
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// remaining return the count of bytes not read yet, it's false if the size of file is unknown
+func (p *rdbParser) remaining() (uint64, bool) {
+	if p.size < 0 {
+		return 0, false
+	}
+
+	left := p.size - p.src.n + int64(p.r.Buffered())
+	if left < 0 {
+		left = 0
+	}
+
+	return uint64(left), true
+}
+
+// checkLength return an error if there are less than n bytes left
+func (p *rdbParser) checkLength(n uint64) error {
+	if left, ok := p.remaining(); ok && n > left {
+		return fmt.Errorf("invalid length %d, only %d bytes left", n, left)
+	}
+
+	return nil
+}
+
+func (p *rdbParser) read(n uint64) ([]byte, error) {
+	if err := p.checkLength(n); err != nil {
+		return nil, err
+	}
+
+	if _, ok := p.remaining(); ok || n <= 64*1024 {
+		data := make([]byte, n)
+		if _, err := io.ReadFull(p.r, data); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		return data, nil
+	}
+
+	// the buffer grows with the data read, so that a corrupted length fails at the end of file
+	// instead of allocating memory of the length
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, p.r, int64(n)); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// readLength read a length, the second return value is true if it's the type of a specially encoded string
+func (p *rdbParser) readLengthWithEncoding() (uint64, bool, error) {
+	b, err := p.r.ReadByte()
+	if err != nil {
+		return 0, false, unexpectedEOF(err)
+	}
+
+	switch b >> 6 {
+	case 0:
+		return uint64(b & 0x3f), false, nil
+	case 1:
+		next, err := p.r.ReadByte()
+		if err != nil {
+			return 0, false, unexpectedEOF(err)
+		}
+		return uint64(b&0x3f)<<8 | uint64(next), false, nil
+	case 2:
+		switch b {
+		case 0x80:
+			data, err := p.read(4)
+			if err != nil {
+				return 0, false, err
+			}
+			return uint64(binary.BigEndian.Uint32(data)), false, nil
+		case 0x81:
+			data, err := p.read(8)
+			if err != nil {
+				return 0, false, err
+			}
+			return binary.BigEndian.Uint64(data), false, nil
+		}
+		return 0, false, fmt.Errorf("invalid length encoding 0x%x", b)
+	}
+
+	return uint64(b & 0x3f), true, nil
+}
+
+func (p *rdbParser) readLength() (uint64, error) {
+	n, encoded, err := p.readLengthWithEncoding()
+	if err == nil && encoded {
+		err = errors.New("unexpected string encoding")
+	}
+
+	return n, err
+}
+
+// readCount read the count of elements, every element takes one byte at least
+func (p *rdbParser) readCount() (uint64, error) {
+	n, err := p.readLength()
+	if err != nil {
+		return 0, err
+	}
+
+	return n, p.checkLength(n)
+}
+
+func (p *rdbParser) readString() (string, error) {
+	n, encoded, err := p.readLengthWithEncoding()
+	if err != nil {
+		return "", err
+	}
+
+	if !encoded {
+		data, err := p.read(n)
+		return string(data), err
+	}
+
+	switch n {
+	case 0, 1, 2:
+		size := uint64(1) << n
+		data, err := p.read(size)
+		if err != nil {
+			return "", err
+		}
+
+		var v int64
+		switch size {
+		case 1:
+			v = int64(int8(data[0]))
+		case 2:
+			v = int64(int16(binary.LittleEndian.Uint16(data)))
+		default:
+			v = int64(int32(binary.LittleEndian.Uint32(data)))
+		}
+		return strconv.FormatInt(v, 10), nil
+	case 3:
+		compressedLen, err := p.readLength()
+		if err != nil {
+			return "", err
+		}
+		length, err := p.readLength()
+		if err != nil {
+			return "", err
+		}
+		data, err := p.read(compressedLen)
+		if err != nil {
+			return "", err
+		}
+
+		decompressed, err := lzfDecompress(data, length)
+		return string(decompressed), err
+	}
+
+	return "", fmt.Errorf("invalid string encoding %d", n)
+}
+
+func (p *rdbParser) readMillisecondTime() (int64, error) {
+	data, err := p.read(8)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(binary.LittleEndian.Uint64(data)), nil
+}
+
+// readDouble read a double saved as string for RDB_TYPE_ZSET
+func (p *rdbParser) readDouble() (float64, error) {
+	n, err := p.r.ReadByte()
+	if err != nil {
+		return 0, unexpectedEOF(err)
+	}
+
+	switch n {
+	case 253:
+		return math.NaN(), nil
+	case 254:
+		return math.Inf(1), nil
+	case 255:
+		return math.Inf(-1), nil
+	}
+
+	data, err := p.read(uint64(n))
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseFloat(string(data), 64)
+}
+
+func (p *rdbParser) readBinaryDouble() (float64, error) {
+	data, err := p.read(8)
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Float64frombits(binary.LittleEndian.Uint64(data)), nil
+}
+
+// skipModuleValue skip a value saved by module, which is a series of typed values ended with opcode EOF
+func (p *rdbParser) skipModuleValue() error {
+	for {
+		opcode, err := p.readLength()
+		if err != nil {
+			return err
+		}
+
+		switch opcode {
+		case rdbModuleOpcodeEOF:
+			return nil
+		case rdbModuleOpcodeSInt, rdbModuleOpcodeUInt:
+			_, err = p.readLength()
+		case rdbModuleOpcodeFloat:
+			_, err = p.read(4)
+		case rdbModuleOpcodeDouble:
+			_, err = p.read(8)
+		case rdbModuleOpcodeString:
+			_, err = p.readString()
+		default:
+			return fmt.Errorf("invalid module opcode %d", opcode)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readStrings read n strings
+func (p *rdbParser) readStrings() ([]string, error) {
+	n, err := p.readCount()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0)
+	for i := uint64(0); i < n; i++ {
+		s, err := p.readString()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, s)
+	}
+
+	return values, nil
+}
+
+// readBlob read a string of compact encoding, such as listpack, and decode it into elements
+func (p *rdbParser) readBlob(entry *RDBEntry, decode func(b []byte) ([]string, error)) ([]string, error) {
+	blob, err := p.readString()
+	if err != nil {
+		return nil, err
+	}
+	entry.blobs = append(entry.blobs, len(blob))
+
+	return decode([]byte(blob))
+}
+
+// readObject read the value of type t
+func (p *rdbParser) readObject(t byte) (*RDBEntry, error) {
+	entry := &RDBEntry{}
+
+	var err error
+	var items []string
+	switch t {
+	case rdbTypeString:
+		var s string
+		if s, err = p.readString(); err == nil {
+			entry.Type, entry.Encoding, entry.Value = "string", stringEncoding(s), s
+		}
+		return entry, err
+	case rdbTypeList:
+		entry.Type, entry.Encoding = "list", "linkedlist"
+		entry.Value, err = p.readStrings()
+		return entry, err
+	case rdbTypeSet:
+		entry.Type, entry.Encoding = "set", "hashtable"
+		entry.Value, err = p.readStrings()
+		return entry, err
+	case rdbTypeZSet, rdbTypeZSet2:
+		entry.Type, entry.Encoding = "zset", "skiplist"
+		entry.Value, err = p.readZSet(t == rdbTypeZSet2)
+		return entry, err
+	case rdbTypeHash:
+		entry.Type, entry.Encoding = "hash", "hashtable"
+		items, err = p.readStrings()
+		if err == nil && len(items)%2 != 0 {
+			err = errors.New("invalid length of hash")
+		}
+	case rdbTypeHashMetadata:
+		entry.Type, entry.Encoding = "hash", "hashtable"
+		items, err = p.readHashMetadata()
+	case rdbTypeHashZipmap:
+		entry.Type, entry.Encoding = "hash", "zipmap"
+		items, err = p.readBlob(entry, parseZipmap)
+	case rdbTypeListZiplist:
+		entry.Type, entry.Encoding = "list", "ziplist"
+		items, err = p.readBlob(entry, parseZiplist)
+	case rdbTypeSetIntset:
+		entry.Type, entry.Encoding = "set", "intset"
+		items, err = p.readBlob(entry, parseIntset)
+	case rdbTypeSetListpack:
+		entry.Type, entry.Encoding = "set", "listpack"
+		items, err = p.readBlob(entry, parseListpack)
+	case rdbTypeZSetZiplist:
+		entry.Type, entry.Encoding = "zset", "ziplist"
+		items, err = p.readBlob(entry, parseZiplist)
+	case rdbTypeZSetListpack:
+		entry.Type, entry.Encoding = "zset", "listpack"
+		items, err = p.readBlob(entry, parseListpack)
+	case rdbTypeHashZiplist:
+		entry.Type, entry.Encoding = "hash", "ziplist"
+		items, err = p.readBlob(entry, parseZiplist)
+	case rdbTypeHashListpack:
+		entry.Type, entry.Encoding = "hash", "listpack"
+		items, err = p.readBlob(entry, parseListpack)
+	case rdbTypeHashListpackEx:
+		entry.Type, entry.Encoding = "hash", "listpack"
+		items, err = p.readHashListpackEx(entry)
+	case rdbTypeListQuicklist, rdbTypeListQuicklist2:
+		entry.Type, entry.Encoding = "list", "quicklist"
+		items, err = p.readQuicklist(entry, t == rdbTypeListQuicklist2)
+	case rdbTypeStreamListpacks, rdbTypeStreamListpacks2, rdbTypeStreamListpacks3:
+		entry.Type, entry.Encoding = "stream", "stream"
+		entry.Value, err = p.readStream(entry, t)
+		return entry, err
+	case rdbTypeModule2:
+		if _, err := p.readLength(); err != nil {
+			return nil, err
+		}
+		if err := p.skipModuleValue(); err != nil {
+			return nil, err
+		}
+		return nil, errRDBModule
+	default:
+		return nil, fmt.Errorf("type %d is not supported", t)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch entry.Type {
+	case "list", "set":
+		entry.Value = items
+	case "zset":
+		entry.Value, err = zsetFromPairs(items)
+	case "hash":
+		entry.Value, err = hashFromPairs(items)
+	}
+
+	return entry, err
+}
+
+// stringEncoding return the encoding of string object in memory
+func stringEncoding(s string) string {
+	if _, err := strconv.ParseInt(s, 10, 64); err == nil && len(s) <= 20 {
+		return "int"
+	}
+	if len(s) <= 44 {
+		return "embstr"
+	}
+
+	return "raw"
+}
+
+func (p *rdbParser) readZSet(binaryScore bool) ([]ZMember, error) {
+	n, err := p.readCount()
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]ZMember, 0)
+	for i := uint64(0); i < n; i++ {
+		member, err := p.readString()
+		if err != nil {
+			return nil, err
+		}
+
+		var score float64
+		if binaryScore {
+			score, err = p.readBinaryDouble()
+		} else {
+			score, err = p.readDouble()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, ZMember{Member: member, Score: score})
+	}
+
+	sortZMembers(members)
+	return members, nil
+}
+
+// readHashMetadata read a hash with field expirations (redis 7.4+), the expirations are discarded
+func (p *rdbParser) readHashMetadata() ([]string, error) {
+	if _, err := p.readMillisecondTime(); err != nil {
+		return nil, err
+	}
+
+	n, err := p.readCount()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]string, 0)
+	for i := uint64(0); i < n; i++ {
+		if _, err := p.readLength(); err != nil {
+			return nil, err
+		}
+
+		for j := 0; j < 2; j++ {
+			s, err := p.readString()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, s)
+		}
+	}
+
+	return items, nil
+}
+
+// readHashListpackEx read a hash in listpack with field expirations (redis 7.4+), the elements
+// are triplets of field, value and expiration, the expirations are discarded
+func (p *rdbParser) readHashListpackEx(entry *RDBEntry) ([]string, error) {
+	if _, err := p.readMillisecondTime(); err != nil {
+		return nil, err
+	}
+
+	triplets, err := p.readBlob(entry, parseListpack)
+	if err != nil {
+		return nil, err
+	}
+	if len(triplets)%3 != 0 {
+		return nil, errors.New("invalid length of hash with field expirations")
+	}
+
+	items := make([]string, 0, len(triplets)/3*2)
+	for i := 0; i < len(triplets); i += 3 {
+		items = append(items, triplets[i], triplets[i+1])
+	}
+
+	return items, nil
+}
+
+// quicklist node containers of RDB_TYPE_LIST_QUICKLIST_2
+const (
+	quicklistNodePlain  = 1
+	quicklistNodePacked = 2
+)
+
+func (p *rdbParser) readQuicklist(entry *RDBEntry, v2 bool) ([]string, error) {
+	n, err := p.readCount()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]string, 0)
+	for i := uint64(0); i < n; i++ {
+		if !v2 {
+			values, err := p.readBlob(entry, parseZiplist)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, values...)
+			continue
+		}
+
+		container, err := p.readLength()
+		if err != nil {
+			return nil, err
+		}
+
+		switch container {
+		case quicklistNodePlain:
+			values, err := p.readBlob(entry, func(b []byte) ([]string, error) { return []string{string(b)}, nil })
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, values...)
+		case quicklistNodePacked:
+			values, err := p.readBlob(entry, parseListpack)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, values...)
+		default:
+			return nil, fmt.Errorf("invalid quicklist container %d", container)
+		}
+	}
+
+	return items, nil
+}
+
+// flags of stream entries in listpacks
+const (
+	streamItemFlagDeleted    = 1
+	streamItemFlagSameFields = 2
+)
+
+// readStream read the entries of stream, consumer groups are skipped
+func (p *rdbParser) readStream(entry *RDBEntry, t byte) ([]StreamEntry, error) {
+	n, err := p.readCount()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]StreamEntry, 0)
+	for i := uint64(0); i < n; i++ {
+		nodeKey, err := p.readString()
+		if err != nil {
+			return nil, err
+		}
+		if len(nodeKey) != 16 {
+			return nil, errors.New("invalid master id of stream listpack")
+		}
+		masterMs, masterSeq := binary.BigEndian.Uint64([]byte(nodeKey[:8])), binary.BigEndian.Uint64([]byte(nodeKey[8:]))
+
+		items, err := p.readBlob(entry, parseListpack)
+		if err != nil {
+			return nil, err
+		}
+
+		nodeEntries, err := parseStreamListpack(items, masterMs, masterSeq)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, nodeEntries...)
+	}
+
+	// length, last id, and first id, max deleted id and entries added since RDB_TYPE_STREAM_LISTPACKS_2
+	metas := 3
+	if t >= rdbTypeStreamListpacks2 {
+		metas += 5
+	}
+	for i := 0; i < metas; i++ {
+		if _, err := p.readLength(); err != nil {
+			return nil, err
+		}
+	}
+
+	groups, err := p.readCount()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < groups; i++ {
+		if err := p.skipStreamGroup(t); err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
+func (p *rdbParser) skipStreamGroup(t byte) error {
+	if _, err := p.readString(); err != nil {
+		return err
+	}
+
+	// last id, and entries read since RDB_TYPE_STREAM_LISTPACKS_2
+	metas := 2
+	if t >= rdbTypeStreamListpacks2 {
+		metas++
+	}
+	for i := 0; i < metas; i++ {
+		if _, err := p.readLength(); err != nil {
+			return err
+		}
+	}
+
+	// pending entries: raw id, delivery time and delivery count
+	pending, err := p.readCount()
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i < pending; i++ {
+		if _, err := p.read(16 + 8); err != nil {
+			return err
+		}
+		if _, err := p.readLength(); err != nil {
+			return err
+		}
+	}
+
+	consumers, err := p.readCount()
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i < consumers; i++ {
+		if _, err := p.readString(); err != nil {
+			return err
+		}
+
+		// seen time, and active time since RDB_TYPE_STREAM_LISTPACKS_3
+		times := uint64(8)
+		if t >= rdbTypeStreamListpacks3 {
+			times += 8
+		}
+		if _, err := p.read(times); err != nil {
+			return err
+		}
+
+		pending, err := p.readCount()
+		if err != nil {
+			return err
+		}
+		if _, err := p.read(pending * 16); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseStreamListpack parse the elements of a stream listpack, which starts with the master entry
+// (count, deleted, master fields and a terminating 0), followed by entries of flags, id deltas,
+// fields or values, and the count of elements of the entry
+func parseStreamListpack(items []string, masterMs, masterSeq uint64) ([]StreamEntry, error) {
+	pos := 0
+	next := func() (string, error) {
+		if pos >= len(items) {
+			return "", errors.New("invalid stream listpack")
+		}
+		pos++
+		return items[pos-1], nil
+	}
+	nextInt := func() (int64, error) {
+		s, err := next()
+		if err != nil {
+			return 0, err
+		}
+		return strconv.ParseInt(s, 10, 64)
+	}
+
+	// count and deleted
+	if _, err := nextInt(); err != nil {
+		return nil, err
+	}
+	if _, err := nextInt(); err != nil {
+		return nil, err
+	}
+
+	masterFieldCount, err := nextInt()
+	if err != nil {
+		return nil, err
+	}
+	if masterFieldCount < 0 || masterFieldCount > int64(len(items)-pos) {
+		return nil, fmt.Errorf("invalid count %d of master fields in stream listpack", masterFieldCount)
+	}
+	masterFields := make([]string, 0, masterFieldCount)
+	for i := int64(0); i < masterFieldCount; i++ {
+		field, err := next()
+		if err != nil {
+			return nil, err
+		}
+		masterFields = append(masterFields, field)
+	}
+	if _, err := next(); err != nil {
+		return nil, err
+	}
+
+	entries := make([]StreamEntry, 0)
+	for pos < len(items) {
+		flags, err := nextInt()
+		if err != nil {
+			return nil, err
+		}
+		msDelta, err := nextInt()
+		if err != nil {
+			return nil, err
+		}
+		seqDelta, err := nextInt()
+		if err != nil {
+			return nil, err
+		}
+
+		entry := StreamEntry{ID: fmt.Sprintf("%d-%d", masterMs+uint64(msDelta), masterSeq+uint64(seqDelta))}
+		if flags&streamItemFlagSameFields != 0 {
+			for _, field := range masterFields {
+				value, err := next()
+				if err != nil {
+					return nil, err
+				}
+				entry.Fields = append(entry.Fields, field, value)
+			}
+		} else {
+			count, err := nextInt()
+			if err != nil {
+				return nil, err
+			}
+			if count < 0 || count > int64(len(items)-pos)/2 {
+				return nil, fmt.Errorf("invalid count %d of fields in stream listpack", count)
+			}
+			for i := int64(0); i < count*2; i++ {
+				s, err := next()
+				if err != nil {
+					return nil, err
+				}
+				entry.Fields = append(entry.Fields, s)
+			}
+		}
+
+		// count of elements of the entry
+		if _, err := next(); err != nil {
+			return nil, err
+		}
+
+		if flags&streamItemFlagDeleted == 0 {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// zsetFromPairs create members of zset from pairs of member and score
+func zsetFromPairs(items []string) ([]ZMember, error) {
+	if len(items)%2 != 0 {
+		return nil, errors.New("invalid length of zset")
+	}
+
+	members := make([]ZMember, 0, len(items)/2)
+	for i := 0; i < len(items); i += 2 {
+		score, err := strconv.ParseFloat(items[i+1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid score %q", items[i+1])
+		}
+		members = append(members, ZMember{Member: items[i], Score: score})
+	}
+
+	sortZMembers(members)
+	return members, nil
+}
+
+// sortZMembers sort the members by score, then by member like redis does
+func sortZMembers(members []ZMember) {
+	sort.SliceStable(members, func(i, j int) bool {
+		if members[i].Score != members[j].Score {
+			return members[i].Score < members[j].Score
+		}
+		return members[i].Member < members[j].Member
+	})
+}
+
+// hashFromPairs create hash from pairs of field and value
+func hashFromPairs(items []string) (map[string]string, error) {
+	if len(items)%2 != 0 {
+		return nil, errors.New("invalid length of hash")
+	}
+
+	values := make(map[string]string, len(items)/2)
+	for i := 0; i < len(items); i += 2 {
+		values[items[i]] = items[i+1]
+	}
+
+	return values, nil
+}
+
+// lzfDecompress decompress data compressed by LZF into length bytes, the output is not allocated
+// up front since length is read from the file
+func lzfDecompress(in []byte, length uint64) ([]byte, error) {
+	out := make([]byte, 0)
+	for i := 0; i < len(in); {
+		if uint64(len(out)) > length {
+			return nil, fmt.Errorf("invalid lzf data, expect %d bytes, got more", length)
+		}
+
+		ctrl := int(in[i])
+		i++
+
+		if ctrl < 32 {
+			// literal run of ctrl+1 bytes
+			n := ctrl + 1
+			if i+n > len(in) {
+				return nil, errors.New("invalid lzf data")
+			}
+			out = append(out, in[i:i+n]...)
+			i += n
+			continue
+		}
+
+		// back reference
+		n := ctrl >> 5
+		if n == 7 {
+			if i >= len(in) {
+				return nil, errors.New("invalid lzf data")
+			}
+			n += int(in[i])
+			i++
+		}
+		if i >= len(in) {
+			return nil, errors.New("invalid lzf data")
+		}
+
+		ref := len(out) - (ctrl&0x1f)<<8 - int(in[i]) - 1
+		i++
+		if ref < 0 {
+			return nil, errors.New("invalid lzf back reference")
+		}
+		for j := 0; j < n+2; j++ {
+			out = append(out, out[ref+j])
+		}
+	}
+
+	if uint64(len(out)) != length {
+		return nil, fmt.Errorf("invalid lzf data, expect %d bytes, got %d", length, len(out))
+	}
+
+	return out, nil
+}
+
+// errInvalidBlob is returned when a compact encoded blob is truncated or corrupted
+var errInvalidBlob = errors.New("invalid compact encoded value")
+
+// parseZiplist parse the elements of a ziplist: zlbytes(4) zltail(4) zllen(2) entries... 0xFF, every
+// entry has the length of previous entry, the encoding and the data
+func parseZiplist(b []byte) ([]string, error) {
+	items := make([]string, 0)
+	pos := 10
+	for {
+		if pos >= len(b) {
+			return nil, errInvalidBlob
+		}
+		if b[pos] == 0xFF {
+			return items, nil
+		}
+
+		if b[pos] < 254 {
+			pos++
+		} else {
+			pos += 5
+		}
+		if pos >= len(b) {
+			return nil, errInvalidBlob
+		}
+
+		enc := b[pos]
+		var n, header int
+		switch enc >> 6 {
+		case 0:
+			n, header = int(enc&0x3f), 1
+		case 1:
+			if pos+2 > len(b) {
+				return nil, errInvalidBlob
+			}
+			n, header = int(enc&0x3f)<<8|int(b[pos+1]), 2
+		case 2:
+			if pos+5 > len(b) {
+				return nil, errInvalidBlob
+			}
+			n, header = int(binary.BigEndian.Uint32(b[pos+1:pos+5])), 5
+		default:
+			var size int
+			switch enc {
+			case 0xC0:
+				size = 2
+			case 0xD0:
+				size = 4
+			case 0xE0:
+				size = 8
+			case 0xF0:
+				size = 3
+			case 0xFE:
+				size = 1
+			default:
+				if enc < 0xF1 || enc > 0xFD {
+					return nil, errInvalidBlob
+				}
+				// 4 bits immediate integer between 0 and 12
+				items = append(items, strconv.Itoa(int(enc&0x0f)-1))
+				pos++
+				continue
+			}
+
+			if pos+1+size > len(b) {
+				return nil, errInvalidBlob
+			}
+			items = append(items, strconv.FormatInt(littleEndianInt(b[pos+1:pos+1+size]), 10))
+			pos += 1 + size
+			continue
+		}
+
+		if pos+header+n > len(b) {
+			return nil, errInvalidBlob
+		}
+		items = append(items, string(b[pos+header:pos+header+n]))
+		pos += header + n
+	}
+}
+
+// littleEndianInt decode a signed little endian integer of 1 to 8 bytes
+func littleEndianInt(b []byte) int64 {
+	var v uint64
+	for i := len(b) - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+
+	shift := uint(64 - 8*len(b))
+	return int64(v<<shift) >> shift
+}
+
+// parseListpack parse the elements of a listpack: total bytes(4) count(2) entries... 0xFF, every
+// entry has the encoding, the data and the length of entry for traversing backwards
+func parseListpack(b []byte) ([]string, error) {
+	items := make([]string, 0)
+	pos := 6
+	for {
+		if pos >= len(b) {
+			return nil, errInvalidBlob
+		}
+
+		enc := b[pos]
+		if enc == 0xFF {
+			return items, nil
+		}
+
+		start := pos
+		switch {
+		case enc&0x80 == 0:
+			// 7 bits unsigned integer
+			items = append(items, strconv.Itoa(int(enc)))
+			pos++
+		case enc&0xC0 == 0x80:
+			// string of 6 bits length
+			n := int(enc & 0x3f)
+			if pos+1+n > len(b) {
+				return nil, errInvalidBlob
+			}
+			items = append(items, string(b[pos+1:pos+1+n]))
+			pos += 1 + n
+		case enc&0xE0 == 0xC0:
+			// 13 bits signed integer
+			if pos+2 > len(b) {
+				return nil, errInvalidBlob
+			}
+			v := int(enc&0x1f)<<8 | int(b[pos+1])
+			if v >= 1<<12 {
+				v -= 1 << 13
+			}
+			items = append(items, strconv.Itoa(v))
+			pos += 2
+		case enc&0xF0 == 0xE0:
+			// string of 12 bits length
+			if pos+2 > len(b) {
+				return nil, errInvalidBlob
+			}
+			n := int(enc&0x0f)<<8 | int(b[pos+1])
+			if pos+2+n > len(b) {
+				return nil, errInvalidBlob
+			}
+			items = append(items, string(b[pos+2:pos+2+n]))
+			pos += 2 + n
+		case enc == 0xF0:
+			// string of 32 bits length
+			if pos+5 > len(b) {
+				return nil, errInvalidBlob
+			}
+			n := int(binary.LittleEndian.Uint32(b[pos+1 : pos+5]))
+			if n < 0 || pos+5+n > len(b) {
+				return nil, errInvalidBlob
+			}
+			items = append(items, string(b[pos+5:pos+5+n]))
+			pos += 5 + n
+		case enc >= 0xF1 && enc <= 0xF4:
+			// 16, 24, 32 and 64 bits signed integers
+			size := []int{2, 3, 4, 8}[enc-0xF1]
+			if pos+1+size > len(b) {
+				return nil, errInvalidBlob
+			}
+			items = append(items, strconv.FormatInt(littleEndianInt(b[pos+1:pos+1+size]), 10))
+			pos += 1 + size
+		default:
+			return nil, errInvalidBlob
+		}
+
+		pos += listpackBacklenSize(pos - start)
+	}
+}
+
+// listpackBacklenSize return the size of the backward length of an entry of l bytes
+func listpackBacklenSize(l int) int {
+	switch {
+	case l <= 127:
+		return 1
+	case l < 16383:
+		return 2
+	case l < 2097151:
+		return 3
+	case l < 268435455:
+		return 4
+	}
+
+	return 5
+}
+
+// parseIntset parse the elements of an intset: encoding(4) length(4) integers of encoding bytes
+func parseIntset(b []byte) ([]string, error) {
+	if len(b) < 8 {
+		return nil, errInvalidBlob
+	}
+
+	size := int(binary.LittleEndian.Uint32(b[:4]))
+	n := int(binary.LittleEndian.Uint32(b[4:8]))
+	if size != 2 && size != 4 && size != 8 || 8+size*n > len(b) {
+		return nil, errInvalidBlob
+	}
+
+	items := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		items = append(items, strconv.FormatInt(littleEndianInt(b[8+i*size:8+(i+1)*size]), 10))
+	}
+
+	return items, nil
+}
+
+// parseZipmap parse the elements of a zipmap: zmlen(1) entries... 0xFF, every entry has the length
+// and data of key, the length, free bytes and data of value
+func parseZipmap(b []byte) ([]string, error) {
+	items := make([]string, 0)
+	pos := 1
+
+	readLen := func() (int, bool, error) {
+		if pos >= len(b) {
+			return 0, false, errInvalidBlob
+		}
+
+		switch l := b[pos]; {
+		case l == 0xFF:
+			return 0, true, nil
+		case l < 254:
+			pos++
+			return int(l), false, nil
+		}
+
+		if pos+5 > len(b) {
+			return 0, false, errInvalidBlob
+		}
+		n := int(binary.LittleEndian.Uint32(b[pos+1 : pos+5]))
+		pos += 5
+		return n, false, nil
+	}
+
+	for {
+		n, end, err := readLen()
+		if err != nil || end {
+			return items, err
+		}
+		if pos+n > len(b) {
+			return nil, errInvalidBlob
+		}
+		key := string(b[pos : pos+n])
+		pos += n
+
+		n, end, err = readLen()
+		if err != nil || end || pos >= len(b) {
+			return nil, errInvalidBlob
+		}
+		free := int(b[pos])
+		pos++
+		if pos+n+free > len(b) {
+			return nil, errInvalidBlob
+		}
+
+		items = append(items, key, string(b[pos:pos+n]))
+		pos += n + free
+	}
+}
+
+// sizes of structures in memory for estimation
+const (
+	memoryObject    = 16
+	memoryDictEntry = 24
+	memoryDict      = 56
+	memoryQuicklist = 40
+	memoryQuickNode = 32
+	memorySkiplist  = 32
+	memoryStream    = 64
+	memoryRaxNode   = 48
+	memoryPointer   = 8
+	// memorySkipNode is a node of skiplist with 1.33 levels in average
+	memorySkipNode = 48
+)
+
+// estimateMemory estimate the memory usage of entry in bytes, including the key in the main dict.
+// It's an approximation of redis on 64 bits with jemalloc, like MEMORY USAGE without sampling
+func estimateMemory(e *RDBEntry) int64 {
+	size := mallocSize(memoryDictEntry) + sdsSize(len(e.Key)) + memoryPointer
+	if e.ExpireAt > 0 {
+		size += mallocSize(memoryDictEntry) + memoryPointer
+	}
+
+	blobs := int64(0)
+	for _, b := range e.blobs {
+		blobs += mallocSize(int64(b))
+	}
+
+	switch v := e.Value.(type) {
+	case string:
+		switch e.Encoding {
+		case "int":
+			size += mallocSize(memoryObject)
+		case "embstr":
+			size += mallocSize(int64(memoryObject + 3 + len(v) + 1))
+		default:
+			size += mallocSize(memoryObject) + sdsSize(len(v))
+		}
+		return size
+	case []StreamEntry:
+		return size + mallocSize(memoryObject) + mallocSize(memoryStream) + blobs + int64(len(e.blobs))*mallocSize(memoryRaxNode)
+	}
+
+	size += mallocSize(memoryObject)
+	switch e.Encoding {
+	case "quicklist":
+		return size + mallocSize(memoryQuicklist) + int64(len(e.blobs))*mallocSize(memoryQuickNode) + blobs
+	case "linkedlist":
+		// lists are converted to quicklist when loaded, elements are packed in listpacks
+		packed := int64(0)
+		for _, s := range e.Value.([]string) {
+			packed += int64(len(s)) + 2
+		}
+		return size + mallocSize(memoryQuicklist) + mallocSize(memoryQuickNode) + mallocSize(packed+7)
+	case "hashtable", "skiplist":
+		n := e.Len()
+		size += mallocSize(memoryDict) + mallocSize(int64(nextPower(n))*memoryPointer) + int64(n)*mallocSize(memoryDictEntry)
+
+		switch v := e.Value.(type) {
+		case []string:
+			for _, s := range v {
+				size += sdsSize(len(s))
+			}
+		case map[string]string:
+			for field, value := range v {
+				size += sdsSize(len(field)) + sdsSize(len(value))
+			}
+		case []ZMember:
+			size += mallocSize(memorySkiplist)
+			for _, m := range v {
+				size += sdsSize(len(m.Member)) + mallocSize(memorySkipNode)
+			}
+		}
+		return size
+	}
+
+	// compact encodings are stored as the blobs
+	return size + blobs
+}
+
+// sdsSize return the allocated size of sds string of length n
+func sdsSize(n int) int64 {
+	header := 3
+	switch {
+	case n >= 1<<32:
+		header = 17
+	case n >= 1<<16:
+		header = 9
+	case n >= 1<<8:
+		header = 5
+	}
+
+	return mallocSize(int64(header + n + 1))
+}
+
+// mallocSize return the size class of jemalloc for n bytes
+func mallocSize(n int64) int64 {
+	if n <= 8 {
+		return 8
+	}
+	if n <= 128 {
+		return (n + 15) / 16 * 16
+	}
+
+	// 4 size classes for every power of 2
+	p := int64(128)
+	for p*2 < n {
+		p *= 2
+	}
+	step := p / 4
+
+	return (n + step - 1) / step * step
+}
+
+// nextPower return the smallest power of 2 not less than n, the buckets of dict are allocated by it
+func nextPower(n int) int {
+	p := 4
+	for p < n {
+		p *= 2
+	}
+
+	return p
+}
@@ -0,0 +1,412 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+// rdbWriter builds RDB files for tests
+type rdbWriter struct {
+	bytes.Buffer
+}
+
+func (w *rdbWriter) length(n int) {
+	switch {
+	case n < 1<<6:
+		w.WriteByte(byte(n))
+	case n < 1<<14:
+		w.WriteByte(byte(0x40 | n>>8))
+		w.WriteByte(byte(n))
+	default:
+		w.WriteByte(0x80)
+		_ = binary.Write(w, binary.BigEndian, uint32(n))
+	}
+}
+
+func (w *rdbWriter) str(s string) {
+	w.length(len(s))
+	w.WriteString(s)
+}
+
+func (w *rdbWriter) key(t byte, key string) {
+	w.WriteByte(t)
+	w.str(key)
+}
+
+// listpack encode the elements in listpack, strings shorter than 64 bytes and integers between 0 and 127 are supported
+func listpack(items ...interface{}) string {
+	var body bytes.Buffer
+	for _, item := range items {
+		var entry []byte
+		switch v := item.(type) {
+		case int:
+			entry = []byte{byte(v)}
+		case string:
+			entry = append([]byte{byte(0x80 | len(v))}, v...)
+		}
+		body.Write(entry)
+		body.WriteByte(byte(len(entry)))
+	}
+
+	var lp bytes.Buffer
+	_ = binary.Write(&lp, binary.LittleEndian, uint32(body.Len()+7))
+	_ = binary.Write(&lp, binary.LittleEndian, uint16(len(items)))
+	lp.Write(body.Bytes())
+	lp.WriteByte(0xFF)
+
+	return lp.String()
+}
+
+// ziplist encode the elements in ziplist, strings shorter than 64 bytes and integers between 0 and 12 are supported
+func ziplist(items ...interface{}) string {
+	var body bytes.Buffer
+	prev := 0
+	for _, item := range items {
+		var entry []byte
+		switch v := item.(type) {
+		case int:
+			entry = []byte{0xF1 + byte(v)}
+		case string:
+			entry = append([]byte{byte(len(v))}, v...)
+		}
+		body.WriteByte(byte(prev))
+		body.Write(entry)
+		prev = len(entry) + 1
+	}
+
+	var zl bytes.Buffer
+	_ = binary.Write(&zl, binary.LittleEndian, uint32(body.Len()+11))
+	_ = binary.Write(&zl, binary.LittleEndian, uint32(0))
+	_ = binary.Write(&zl, binary.LittleEndian, uint16(len(items)))
+	zl.Write(body.Bytes())
+	zl.WriteByte(0xFF)
+
+	return zl.String()
+}
+
+const rdbCreatedAt = int64(1700000000)
+
+func testRDB() []byte {
+	w := &rdbWriter{}
+	w.WriteString("REDIS0011")
+
+	w.WriteByte(0xFA)
+	w.str("redis-ver")
+	w.str("7.2.4")
+	w.WriteByte(0xFA)
+	w.str("ctime")
+	w.str(fmt.Sprint(rdbCreatedAt))
+
+	w.WriteByte(0xFE)
+	w.length(0)
+	w.WriteByte(0xFB)
+	w.length(11)
+	w.length(1)
+
+	w.WriteByte(0xFC)
+	_ = binary.Write(w, binary.LittleEndian, uint64(rdbCreatedAt*1000+60000))
+	w.key(0, "session")
+	w.str("abc")
+
+	w.WriteByte(0xF9)
+	w.WriteByte(5)
+	w.key(0, "counter")
+	w.Write([]byte{0xC0, 123})
+
+	// "a" followed by a back reference of 9 bytes
+	w.key(0, "lzf")
+	w.WriteByte(0xC3)
+	w.length(5)
+	w.length(10)
+	w.Write([]byte{0x00, 'a', 0xE0, 0x00, 0x00})
+
+	w.key(18, "queue")
+	w.length(1)
+	w.length(2)
+	w.str(listpack("a", "b", 7))
+
+	w.key(11, "ids")
+	var intset bytes.Buffer
+	for _, v := range []interface{}{uint32(2), uint32(3), int16(-1), int16(2), int16(300)} {
+		_ = binary.Write(&intset, binary.LittleEndian, v)
+	}
+	w.str(intset.String())
+
+	w.key(17, "rank")
+	w.str(listpack("tom", 2, "amy", 1))
+
+	w.key(16, "user:1")
+	w.str(listpack("name", "Tom", "age", 18))
+
+	w.key(13, "user:2")
+	w.str(ziplist("name", "Amy", "level", 3))
+
+	w.key(5, "scores")
+	w.length(1)
+	w.str("x")
+	_ = binary.Write(w, binary.LittleEndian, math.Float64bits(1.5))
+
+	w.key(1, "old")
+	w.length(2)
+	w.str("x")
+	w.str("y")
+
+	w.key(15, "events")
+	w.length(1)
+	nodeKey := make([]byte, 16)
+	binary.BigEndian.PutUint64(nodeKey, 1000)
+	w.str(string(nodeKey))
+	w.str(listpack(
+		// master entry: count, deleted, fields and the terminator
+		2, 0, 1, "f", 0,
+		// entry with the same fields as master
+		2, 0, 0, "v", 4,
+		// entry with its own fields
+		0, 1, 0, 1, "g", "w", 6,
+		// deleted entry
+		3, 2, 0, "x", 4,
+	))
+	w.length(2)
+	w.length(1001)
+	w.length(0)
+	w.length(0)
+
+	// key of module is skipped
+	w.key(7, "module")
+	w.length(12345)
+	w.length(2)
+	w.length(5)
+	w.length(0)
+
+	w.WriteByte(0xFE)
+	w.length(1)
+	w.key(0, "other")
+	w.str("x")
+
+	w.WriteByte(0xFF)
+	w.Write(make([]byte, 8))
+
+	return w.Bytes()
+}
+
+func TestParseRDB(t *testing.T) {
+	rdb, err := api.ParseRDB(bytes.NewReader(testRDB()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rdb.Version != 11 || rdb.Aux["redis-ver"] != "7.2.4" || rdb.CreatedAt.Unix() != rdbCreatedAt || rdb.Skipped != 1 {
+		t.Fatalf("test failed: %+v", rdb)
+	}
+
+	db := rdb.Databases[0]
+	if len(db.Keys) != 11 || db.Expires != 1 || len(rdb.Databases[1].Keys) != 1 {
+		t.Fatalf("test failed: %v", db.Keys)
+	}
+
+	testcases := map[string]string{
+		"session": `string embstr "abc"`,
+		"counter": `string int "123"`,
+		"lzf":     `string embstr "aaaaaaaaaa"`,
+		"queue":   `list quicklist []string{"a", "b", "7"}`,
+		"ids":     `set intset []string{"-1", "2", "300"}`,
+		"rank":    `zset listpack []api.ZMember{api.ZMember{Member:"amy", Score:1}, api.ZMember{Member:"tom", Score:2}}`,
+		"user:1":  `hash listpack map[string]string{"age":"18", "name":"Tom"}`,
+		"user:2":  `hash ziplist map[string]string{"level":"3", "name":"Amy"}`,
+		"scores":  `zset skiplist []api.ZMember{api.ZMember{Member:"x", Score:1.5}}`,
+		"old":     `list linkedlist []string{"x", "y"}`,
+		"events":  `stream stream []api.StreamEntry{api.StreamEntry{ID:"1000-0", Fields:[]string{"f", "v"}}, api.StreamEntry{ID:"1001-0", Fields:[]string{"g", "w"}}}`,
+	}
+
+	for key, expect := range testcases {
+		e := db.Entries[key]
+		if e == nil {
+			t.Errorf("test failed: %s not found", key)
+			continue
+		}
+
+		if got := fmt.Sprintf("%s %s %#v", e.Type, e.Encoding, e.Value); got != expect {
+			t.Errorf("test failed: %s, expect %s, got %s", key, expect, got)
+		}
+
+		if e.Memory <= 0 {
+			t.Errorf("test failed: memory of %s is not estimated", key)
+		}
+	}
+
+	if e := db.Entries["counter"]; e.Freq != 5 || e.Idle != -1 {
+		t.Errorf("test failed: %+v", e)
+	}
+
+	if _, err := api.ParseRDB(bytes.NewReader(testRDB()[:200])); err == nil {
+		t.Error("test failed: truncated file should be invalid")
+	}
+}
+
+// testRDBFile write the test RDB into a temporary file, the file is removed by the returned func
+func testRDBFile(t *testing.T) (string, func()) {
+	f, err := ioutil.TempFile("", "dump-*.rdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = f.Write(testRDB())
+	_ = f.Close()
+
+	return f.Name(), func() { os.Remove(f.Name()) }
+}
+
+func TestRDBClient(t *testing.T) {
+	path, remove := testRDBFile(t)
+	defer remove()
+
+	client := api.NewRDBClient(path, 0)
+
+	if keys, err := client.Keys("user:[12]").Result(); err != nil || strings.Join(keys, ",") != "user:1,user:2" {
+		t.Errorf("test failed: %v, %v", keys, err)
+	}
+
+	// all keys are returned by scanning round by round
+	keys, err := api.KeysWithLimit(client, "*", -1)
+	if err != nil || len(keys) != 11 {
+		t.Errorf("test failed: %v, %v", keys, err)
+	}
+
+	if ttl := client.TTL("session").Val(); ttl != time.Minute {
+		t.Errorf("test failed: %s", ttl)
+	}
+	if ttl := client.TTL("counter").Val(); ttl != -time.Second {
+		t.Errorf("test failed: %s", ttl)
+	}
+
+	if values := client.LRange("queue", 1, -1).Val(); strings.Join(values, ",") != "b,7" {
+		t.Errorf("test failed: %v", values)
+	}
+
+	if z := client.ZRangeWithScores("rank", 0, 0).Val(); len(z) != 1 || z[0].Member != "amy" {
+		t.Errorf("test failed: %v", z)
+	}
+
+	if _, err := client.Get("queue").Result(); err == nil || !strings.HasPrefix(err.Error(), "WRONGTYPE") {
+		t.Errorf("test failed: %v", err)
+	}
+
+	if res, err := client.Do("XRANGE", "events", "1001", "+").Result(); err != nil || fmt.Sprint(res) != "[[1001-0 [g w]]]" {
+		t.Errorf("test failed: %v, %v", res, err)
+	}
+
+	if encoding, _ := client.Do("OBJECT", "ENCODING", "ids").String(); encoding != "intset" {
+		t.Errorf("test failed: %s", encoding)
+	}
+
+	if freq, _ := client.Do("OBJECT", "FREQ", "counter").Int64(); freq != 5 {
+		t.Errorf("test failed: %d", freq)
+	}
+
+	if usage, _ := client.Do("MEMORY", "USAGE", "user:1").Int64(); usage <= 0 {
+		t.Errorf("test failed: %d", usage)
+	}
+
+	if err := client.Do("SET", "name", "Tom").Err(); err == nil {
+		t.Error("test failed: writing should be rejected")
+	}
+
+	databases, err := api.RedisDatabases(client)
+	if err != nil || len(databases) != 16 || databases[0].Keys != 11 || databases[1].Keys != 1 {
+		t.Errorf("test failed: %v, %v", databases, err)
+	}
+
+	if keys := api.NewRDBClient(path, 1).Keys("*").Val(); len(keys) != 1 || keys[0] != "other" {
+		t.Errorf("test failed: %v", keys)
+	}
+}
+
+func TestParseCorruptedRDB(t *testing.T) {
+	corrupt := func(write func(w *rdbWriter)) []byte {
+		w := &rdbWriter{}
+		w.WriteString("REDIS0011")
+		write(w)
+		w.WriteByte(0xFF)
+		w.Write(make([]byte, 8))
+		return w.Bytes()
+	}
+
+	huge := func(w *rdbWriter) {
+		w.WriteByte(0x80)
+		_ = binary.Write(w, binary.BigEndian, uint32(0xFFFFFFFF))
+	}
+
+	testcases := map[string][]byte{
+		"negative count of master fields": corrupt(func(w *rdbWriter) {
+			w.key(15, "events")
+			w.length(1)
+			w.str(string(make([]byte, 16)))
+			w.str(listpack(1, 0, "-1", "f", 0))
+		}),
+		"too many master fields": corrupt(func(w *rdbWriter) {
+			w.key(15, "events")
+			w.length(1)
+			w.str(string(make([]byte, 16)))
+			w.str(listpack(1, 0, 100, "f", 0))
+		}),
+		"hash with field expirations of huge count": corrupt(func(w *rdbWriter) {
+			w.key(24, "hash")
+			w.Write(make([]byte, 8))
+			huge(w)
+		}),
+		"list of huge count": corrupt(func(w *rdbWriter) {
+			w.key(1, "list")
+			huge(w)
+		}),
+		"zset of huge count": corrupt(func(w *rdbWriter) {
+			w.key(5, "zset")
+			huge(w)
+		}),
+		"string of huge length": corrupt(func(w *rdbWriter) {
+			w.key(0, "string")
+			huge(w)
+		}),
+		"lzf string of huge length": corrupt(func(w *rdbWriter) {
+			w.key(0, "lzf")
+			w.WriteByte(0xC3)
+			w.length(5)
+			huge(w)
+			w.Write([]byte{0x00, 'a', 0xE0, 0x00, 0x00})
+		}),
+	}
+
+	for name, data := range testcases {
+		if _, err := api.ParseRDB(bytes.NewReader(data)); err == nil {
+			t.Errorf("test failed: %s should be invalid", name)
+		}
+
+		// the size is unknown, lengths are not checked up front
+		if _, err := api.ParseRDB(io.MultiReader(bytes.NewReader(data))); err == nil {
+			t.Errorf("test failed: %s should be invalid without size", name)
+		}
+	}
+
+	// lengths are checked against the size of file
+	f, err := ioutil.TempFile("", "dump-*.rdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	_, _ = f.Write(testcases["hash with field expirations of huge count"])
+	_ = f.Close()
+
+	if _, err := api.LoadRDB(f.Name()); err == nil || !strings.Contains(err.Error(), "bytes left") {
+		t.Errorf("test failed: %v", err)
+	}
+}
@@ -0,0 +1,820 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// errWrongType is the same error as redis returns for operations against keys of other types
+var errWrongType = errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+
+var rdbFiles = struct {
+	sync.Mutex
+	loaded map[string]*RDB
+}{loaded: make(map[string]*RDB)}
+
+// OpenRDB load the RDB file at path, the snapshot is cached so that it's only parsed once when databases are switched
+func OpenRDB(path string) (*RDB, error) {
+	rdbFiles.Lock()
+	defer rdbFiles.Unlock()
+
+	if rdb, ok := rdbFiles.loaded[path]; ok {
+		return rdb, nil
+	}
+
+	rdb, err := LoadRDB(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rdbFiles.loaded[path] = rdb
+	return rdb, nil
+}
+
+// RDBClient is a read-only RedisClient browsing a database of RDB file, TTLs are relative to the time
+// when the snapshot was created. Commands writing keys or managing the server are rejected
+type RDBClient struct {
+	path string
+	rdb  *RDB
+	db   int
+	// err is returned by all commands if the file can't be loaded
+	err error
+}
+
+var _ RedisClient = (*RDBClient)(nil)
+
+// NewRDBClient create a client browsing the database db of the RDB file at path
+func NewRDBClient(path string, db int) *RDBClient {
+	rdb, err := OpenRDB(path)
+	return &RDBClient{path: path, rdb: rdb, db: db, err: err}
+}
+
+// Path return the path of RDB file
+func (c *RDBClient) Path() string {
+	return c.path
+}
+
+// RDB return the loaded snapshot, nil if the file can't be loaded
+func (c *RDBClient) RDB() *RDB {
+	return c.rdb
+}
+
+func (c *RDBClient) database() *RDBDatabase {
+	if c.rdb != nil {
+		if d, ok := c.rdb.Databases[c.db]; ok {
+			return d
+		}
+	}
+
+	return &RDBDatabase{Entries: make(map[string]*RDBEntry)}
+}
+
+// entry return the entry of key, redis.Nil is returned if the key doesn't exist
+func (c *RDBClient) entry(key string, keyType string) (*RDBEntry, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	e, ok := c.database().Entries[key]
+	if !ok {
+		return nil, redis.Nil
+	}
+
+	if keyType != "" && e.Type != keyType {
+		return nil, errWrongType
+	}
+
+	return e, nil
+}
+
+// pttl return the TTL in milliseconds relative to the creation of snapshot, -1 if no expiration and -2 if not exists
+func (c *RDBClient) pttl(key string) (int64, error) {
+	e, err := c.entry(key, "")
+	if err == redis.Nil {
+		return -2, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if e.ExpireAt == 0 {
+		return -1, nil
+	}
+
+	ttl := e.ExpireAt - c.rdb.CreatedAt.UnixNano()/int64(time.Millisecond)
+	if ttl < 0 {
+		// the key was expired but not evicted when the snapshot was created
+		ttl = 0
+	}
+
+	return ttl, nil
+}
+
+// Keys return the keys matching pattern in order
+func (c *RDBClient) Keys(pattern string) *redis.StringSliceCmd {
+	keys, err := c.keys(pattern, "")
+	return redis.NewStringSliceResult(keys, err)
+}
+
+func (c *RDBClient) keys(pattern, keyType string) ([]string, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	d := c.database()
+	keys := make([]string, 0)
+	for _, k := range d.Keys {
+		if (keyType == "" || d.Entries[k].Type == keyType) && matchPattern(pattern, k) {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys, nil
+}
+
+// Scan return the keys matching pattern, the cursor is the index in ordered keys
+func (c *RDBClient) Scan(cursor uint64, match string, count int64) *redis.ScanCmd {
+	keys, next, err := c.scan(cursor, match, count, "")
+	return redis.NewScanCmdResult(keys, next, err)
+}
+
+func (c *RDBClient) scan(cursor uint64, match string, count int64, keyType string) ([]string, uint64, error) {
+	if c.err != nil {
+		return nil, 0, c.err
+	}
+	if match == "" {
+		match = "*"
+	}
+	if count <= 0 {
+		count = 10
+	}
+
+	d := c.database()
+	keys := make([]string, 0)
+	i := cursor
+	for ; i < uint64(len(d.Keys)) && i < cursor+uint64(count); i++ {
+		k := d.Keys[i]
+		if (keyType == "" || d.Entries[k].Type == keyType) && matchPattern(match, k) {
+			keys = append(keys, k)
+		}
+	}
+
+	if i >= uint64(len(d.Keys)) {
+		i = 0
+	}
+
+	return keys, i, nil
+}
+
+// Type return the type of key, none if not exists
+func (c *RDBClient) Type(key string) *redis.StatusCmd {
+	e, err := c.entry(key, "")
+	if err == redis.Nil {
+		return redis.NewStatusResult("none", nil)
+	}
+	if err != nil {
+		return redis.NewStatusResult("", err)
+	}
+
+	return redis.NewStatusResult(e.Type, nil)
+}
+
+// TTL return the TTL of key like go-redis, which is -1s if no expiration and -2s if not exists
+func (c *RDBClient) TTL(key string) *redis.DurationCmd {
+	ttl, err := c.pttl(key)
+	if ttl < 0 {
+		return redis.NewDurationResult(time.Duration(ttl)*time.Second, err)
+	}
+
+	return redis.NewDurationResult(time.Duration(ttl/1000)*time.Second, err)
+}
+
+// Get return the value of string
+func (c *RDBClient) Get(key string) *redis.StringCmd {
+	e, err := c.entry(key, "string")
+	if err != nil {
+		return redis.NewStringResult("", err)
+	}
+
+	return redis.NewStringResult(e.Value.(string), nil)
+}
+
+// LRange return the elements of list between start and stop
+func (c *RDBClient) LRange(key string, start, stop int64) *redis.StringSliceCmd {
+	e, err := c.entry(key, "list")
+	if err == redis.Nil {
+		return redis.NewStringSliceResult([]string{}, nil)
+	}
+	if err != nil {
+		return redis.NewStringSliceResult(nil, err)
+	}
+
+	values := e.Value.([]string)
+	from, to, ok := rangeIndexes(start, stop, len(values))
+	if !ok {
+		return redis.NewStringSliceResult([]string{}, nil)
+	}
+
+	return redis.NewStringSliceResult(values[from:to+1], nil)
+}
+
+// SMembers return the members of set
+func (c *RDBClient) SMembers(key string) *redis.StringSliceCmd {
+	e, err := c.entry(key, "set")
+	if err == redis.Nil {
+		return redis.NewStringSliceResult([]string{}, nil)
+	}
+	if err != nil {
+		return redis.NewStringSliceResult(nil, err)
+	}
+
+	return redis.NewStringSliceResult(e.Value.([]string), nil)
+}
+
+// ZRangeWithScores return the members of zset between start and stop ordered by score
+func (c *RDBClient) ZRangeWithScores(key string, start, stop int64) *redis.ZSliceCmd {
+	members, err := c.zrange(key, start, stop)
+	if err != nil {
+		return redis.NewZSliceCmdResult(nil, err)
+	}
+
+	res := make([]redis.Z, 0, len(members))
+	for _, m := range members {
+		res = append(res, redis.Z{Score: m.Score, Member: m.Member})
+	}
+
+	return redis.NewZSliceCmdResult(res, nil)
+}
+
+func (c *RDBClient) zrange(key string, start, stop int64) ([]ZMember, error) {
+	e, err := c.entry(key, "zset")
+	if err == redis.Nil {
+		return []ZMember{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	members := e.Value.([]ZMember)
+	from, to, ok := rangeIndexes(start, stop, len(members))
+	if !ok {
+		return []ZMember{}, nil
+	}
+
+	return members[from : to+1], nil
+}
+
+// HKeys return the fields of hash in order
+func (c *RDBClient) HKeys(key string) *redis.StringSliceCmd {
+	e, err := c.entry(key, "hash")
+	if err == redis.Nil {
+		return redis.NewStringSliceResult([]string{}, nil)
+	}
+	if err != nil {
+		return redis.NewStringSliceResult(nil, err)
+	}
+
+	return redis.NewStringSliceResult(sortedKeys(e.Value.(map[string]string)), nil)
+}
+
+// HGet return the value of field in hash
+func (c *RDBClient) HGet(key, field string) *redis.StringCmd {
+	e, err := c.entry(key, "hash")
+	if err != nil {
+		return redis.NewStringResult("", err)
+	}
+
+	value, ok := e.Value.(map[string]string)[field]
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+
+	return redis.NewStringResult(value, nil)
+}
+
+// Process is not supported, since the result can't be filled into cmd
+func (c *RDBClient) Process(cmd redis.Cmder) error {
+	return fmt.Errorf("ERR %s is not supported on RDB file", cmd.Name())
+}
+
+// Info return the server info generated from the snapshot, with sections Server, Memory and Keyspace
+func (c *RDBClient) Info(section ...string) *redis.StringCmd {
+	if c.err != nil {
+		return redis.NewStringResult("", c.err)
+	}
+
+	return redis.NewStringResult(c.info(section...), nil)
+}
+
+func (c *RDBClient) info(section ...string) string {
+	name := "all"
+	if len(section) > 0 {
+		name = strings.ToLower(section[0])
+	}
+	all := name == "all" || name == "default" || name == "everything"
+
+	version := c.rdb.Aux["redis-ver"]
+	if version == "" {
+		version = "unknown"
+	}
+
+	memory := int64(0)
+	dbs := make([]int, 0, len(c.rdb.Databases))
+	for db, d := range c.rdb.Databases {
+		dbs = append(dbs, db)
+		for _, e := range d.Entries {
+			memory += e.Memory
+		}
+	}
+	sort.Ints(dbs)
+
+	lines := make([]string, 0)
+	if all || name == "server" {
+		lines = append(lines,
+			"# Server",
+			"redis_version:"+version,
+			"redis_mode:standalone",
+			"rdb_file:"+c.path,
+			fmt.Sprintf("rdb_version:%d", c.rdb.Version),
+			fmt.Sprintf("rdb_created_at:%d", c.rdb.CreatedAt.Unix()),
+			fmt.Sprintf("rdb_skipped_keys:%d", c.rdb.Skipped),
+			"",
+		)
+	}
+
+	if all || name == "memory" {
+		lines = append(lines,
+			"# Memory",
+			fmt.Sprintf("used_memory:%d", memory),
+			"used_memory_human:"+humanBytes(memory),
+			"",
+		)
+	}
+
+	if all || name == "keyspace" {
+		lines = append(lines, "# Keyspace")
+		for _, db := range dbs {
+			d := c.rdb.Databases[db]
+			lines = append(lines, fmt.Sprintf("db%d:keys=%d,expires=%d,avg_ttl=0", db, len(d.Entries), d.Expires))
+		}
+		lines = append(lines, "")
+	}
+
+	return strings.Join(lines, "\r\n")
+}
+
+// Do execute the read-only commands against the snapshot, replies are in the same types as go-redis
+func (c *RDBClient) Do(args ...interface{}) *redis.Cmd {
+	if c.err != nil {
+		return redis.NewCmdResult(nil, c.err)
+	}
+
+	if len(args) == 0 {
+		return redis.NewCmdResult(nil, errors.New("ERR empty command"))
+	}
+
+	argv := make([]string, len(args)-1)
+	for i, arg := range args[1:] {
+		argv[i] = fmt.Sprint(arg)
+	}
+
+	val, err := c.execute(strings.ToUpper(fmt.Sprint(args[0])), argv)
+	return redis.NewCmdResult(val, err)
+}
+
+// rdbCommandArity is the min count of arguments of supported commands
+var rdbCommandArity = map[string]int{
+	"PING": 0, "ECHO": 1, "DBSIZE": 0, "RANDOMKEY": 0, "EXISTS": 1, "TYPE": 1, "TTL": 1, "PTTL": 1,
+	"GET": 1, "STRLEN": 1, "LRANGE": 3, "LLEN": 1, "LINDEX": 2, "SMEMBERS": 1, "SCARD": 1, "SISMEMBER": 2,
+	"ZRANGE": 3, "ZCARD": 1, "ZSCORE": 2, "HGET": 2, "HGETALL": 1, "HKEYS": 1, "HVALS": 1, "HLEN": 1,
+	"HEXISTS": 2, "XRANGE": 3, "XLEN": 1, "SCAN": 1, "KEYS": 1, "INFO": 0, "CONFIG": 2, "MEMORY": 2,
+	"OBJECT": 2,
+}
+
+func (c *RDBClient) execute(name string, argv []string) (interface{}, error) {
+	arity, ok := rdbCommandArity[name]
+	if !ok {
+		return nil, fmt.Errorf("ERR %s is not supported, the RDB file %s is browsed read-only", name, c.path)
+	}
+	if len(argv) < arity {
+		return nil, fmt.Errorf("ERR wrong number of arguments for '%s' command", strings.ToLower(name))
+	}
+
+	switch name {
+	case "PING":
+		return "PONG", nil
+	case "ECHO":
+		return argv[0], nil
+	case "DBSIZE":
+		return int64(len(c.database().Keys)), nil
+	case "RANDOMKEY":
+		keys := c.database().Keys
+		if len(keys) == 0 {
+			return nil, redis.Nil
+		}
+		return keys[time.Now().UnixNano()%int64(len(keys))], nil
+	case "EXISTS":
+		count := int64(0)
+		for _, key := range argv {
+			if _, ok := c.database().Entries[key]; ok {
+				count++
+			}
+		}
+		return count, nil
+	case "TYPE":
+		return c.Type(argv[0]).Result()
+	case "TTL":
+		ttl, err := c.pttl(argv[0])
+		if ttl > 0 {
+			ttl /= 1000
+		}
+		return ttl, err
+	case "PTTL":
+		return c.pttl(argv[0])
+	case "GET":
+		return c.Get(argv[0]).Result()
+	case "STRLEN":
+		s, err := c.Get(argv[0]).Result()
+		if err == redis.Nil {
+			err = nil
+		}
+		return int64(len(s)), err
+	case "LRANGE":
+		start, stop, err := parseRange(argv[1], argv[2])
+		if err != nil {
+			return nil, err
+		}
+		values, err := c.LRange(argv[0], start, stop).Result()
+		return stringsReply(values), err
+	case "LINDEX":
+		index, err := strconv.ParseInt(argv[1], 10, 64)
+		if err != nil {
+			return nil, errors.New("ERR value is not an integer or out of range")
+		}
+		values, err := c.LRange(argv[0], index, index).Result()
+		if err != nil || len(values) == 0 {
+			return nil, orNil(err)
+		}
+		return values[0], nil
+	case "SMEMBERS":
+		values, err := c.SMembers(argv[0]).Result()
+		return stringsReply(values), err
+	case "SISMEMBER":
+		values, err := c.SMembers(argv[0]).Result()
+		for _, v := range values {
+			if v == argv[1] {
+				return int64(1), nil
+			}
+		}
+		return int64(0), err
+	case "ZRANGE":
+		start, stop, err := parseRange(argv[1], argv[2])
+		if err != nil {
+			return nil, err
+		}
+		members, err := c.zrange(argv[0], start, stop)
+		withScores := len(argv) > 3 && strings.ToUpper(argv[3]) == "WITHSCORES"
+		reply := make([]interface{}, 0)
+		for _, m := range members {
+			reply = append(reply, m.Member)
+			if withScores {
+				reply = append(reply, strconv.FormatFloat(m.Score, 'f', -1, 64))
+			}
+		}
+		return reply, err
+	case "ZSCORE":
+		members, err := c.zrange(argv[0], 0, -1)
+		for _, m := range members {
+			if m.Member == argv[1] {
+				return strconv.FormatFloat(m.Score, 'f', -1, 64), nil
+			}
+		}
+		return nil, orNil(err)
+	case "HGET":
+		return c.HGet(argv[0], argv[1]).Result()
+	case "HGETALL", "HVALS":
+		fields, err := c.HKeys(argv[0]).Result()
+		reply := make([]interface{}, 0)
+		for _, f := range fields {
+			if name == "HGETALL" {
+				reply = append(reply, f)
+			}
+			reply = append(reply, c.HGet(argv[0], f).Val())
+		}
+		return reply, err
+	case "HKEYS":
+		fields, err := c.HKeys(argv[0]).Result()
+		return stringsReply(fields), err
+	case "HEXISTS":
+		if _, err := c.HGet(argv[0], argv[1]).Result(); err != nil {
+			return int64(0), ignoreNil(err)
+		}
+		return int64(1), nil
+	case "LLEN", "SCARD", "ZCARD", "HLEN", "XLEN":
+		keyType := map[string]string{"LLEN": "list", "SCARD": "set", "ZCARD": "zset", "HLEN": "hash", "XLEN": "stream"}[name]
+		e, err := c.entry(argv[0], keyType)
+		if err != nil {
+			return int64(0), ignoreNil(err)
+		}
+		return int64(e.Len()), nil
+	case "XRANGE":
+		return c.xrange(argv)
+	case "SCAN":
+		return c.scanCommand(argv)
+	case "KEYS":
+		keys, err := c.keys(argv[0], "")
+		return stringsReply(keys), err
+	case "INFO":
+		return c.info(argv...), nil
+	case "CONFIG":
+		return c.config(argv)
+	case "MEMORY", "OBJECT":
+		return c.object(name, argv)
+	}
+
+	return nil, fmt.Errorf("ERR %s is not supported", name)
+}
+
+// xrange return the entries of stream between start and end, COUNT is supported
+func (c *RDBClient) xrange(argv []string) (interface{}, error) {
+	e, err := c.entry(argv[0], "stream")
+	if err != nil {
+		return []interface{}{}, ignoreNil(err)
+	}
+
+	count := -1
+	if len(argv) >= 5 && strings.ToUpper(argv[3]) == "COUNT" {
+		if count, err = strconv.Atoi(argv[4]); err != nil {
+			return nil, errors.New("ERR value is not an integer or out of range")
+		}
+	}
+
+	reply := make([]interface{}, 0)
+	for _, entry := range e.Value.([]StreamEntry) {
+		if count >= 0 && len(reply) >= count {
+			break
+		}
+		if (argv[1] != "-" && compareStreamID(entry.ID, argv[1]) < 0) || (argv[2] != "+" && compareStreamID(entry.ID, argv[2]) > 0) {
+			continue
+		}
+
+		reply = append(reply, []interface{}{entry.ID, stringsReply(entry.Fields)})
+	}
+
+	return reply, nil
+}
+
+// compareStreamID compare the stream ids in the form of ms-seq, the sequence is 0 if omitted
+func compareStreamID(a, b string) int {
+	parse := func(id string) (uint64, uint64) {
+		parts := strings.SplitN(id, "-", 2)
+		ms, _ := strconv.ParseUint(parts[0], 10, 64)
+		seq := uint64(0)
+		if len(parts) == 2 {
+			seq, _ = strconv.ParseUint(parts[1], 10, 64)
+		}
+		return ms, seq
+	}
+
+	ams, aseq := parse(a)
+	bms, bseq := parse(b)
+	switch {
+	case ams != bms && ams < bms, ams == bms && aseq < bseq:
+		return -1
+	case ams == bms && aseq == bseq:
+		return 0
+	}
+
+	return 1
+}
+
+// scanCommand execute SCAN cursor [MATCH pattern] [COUNT count] [TYPE type]
+func (c *RDBClient) scanCommand(argv []string) (interface{}, error) {
+	cursor, err := strconv.ParseUint(argv[0], 10, 64)
+	if err != nil {
+		return nil, errors.New("ERR invalid cursor")
+	}
+
+	match, count, keyType := "*", int64(10), ""
+	for i := 1; i+1 < len(argv); i += 2 {
+		switch strings.ToUpper(argv[i]) {
+		case "MATCH":
+			match = argv[i+1]
+		case "COUNT":
+			if count, err = strconv.ParseInt(argv[i+1], 10, 64); err != nil {
+				return nil, errors.New("ERR value is not an integer or out of range")
+			}
+		case "TYPE":
+			keyType = strings.ToLower(argv[i+1])
+		default:
+			return nil, errors.New("ERR syntax error")
+		}
+	}
+
+	keys, next, err := c.scan(cursor, match, count, keyType)
+	return []interface{}{strconv.FormatUint(next, 10), stringsReply(keys)}, err
+}
+
+// config answer CONFIG GET of databases and maxmemory-policy, which are used by the database switcher
+// and hot keys, the policy is inferred from the LFU or LRU info saved with keys
+func (c *RDBClient) config(argv []string) (interface{}, error) {
+	if strings.ToUpper(argv[0]) != "GET" {
+		return nil, fmt.Errorf("ERR CONFIG %s is not supported, the RDB file %s is browsed read-only", argv[0], c.path)
+	}
+
+	values := map[string]string{"databases": "16", "maxmemory-policy": "noeviction"}
+	for db, d := range c.rdb.Databases {
+		if db >= 16 {
+			values["databases"] = strconv.Itoa(db + 1)
+		}
+
+		for _, e := range d.Entries {
+			if e.Freq >= 0 {
+				values["maxmemory-policy"] = "allkeys-lfu"
+			} else if e.Idle >= 0 {
+				values["maxmemory-policy"] = "allkeys-lru"
+			}
+			break
+		}
+	}
+
+	reply := make([]interface{}, 0)
+	for _, name := range []string{"databases", "maxmemory-policy"} {
+		if matchPattern(strings.ToLower(argv[1]), name) {
+			reply = append(reply, name, values[name])
+		}
+	}
+
+	return reply, nil
+}
+
+// object answer MEMORY USAGE and OBJECT ENCODING, FREQ and IDLETIME
+func (c *RDBClient) object(name string, argv []string) (interface{}, error) {
+	sub := strings.ToUpper(argv[0])
+	e, err := c.entry(argv[1], "")
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case name == "MEMORY" && sub == "USAGE":
+		return e.Memory, nil
+	case name == "OBJECT" && sub == "ENCODING":
+		return e.Encoding, nil
+	case name == "OBJECT" && sub == "FREQ":
+		if e.Freq < 0 {
+			return nil, errors.New("ERR An LFU maxmemory policy is not selected, access frequency not tracked")
+		}
+		return int64(e.Freq), nil
+	case name == "OBJECT" && sub == "IDLETIME":
+		if e.Idle < 0 {
+			return nil, errors.New("ERR An LRU maxmemory policy is not selected, idle time not saved")
+		}
+		return e.Idle, nil
+	}
+
+	return nil, fmt.Errorf("ERR %s %s is not supported, the RDB file %s is browsed read-only", name, sub, c.path)
+}
+
+// rangeIndexes normalize the start and stop of range like LRANGE, false is returned if the range is empty
+func rangeIndexes(start, stop int64, n int) (int, int, bool) {
+	if start < 0 {
+		start += int64(n)
+	}
+	if stop < 0 {
+		stop += int64(n)
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start > stop || start >= int64(n) {
+		return 0, 0, false
+	}
+	if stop >= int64(n) {
+		stop = int64(n) - 1
+	}
+
+	return int(start), int(stop), true
+}
+
+func parseRange(start, stop string) (int64, int64, error) {
+	from, err1 := strconv.ParseInt(start, 10, 64)
+	to, err2 := strconv.ParseInt(stop, 10, 64)
+	if err1 != nil || err2 != nil {
+		return 0, 0, errors.New("ERR value is not an integer or out of range")
+	}
+
+	return from, to, nil
+}
+
+// stringsReply convert strings to the reply of array
+func stringsReply(values []string) []interface{} {
+	reply := make([]interface{}, len(values))
+	for i, v := range values {
+		reply[i] = v
+	}
+
+	return reply
+}
+
+// orNil return redis.Nil if err is nil
+func orNil(err error) error {
+	if err == nil {
+		return redis.Nil
+	}
+
+	return err
+}
+
+// ignoreNil return nil if err is redis.Nil
+func ignoreNil(err error) error {
+	if err == redis.Nil {
+		return nil
+	}
+
+	return err
+}
+
+// matchPattern check whether s matches the glob-style pattern like redis does, * ? [abc] [^a] [a-z]
+// and escaping by \ are supported
+func matchPattern(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if matchPattern(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+			s = s[1:]
+		case '[':
+			if len(s) == 0 {
+				return false
+			}
+
+			pattern = pattern[1:]
+			not := len(pattern) > 0 && pattern[0] == '^'
+			if not {
+				pattern = pattern[1:]
+			}
+
+			matched := false
+			for len(pattern) > 0 && pattern[0] != ']' {
+				switch {
+				case pattern[0] == '\\' && len(pattern) >= 2:
+					pattern = pattern[1:]
+					matched = matched || pattern[0] == s[0]
+				case len(pattern) >= 3 && pattern[1] == '-' && pattern[2] != ']':
+					lo, hi := pattern[0], pattern[2]
+					if lo > hi {
+						lo, hi = hi, lo
+					}
+					matched = matched || s[0] >= lo && s[0] <= hi
+					pattern = pattern[2:]
+				default:
+					matched = matched || pattern[0] == s[0]
+				}
+				pattern = pattern[1:]
+			}
+
+			if matched == not {
+				return false
+			}
+			s = s[1:]
+			if len(pattern) == 0 {
+				return len(s) == 0
+			}
+		case '\\':
+			if len(pattern) >= 2 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if len(s) == 0 || pattern[0] != s[0] {
+				return false
+			}
+			s = s[1:]
+		}
+		pattern = pattern[1:]
+	}
+
+	return len(s) == 0
+}
@@ -31,12 +31,21 @@ type RedisClient interface {
 
 // NewRedisClient create a new redis client which wraps single or cluster client
 func NewRedisClient(conf config.Config, outputChan chan core.OutputMessage) RedisClient {
+	if conf.RDBFile != "" {
+		return NewRDBClient(conf.RDBFile, conf.DB)
+	}
+
 	if conf.Cluster {
 		options := &redis.ClusterOptions{
 			Addrs:    []string{fmt.Sprintf("%s:%d", conf.Host, conf.Port)},
 			Password: conf.Password,
 		}
 
+		if conf.Username != "" {
+			options.Password = ""
+			options.OnConnect = authOnConnect(conf.Username, conf.Password, 0)
+		}
+
 		return redis.NewClusterClient(options)
 	}
 
@@ -48,6 +57,12 @@ func NewRedisClient(conf config.Config, outputChan chan core.OutputMessage) Redi
 		ReadTimeout:  2 * time.Second,
 	}
 
+	if conf.Username != "" {
+		// SELECT is sent before OnConnect by go-redis, so it's moved into OnConnect after AUTH
+		options.Password, options.DB = "", 0
+		options.OnConnect = authOnConnect(conf.Username, conf.Password, conf.DB)
+	}
+
 	client := redis.NewClient(options)
 	if conf.Debug {
 		client.WrapProcess(func(oldProcess func(cmd redis.Cmder) error) func(cmd redis.Cmder) error {
@@ -64,14 +79,73 @@ func NewRedisClient(conf config.Config, outputChan chan core.OutputMessage) Redi
 	return client
 }
 
+// authOnConnect return a connection hook which sends AUTH with username (ACL, redis 6+) and SELECT,
+// go-redis only supports AUTH with password
+func authOnConnect(username, password string, db int) func(*redis.Conn) error {
+	return func(cn *redis.Conn) error {
+		if err := cn.Do("AUTH", username, password).Err(); err != nil {
+			return err
+		}
+
+		if db != 0 {
+			return cn.Do("SELECT", db).Err()
+		}
+
+		return nil
+	}
+}
+
+// ForEachMaster call fn for every master in cluster mode, or for the client itself in standalone mode,
+// fn may be called concurrently in cluster mode
+func ForEachMaster(client RedisClient, fn func(addr string, node RedisClient) error) error {
+	if clusterClient, ok := client.(*redis.ClusterClient); ok {
+		return clusterClient.ForEachMaster(func(master *redis.Client) error {
+			return fn(master.Options().Addr, master)
+		})
+	}
+
+	return fn(ClientAddr(client), client)
+}
+
+// ForEachNode call fn for every node (masters and replicas) in cluster mode, or for the client itself in standalone mode.
+// fn may be called concurrently in cluster mode
+func ForEachNode(client RedisClient, fn func(addr string, node RedisClient) error) error {
+	if clusterClient, ok := client.(*redis.ClusterClient); ok {
+		return clusterClient.ForEachNode(func(node *redis.Client) error {
+			return fn(node.Options().Addr, node)
+		})
+	}
+
+	return fn(ClientAddr(client), client)
+}
+
+// ClientAddr return the address of a standalone client, or the seed addresses of a cluster client
+func ClientAddr(client RedisClient) string {
+	switch c := client.(type) {
+	case *redis.Client:
+		return c.Options().Addr
+	case *redis.ClusterClient:
+		return strings.Join(c.Options().Addrs, ",")
+	case *RDBClient:
+		return c.Path()
+	}
+
+	return ""
+}
+
 func RedisExecute(client RedisClient, command string) (interface{}, error) {
+	return client.Do(commandArgs(command)...).Result()
+}
+
+// commandArgs split the command text into arguments
+func commandArgs(command string) []interface{} {
 	stringArgs := strings.Split(command, " ")
 	var args = make([]interface{}, len(stringArgs))
 	for i, s := range stringArgs {
 		args[i] = s
 	}
 
-	return client.Do(args...).Result()
+	return args
 }
 
 var redisKeys = make([]string, 0)
@@ -109,21 +183,25 @@ func RedisAllKeys(client RedisClient, cache bool) ([]string, error) {
 	return keys, nil
 }
 
+// KeysWithLimit scan keys matching the pattern, every master is scanned in cluster mode.
+// maxScanCount is the max rounds of SCAN, -1 means scanning until all keys are returned
 func KeysWithLimit(client RedisClient, key string, maxScanCount int) (redisKeys []string, err error) {
-	var cursor uint64 = 0
-	var keys []string
+	scanner := NewKeyScanner(client, key)
 
 	var scanCount = 0
-	for scanCount < maxScanCount || maxScanCount == -1{
+	for scanCount < maxScanCount || maxScanCount == -1 {
 		scanCount++
 
-		keys, cursor, err = client.Scan(cursor, key, 100).Result()
+		keys, err := scanner.Next(100)
 		if err != nil {
-			return
+			return redisKeys, err
+		}
+
+		for _, k := range keys {
+			redisKeys = append(redisKeys, k.Key)
 		}
 
-		redisKeys = append(redisKeys, keys...)
-		if cursor == 0 {
+		if scanner.Done() {
 			break
 		}
 	}
@@ -131,29 +209,18 @@ func KeysWithLimit(client RedisClient, key string, maxScanCount int) (redisKeys
 	return
 }
 
-func RedisServerInfo(conf config.Config, client RedisClient) (string, error) {
-	res, err := client.Info().Result()
+// RedisHashValues return all fields and values of a hash
+func RedisHashValues(client RedisClient, key string) (map[string]string, error) {
+	res, err := client.Do("HGETALL", key).Result()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	var kvpairs = make(map[string]string)
-	for _, kv := range strings.Split(res, "\n") {
-		if strings.HasPrefix(kv, "#") || kv == "" {
-			continue
-		}
-
-		pair := strings.SplitN(kv, ":", 2)
-		if len(pair) != 2 {
-			continue
-		}
-
-		kvpairs[pair[0]] = pair[1]
+	items, _ := res.([]interface{})
+	values := make(map[string]string, len(items)/2)
+	for i := 0; i+1 < len(items); i += 2 {
+		values[fmt.Sprint(items[i])] = fmt.Sprint(items[i+1])
 	}
 
-	keySpace := "-"
-	if ks, ok := kvpairs[fmt.Sprintf("db%d", conf.DB)]; ok {
-		keySpace = ks
-	}
-	return fmt.Sprintf(" RedisVersion: %s    Memory: %s    Server: %s:%d/%d\n KeySpace: %s", kvpairs["redis_version"], kvpairs["used_memory_human"], conf.Host, conf.Port, conf.DB, keySpace), nil
+	return values, nil
 }
@@ -0,0 +1,285 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mylxsw/redis-tui/config"
+)
+
+// ReplicaInfo is a replica connected to a master, parsed from slaveN fields of INFO replication
+type ReplicaInfo struct {
+	Addr   string
+	State  string
+	Offset int64
+	// Lag is the seconds since last ack from the replica
+	Lag int64
+}
+
+// ReplicationInfo is the replication status of a node
+type ReplicationInfo struct {
+	Addr string
+	Role string
+
+	// fields of replicas
+	MasterAddr             string
+	MasterLinkStatus       string
+	MasterLastIOSecondsAgo int64
+	MasterSyncInProgress   bool
+	SlaveReplOffset        int64
+	// ReplicaState is the state of replication from ROLE: connect, connecting, sync or connected
+	ReplicaState string
+
+	MasterReplOffset       int64
+	BacklogActive          bool
+	BacklogSize            int64
+	BacklogFirstByteOffset int64
+	BacklogHistlen         int64
+
+	Replicas []ReplicaInfo
+
+	// Err is the error when querying the node, other fields are empty if it's not nil
+	Err error
+}
+
+// IsReplica check whether the node is a replica
+func (r ReplicationInfo) IsReplica() bool {
+	return r.Role == "slave" || r.Role == "replica"
+}
+
+// BacklogUsage return the ratio of replication backlog used
+func (r ReplicationInfo) BacklogUsage() float64 {
+	if r.BacklogSize <= 0 {
+		return 0
+	}
+
+	return float64(r.BacklogHistlen) / float64(r.BacklogSize)
+}
+
+// LagBytes return the bytes the replica is behind the master offset
+func (r ReplicaInfo) LagBytes(masterOffset int64) int64 {
+	if lag := masterOffset - r.Offset; lag > 0 {
+		return lag
+	}
+
+	return 0
+}
+
+// ParseReplicationInfo parse the Replication section of INFO
+func ParseReplicationInfo(addr string, info *ServerInfo) ReplicationInfo {
+	r := ReplicationInfo{
+		Addr:                   addr,
+		Role:                   info.String("role"),
+		MasterLinkStatus:       info.String("master_link_status"),
+		MasterLastIOSecondsAgo: info.Int64("master_last_io_seconds_ago"),
+		MasterSyncInProgress:   info.Int64("master_sync_in_progress") == 1,
+		SlaveReplOffset:        info.Int64("slave_repl_offset"),
+		MasterReplOffset:       info.Int64("master_repl_offset"),
+		BacklogActive:          info.Int64("repl_backlog_active") == 1,
+		BacklogSize:            info.Int64("repl_backlog_size"),
+		BacklogFirstByteOffset: info.Int64("repl_backlog_first_byte_offset"),
+		BacklogHistlen:         info.Int64("repl_backlog_histlen"),
+		Replicas:               make([]ReplicaInfo, 0),
+	}
+
+	if host := info.String("master_host"); host != "" {
+		r.MasterAddr = net.JoinHostPort(host, info.String("master_port"))
+	}
+
+	section, _ := info.Section("Replication")
+	for _, field := range section.Fields {
+		if !strings.HasPrefix(field.Key, "slave") {
+			continue
+		}
+		if _, err := strconv.Atoi(strings.TrimPrefix(field.Key, "slave")); err != nil {
+			continue
+		}
+
+		values := make(map[string]string)
+		for _, kv := range strings.Split(field.Value, ",") {
+			if pair := strings.SplitN(kv, "=", 2); len(pair) == 2 {
+				values[pair[0]] = pair[1]
+			}
+		}
+
+		replica := ReplicaInfo{Addr: net.JoinHostPort(values["ip"], values["port"]), State: values["state"]}
+		replica.Offset, _ = strconv.ParseInt(values["offset"], 10, 64)
+		replica.Lag, _ = strconv.ParseInt(values["lag"], 10, 64)
+		r.Replicas = append(r.Replicas, replica)
+	}
+
+	return r
+}
+
+// ParseRole parse the reply of ROLE, the replication state is returned for replicas
+func ParseRole(reply interface{}) (role string, state string) {
+	items, ok := reply.([]interface{})
+	if !ok || len(items) == 0 {
+		return "", ""
+	}
+
+	role = fmt.Sprint(items[0])
+	if role == "slave" && len(items) >= 4 {
+		state = fmt.Sprint(items[3])
+	}
+
+	return role, state
+}
+
+// RedisReplicationTopology query the replication status of nodes. Every node is queried in cluster
+// mode, otherwise the nodes are discovered from the connected server by following its master and replicas
+func RedisReplicationTopology(client RedisClient, conf config.Config) ([]ReplicationInfo, error) {
+	if conf.Cluster {
+		var lock sync.Mutex
+		nodes := make([]ReplicationInfo, 0)
+		err := ForEachNode(client, func(addr string, node RedisClient) error {
+			r := ReplicationInfo{Addr: addr}
+			if res, err := node.Info("replication").Result(); err != nil {
+				r.Err = err
+			} else {
+				r = ParseReplicationInfo(addr, ParseInfo(res))
+				if role, err := node.Do("ROLE").Result(); err == nil {
+					_, r.ReplicaState = ParseRole(role)
+				}
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			nodes = append(nodes, r)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		sortReplicationInfos(nodes)
+		return nodes, nil
+	}
+
+	start := net.JoinHostPort(conf.Host, strconv.Itoa(conf.Port))
+	visited := map[string]bool{start: true}
+	queue := []string{start}
+	nodes := make([]ReplicationInfo, 0)
+	for len(queue) > 0 {
+		addr := queue[0]
+		queue = queue[1:]
+
+		r := nodeReplicationInfo(addr, conf)
+		nodes = append(nodes, r)
+		if r.Err != nil {
+			continue
+		}
+
+		next := make([]string, 0, len(r.Replicas)+1)
+		if r.MasterAddr != "" {
+			next = append(next, r.MasterAddr)
+		}
+		for _, replica := range r.Replicas {
+			next = append(next, replica.Addr)
+		}
+
+		for _, n := range next {
+			if !visited[n] {
+				visited[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	if nodes[0].Err != nil {
+		return nil, nodes[0].Err
+	}
+
+	sortReplicationInfos(nodes)
+	return nodes, nil
+}
+
+// nodeReplicationInfo query replication status of the node at addr by a new connection
+func nodeReplicationInfo(addr string, conf config.Config) ReplicationInfo {
+	r := ReplicationInfo{Addr: addr}
+
+	conn, err := DialRawConn(addr, conf)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	defer conn.Close()
+
+	res, err := conn.Do("INFO", "replication")
+	if err != nil {
+		r.Err = err
+		return r
+	}
+
+	r = ParseReplicationInfo(addr, ParseInfo(fmt.Sprint(res)))
+	if role, err := conn.Do("ROLE"); err == nil {
+		_, r.ReplicaState = ParseRole(role)
+	}
+
+	return r
+}
+
+// sortReplicationInfos sort nodes by role and address, masters come first
+func sortReplicationInfos(nodes []ReplicationInfo) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].IsReplica() != nodes[j].IsReplica() {
+			return !nodes[i].IsReplica()
+		}
+
+		return nodes[i].Addr < nodes[j].Addr
+	})
+}
+
+// RedisReplicaOf make the node at addr a replica of master, or promote it to a master if master is empty.
+// CLUSTER REPLICATE and CLUSTER FAILOVER are used in cluster mode since REPLICAOF is not allowed there
+func RedisReplicaOf(client RedisClient, conf config.Config, addr string, master string) error {
+	if conf.Cluster {
+		args := []interface{}{"CLUSTER", "FAILOVER"}
+		if master != "" {
+			nodes, err := RedisClusterNodes(client)
+			if err != nil {
+				return err
+			}
+
+			masterID := ""
+			for _, n := range nodes {
+				if n.Addr == master || n.ID == master {
+					masterID = n.ID
+				}
+			}
+			if masterID == "" {
+				return fmt.Errorf("node %s not found", master)
+			}
+
+			args = []interface{}{"CLUSTER", "REPLICATE", masterID}
+		}
+
+		return RedisNode(client, addr, func(node RedisClient) error {
+			return node.Do(args...).Err()
+		})
+	}
+
+	args := []interface{}{"REPLICAOF", "NO", "ONE"}
+	if master != "" {
+		host, port, err := net.SplitHostPort(master)
+		if err != nil {
+			return err
+		}
+
+		args = []interface{}{"REPLICAOF", host, port}
+	}
+
+	conn, err := DialRawConn(addr, conf)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Do(args...)
+	return err
+}
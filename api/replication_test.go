@@ -0,0 +1,53 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestParseReplicationInfo(t *testing.T) {
+	master := api.ParseReplicationInfo("127.0.0.1:6379", api.ParseInfo(`# Replication
+role:master
+connected_slaves:2
+slave0:ip=127.0.0.1,port=6380,state=online,offset=1000,lag=0
+slave1:ip=127.0.0.1,port=6381,state=wait_bgsave,offset=0,lag=12
+master_replid:8a6bd6f3e7fa1b1f0cb5f0c1cde1f5fbf3c2d1e0
+master_repl_offset:1200
+repl_backlog_active:1
+repl_backlog_size:1048576
+repl_backlog_first_byte_offset:1
+repl_backlog_histlen:262144
+`))
+
+	if master.IsReplica() || len(master.Replicas) != 2 || master.MasterReplOffset != 1200 {
+		t.Fatalf("test failed: %+v", master)
+	}
+
+	if r := master.Replicas[1]; r.Addr != "127.0.0.1:6381" || r.State != "wait_bgsave" || r.Lag != 12 || r.LagBytes(master.MasterReplOffset) != 1200 {
+		t.Errorf("test failed: %+v", r)
+	}
+
+	if master.BacklogUsage() != 0.25 {
+		t.Errorf("test failed: backlog usage %f", master.BacklogUsage())
+	}
+
+	replica := api.ParseReplicationInfo("127.0.0.1:6380", api.ParseInfo(`# Replication
+role:slave
+master_host:127.0.0.1
+master_port:6379
+master_link_status:up
+master_last_io_seconds_ago:1
+master_sync_in_progress:0
+slave_repl_offset:1000
+`))
+
+	if !replica.IsReplica() || replica.MasterAddr != "127.0.0.1:6379" || replica.MasterLinkStatus != "up" || replica.SlaveReplOffset != 1000 {
+		t.Errorf("test failed: %+v", replica)
+	}
+
+	role, state := api.ParseRole([]interface{}{"slave", "127.0.0.1", int64(6379), "connected", int64(1000)})
+	if role != "slave" || state != "connected" {
+		t.Errorf("test failed: %s %s", role, state)
+	}
+}
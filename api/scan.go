@@ -0,0 +1,141 @@
+package api
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis"
+)
+
+// ScannedKey is a key returned by KeyScanner with the node it lives on
+type ScannedKey struct {
+	Key  string
+	Node string
+	Slot int
+}
+
+// KeyScanner scans keys matching a pattern on every master, a cursor is kept for each node so
+// that the scanning can be continued round by round
+type KeyScanner struct {
+	client  RedisClient
+	pattern string
+	// node is the only master need to be scanned when the pattern has a hash tag
+	node string
+
+	lock      sync.Mutex
+	cursors   map[string]uint64
+	done      map[string]bool
+	exhausted bool
+}
+
+// NewKeyScanner create a KeyScanner, only the master serving the slot of hash tag is scanned if
+// the pattern matches keys of a single slot in cluster mode
+func NewKeyScanner(client RedisClient, pattern string) *KeyScanner {
+	s := &KeyScanner{
+		client:  client,
+		pattern: pattern,
+		cursors: make(map[string]uint64),
+		done:    make(map[string]bool),
+	}
+
+	if _, ok := client.(*redis.ClusterClient); ok {
+		if slot, ok := PatternSlot(pattern); ok {
+			if addr, err := RedisSlotAddr(client, slot); err == nil {
+				s.node = addr
+			}
+		}
+	}
+
+	return s
+}
+
+// Done check whether all the nodes have been scanned
+func (s *KeyScanner) Done() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.exhausted || len(s.done) > 0 && len(s.done) == len(s.cursors)
+}
+
+// Next scan a batch of keys on every node which has not been scanned completely
+func (s *KeyScanner) Next(count int64) ([]ScannedKey, error) {
+	keys := make([]ScannedKey, 0)
+	scanned := false
+	err := ForEachMaster(s.client, func(addr string, node RedisClient) error {
+		if s.node != "" && s.node != addr {
+			return nil
+		}
+
+		s.lock.Lock()
+		cursor, done := s.cursors[addr], s.done[addr]
+		s.lock.Unlock()
+
+		if done {
+			return nil
+		}
+
+		res, next, err := node.Scan(cursor, s.pattern, count).Result()
+		if err != nil {
+			return err
+		}
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		scanned = true
+		s.cursors[addr] = next
+		if next == 0 {
+			s.done[addr] = true
+		}
+
+		for _, k := range res {
+			keys = append(keys, ScannedKey{Key: k, Node: addr, Slot: KeySlot(k)})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !scanned && s.node != "" {
+		// the master serving the slot is not known by client, fallback to scan all masters
+		s.node = ""
+		return s.Next(count)
+	}
+
+	if !scanned {
+		s.lock.Lock()
+		s.exhausted = true
+		s.lock.Unlock()
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Key < keys[j].Key
+	})
+
+	return keys, nil
+}
+
+// PatternSlot return the slot of keys matching the pattern, the second return value is false if
+// the matched keys may be in different slots. It's true only if the literal prefix of the pattern
+// contains a hash tag, such as {user1000}:*
+func PatternSlot(pattern string) (int, bool) {
+	prefix := pattern
+	if i := strings.IndexAny(pattern, "*?[\\"); i >= 0 {
+		prefix = pattern[:i]
+	}
+
+	begin := strings.Index(prefix, "{")
+	if begin < 0 {
+		return 0, false
+	}
+
+	end := strings.Index(prefix[begin+1:], "}")
+	if end <= 0 {
+		return 0, false
+	}
+
+	return KeySlot(prefix), true
+}
@@ -0,0 +1,23 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestPatternSlot(t *testing.T) {
+	slotPatterns := []string{"{user1000}*", "{user1000}.fo*", "prefix:{user1000}:?", "{user1000}"}
+	for _, pattern := range slotPatterns {
+		slot, ok := api.PatternSlot(pattern)
+		if !ok || slot != api.KeySlot("{user1000}.following") {
+			t.Errorf("test failed: pattern %s, got %d, %v", pattern, slot, ok)
+		}
+	}
+
+	for _, pattern := range []string{"*", "user*", "{user*}", "{}*", "{user1000*}", "user[{]a}*"} {
+		if _, ok := api.PatternSlot(pattern); ok {
+			t.Errorf("test failed: pattern %s should match keys of different slots", pattern)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+
+	"github.com/go-redis/redis"
+)
+
+// ScriptSHA return the SHA1 digest of script, which is used by EVALSHA
+func ScriptSHA(script string) string {
+	sum := sha1.Sum([]byte(script))
+	return hex.EncodeToString(sum[:])
+}
+
+// RedisScriptLoad load the script into the script cache, it's loaded on every master in cluster mode
+func RedisScriptLoad(client RedisClient, script string) (string, error) {
+	sha := ScriptSHA(script)
+	err := ForEachMaster(client, func(addr string, node RedisClient) error {
+		return node.Do("SCRIPT", "LOAD", script).Err()
+	})
+
+	return sha, err
+}
+
+// RedisEval run the script by EVAL, or by EVALSHA if useSHA is true. The script is loaded by
+// SCRIPT LOAD and retried when EVALSHA returns NOSCRIPT. A nil reply is returned as nil without error
+func RedisEval(client RedisClient, script string, keys []string, args []string, useSHA bool) (interface{}, error) {
+	cmdArgs := func(command string, scriptOrSHA string) []interface{} {
+		cmd := []interface{}{command, scriptOrSHA, len(keys)}
+		for _, k := range keys {
+			cmd = append(cmd, k)
+		}
+		for _, a := range args {
+			cmd = append(cmd, a)
+		}
+
+		return cmd
+	}
+
+	if !useSHA {
+		return nilReply(client.Do(cmdArgs("EVAL", script)...).Result())
+	}
+
+	sha := ScriptSHA(script)
+	res, err := nilReply(client.Do(cmdArgs("EVALSHA", sha)...).Result())
+	if err == nil || !strings.HasPrefix(err.Error(), "NOSCRIPT") {
+		return res, err
+	}
+
+	if _, err := RedisScriptLoad(client, script); err != nil {
+		return nil, err
+	}
+
+	return nilReply(client.Do(cmdArgs("EVALSHA", sha)...).Result())
+}
+
+// nilReply convert the redis.Nil error into a nil reply
+func nilReply(res interface{}, err error) (interface{}, error) {
+	if err == redis.Nil {
+		return nil, nil
+	}
+
+	return res, err
+}
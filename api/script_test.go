@@ -0,0 +1,13 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestScriptSHA(t *testing.T) {
+	if sha := api.ScriptSHA("return 1"); sha != "e0e1f9fabfc9d4800c877a703b823ac0578ff8db" {
+		t.Errorf("test failed: got %s", sha)
+	}
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SlowLogEntry is an entry of SLOWLOG GET
+type SlowLogEntry struct {
+	Node       string
+	ID         int64
+	Time       time.Time
+	Duration   time.Duration
+	Args       []string
+	ClientAddr string
+	ClientName string
+}
+
+// Command return the command of the entry, arguments contain spaces are quoted
+func (e SlowLogEntry) Command() string {
+	return JoinArgs(e.Args)
+}
+
+// JoinArgs join command arguments by space, arguments contain spaces or quotes are quoted
+func JoinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\r\n\"'") {
+			quoted[i] = strconv.Quote(arg)
+		} else {
+			quoted[i] = arg
+		}
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// ParseSlowLog parse the reply of SLOWLOG GET
+func ParseSlowLog(node string, reply interface{}) ([]SlowLogEntry, error) {
+	items, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected slowlog reply: %v", reply)
+	}
+
+	entries := make([]SlowLogEntry, 0, len(items))
+	for _, item := range items {
+		fields, ok := item.([]interface{})
+		if !ok || len(fields) < 4 {
+			return nil, fmt.Errorf("unexpected slowlog entry: %v", item)
+		}
+
+		entry := SlowLogEntry{
+			Node:     node,
+			ID:       toInt64(fields[0]),
+			Time:     time.Unix(toInt64(fields[1]), 0),
+			Duration: time.Duration(toInt64(fields[2])) * time.Microsecond,
+		}
+
+		if args, ok := fields[3].([]interface{}); ok {
+			for _, arg := range args {
+				entry.Args = append(entry.Args, fmt.Sprintf("%v", arg))
+			}
+		}
+
+		// client address and name are available since redis 4.0
+		if len(fields) >= 6 {
+			entry.ClientAddr = fmt.Sprintf("%v", fields[4])
+			entry.ClientName = fmt.Sprintf("%v", fields[5])
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// RedisSlowLogs return the slow logs of the server, slow logs of all masters are merged in cluster mode
+func RedisSlowLogs(client RedisClient, count int) ([]SlowLogEntry, error) {
+	var lock sync.Mutex
+	entries := make([]SlowLogEntry, 0)
+	err := ForEachMaster(client, func(addr string, node RedisClient) error {
+		res, err := node.Do("SLOWLOG", "GET", count).Result()
+		if err != nil {
+			return err
+		}
+
+		nodeEntries, err := ParseSlowLog(addr, res)
+		if err != nil {
+			return err
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		entries = append(entries, nodeEntries...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.After(entries[j].Time)
+	})
+
+	return entries, nil
+}
+
+// RedisSlowLogReset reset the slow logs of the server, or all masters in cluster mode
+func RedisSlowLogReset(client RedisClient) error {
+	return ForEachMaster(client, func(addr string, node RedisClient) error {
+		return node.Do("SLOWLOG", "RESET").Err()
+	})
+}
+
+// toInt64 convert a redis reply to int64
+func toInt64(v interface{}) int64 {
+	switch val := v.(type) {
+	case int64:
+		return val
+	case string:
+		i, _ := strconv.ParseInt(val, 10, 64)
+		return i
+	}
+
+	return 0
+}
@@ -0,0 +1,32 @@
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestParseSlowLog(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{int64(14), int64(1309448221), int64(15), []interface{}{"ping"}},
+		[]interface{}{int64(13), int64(1309448128), int64(30), []interface{}{"set", "foo", "hello world"}, "127.0.0.1:58217", "worker"},
+	}
+
+	entries, err := api.ParseSlowLog("127.0.0.1:6379", reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 || entries[0].ID != 14 || entries[0].ClientAddr != "" {
+		t.Errorf("test failed: %v", entries)
+	}
+
+	if entries[1].Duration != 30*time.Microsecond || entries[1].ClientName != "worker" {
+		t.Errorf("test failed: %v", entries[1])
+	}
+
+	if entries[1].Command() != `set foo "hello world"` {
+		t.Errorf("test failed: %s", entries[1].Command())
+	}
+}
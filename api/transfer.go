@@ -0,0 +1,264 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// ConflictPolicy decides what to do when a key to be copied already exists on the target
+type ConflictPolicy string
+
+// Conflict policies of KeyTransfer
+const (
+	ConflictSkip      ConflictPolicy = "skip"
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	ConflictRename    ConflictPolicy = "rename"
+)
+
+// ParseConflictPolicy parse the name of a conflict policy
+func ParseConflictPolicy(name string) (ConflictPolicy, error) {
+	switch p := ConflictPolicy(strings.ToLower(strings.TrimSpace(name))); p {
+	case ConflictSkip, ConflictOverwrite, ConflictRename:
+		return p, nil
+	}
+
+	return "", fmt.Errorf("invalid conflict policy %s, it should be one of skip, overwrite and rename", name)
+}
+
+// KeyTransfer copies keys matching a pattern from a source to a target by DUMP/RESTORE, keys are
+// read and written by type-aware commands if the payload of DUMP is not accepted by the target,
+// such as copying to an older version
+type KeyTransfer struct {
+	Pattern  string
+	Conflict ConflictPolicy
+	// RenameSuffix is appended to the key when it exists on the target and the conflict policy is rename
+	RenameSuffix string
+	// Concurrency is the count of keys copied at the same time
+	Concurrency int
+	// RateLimit is the max count of keys copied per second, 0 means unlimited
+	RateLimit int
+	// DryRun only checks the conflicts, nothing is written
+	DryRun bool
+}
+
+// KeyTransferProgress is the statistics of a key transfer
+type KeyTransferProgress struct {
+	Scanned int64
+	Copied  int64
+	Renamed int64
+	Skipped int64
+	Failed  int64
+	// Fallback is the count of keys copied by type-aware reads and writes
+	Fallback  int64
+	LastError string
+}
+
+// String format the progress in a line
+func (p KeyTransferProgress) String() string {
+	text := fmt.Sprintf("scanned=%d, copied=%d (renamed=%d, fallback=%d), skipped=%d, failed=%d",
+		p.Scanned, p.Copied, p.Renamed, p.Fallback, p.Skipped, p.Failed)
+	if p.LastError != "" {
+		text += ", last error: " + p.LastError
+	}
+
+	return text
+}
+
+// TransferTargetKey decide the key written on the target according to the conflict policy, exists
+// tells whether key exists on the target and renamedExists tells whether the renamed key exists
+func TransferTargetKey(key string, exists, renamedExists bool, policy ConflictPolicy, suffix string) (target string, replace bool, skip bool) {
+	if !exists {
+		return key, false, false
+	}
+
+	switch policy {
+	case ConflictOverwrite:
+		return key, true, false
+	case ConflictRename:
+		if renamedExists {
+			return "", false, true
+		}
+		return key + suffix, false, false
+	}
+
+	return "", false, true
+}
+
+// Run execute the transfer, progress is called periodically and the transfer is stopped when stop is closed
+func (t KeyTransfer) Run(source, target RedisClient, progress func(p KeyTransferProgress), stop <-chan struct{}) (KeyTransferProgress, error) {
+	if t.Concurrency <= 0 {
+		t.Concurrency = 4
+	}
+	if t.RenameSuffix == "" {
+		t.RenameSuffix = ":copy"
+	}
+	if t.Conflict == "" {
+		t.Conflict = ConflictSkip
+	}
+
+	var lock sync.Mutex
+	var p KeyTransferProgress
+	lastReport := time.Now()
+	update := func(fn func(p *KeyTransferProgress)) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		fn(&p)
+		if time.Since(lastReport) >= 200*time.Millisecond {
+			lastReport = time.Now()
+			progress(p)
+		}
+	}
+
+	var limiter <-chan time.Time
+	if t.RateLimit > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(t.RateLimit))
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+
+	keys := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < t.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range keys {
+				if limiter != nil {
+					<-limiter
+				}
+
+				renamed, fallback, skipped, err := t.transfer(source, target, key)
+				update(func(p *KeyTransferProgress) {
+					switch {
+					case err != nil:
+						p.Failed++
+						p.LastError = fmt.Sprintf("%s: %s", key, err)
+					case skipped:
+						p.Skipped++
+					default:
+						p.Copied++
+						if renamed {
+							p.Renamed++
+						}
+						if fallback {
+							p.Fallback++
+						}
+					}
+				})
+			}
+		}()
+	}
+
+	var err error
+	scanner := NewKeyScanner(source, t.Pattern)
+scan:
+	for !scanner.Done() {
+		var batch []ScannedKey
+		if batch, err = scanner.Next(1000); err != nil {
+			break
+		}
+
+		update(func(p *KeyTransferProgress) { p.Scanned += int64(len(batch)) })
+		for _, k := range batch {
+			select {
+			case <-stop:
+				err = errors.New("transfer stopped")
+				break scan
+			case keys <- k.Key:
+			}
+		}
+	}
+
+	close(keys)
+	wg.Wait()
+
+	progress(p)
+	return p, err
+}
+
+// transfer copy a key to the target according to the conflict policy
+func (t KeyTransfer) transfer(source, target RedisClient, key string) (renamed, fallback, skipped bool, err error) {
+	exists, err := target.Do("EXISTS", key).Int64()
+	if err != nil {
+		return
+	}
+
+	renamedExists := int64(0)
+	if exists > 0 && t.Conflict == ConflictRename {
+		if renamedExists, err = target.Do("EXISTS", key+t.RenameSuffix).Int64(); err != nil {
+			return
+		}
+	}
+
+	targetKey, replace, skipped := TransferTargetKey(key, exists > 0, renamedExists > 0, t.Conflict, t.RenameSuffix)
+	if skipped || t.DryRun {
+		return targetKey != key, false, skipped, nil
+	}
+
+	renamed = targetKey != key
+
+	pttl, err := source.Do("PTTL", key).Int64()
+	if err != nil {
+		return
+	}
+	if pttl == -2 {
+		// the key is expired or deleted after scanned
+		return renamed, false, true, nil
+	}
+	if pttl < 0 {
+		pttl = 0
+	}
+
+	payload, err := source.Do("DUMP", key).String()
+	if err == redis.Nil {
+		return renamed, false, true, nil
+	}
+	if err != nil {
+		return
+	}
+
+	args := []interface{}{"RESTORE", targetKey, pttl, payload}
+	if replace {
+		args = append(args, "REPLACE")
+	}
+
+	err = target.Do(args...).Err()
+	if err == nil || !restoreIncompatible(err) {
+		return
+	}
+
+	// the payload is not compatible with the target, such as copying to an older version
+	rec, found, err := RedisKeyRecord(source, key)
+	if err != nil {
+		return
+	}
+	if !found {
+		return renamed, false, true, nil
+	}
+
+	rec.Key, rec.TTL = targetKey, pttl
+	commands, err := rec.Commands(replace)
+	if err != nil {
+		return
+	}
+
+	for _, cmd := range commands {
+		if err = target.Do(stringArgs(cmd)...).Err(); err != nil {
+			return
+		}
+	}
+
+	return renamed, true, false, nil
+}
+
+// restoreIncompatible check whether the error of RESTORE is caused by an incompatible payload
+func restoreIncompatible(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "payload version") || strings.Contains(msg, "Bad data format")
+}
@@ -0,0 +1,37 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+func TestTransferTargetKey(t *testing.T) {
+	testcases := []struct {
+		exists, renamedExists bool
+		policy                api.ConflictPolicy
+		target                string
+		replace, skip         bool
+	}{
+		{false, false, api.ConflictSkip, "user:1", false, false},
+		{true, false, api.ConflictSkip, "", false, true},
+		{true, false, api.ConflictOverwrite, "user:1", true, false},
+		{true, false, api.ConflictRename, "user:1:copy", false, false},
+		{true, true, api.ConflictRename, "", false, true},
+	}
+
+	for _, tc := range testcases {
+		target, replace, skip := api.TransferTargetKey("user:1", tc.exists, tc.renamedExists, tc.policy, ":copy")
+		if target != tc.target || replace != tc.replace || skip != tc.skip {
+			t.Errorf("test failed: %+v, got %s, %v, %v", tc, target, replace, skip)
+		}
+	}
+
+	if _, err := api.ParseConflictPolicy("Overwrite"); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := api.ParseConflictPolicy("merge"); err == nil {
+		t.Error("test failed: merge should be invalid")
+	}
+}
@@ -1,10 +1,76 @@
 package config
 
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type Config struct {
 	Host     string
 	Port     int
+	Username string
 	Password string
 	DB       int
 	Cluster  bool
 	Debug    bool
-}
\ No newline at end of file
+	// RDBFile is the RDB file browsed offline instead of connecting to a server
+	RDBFile string
+
+	DashboardInterval time.Duration
+	WatchInterval     time.Duration
+}
+
+// ParseURL create a config from url in the form of redis://[[username]:password@]host[:port][/db][?cluster=true],
+// the scheme can be omitted. Options not related to the connection, such as refresh intervals, are copied from base
+func ParseURL(base Config, rawURL string) (Config, error) {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "redis://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return base, err
+	}
+
+	if u.Scheme != "redis" {
+		return base, fmt.Errorf("unsupported scheme %s", u.Scheme)
+	}
+
+	conf := base
+	conf.Host, conf.Port = u.Hostname(), 6379
+	conf.Username, conf.Password, conf.DB = "", "", 0
+	conf.RDBFile = ""
+	conf.Cluster = u.Query().Get("cluster") == "true"
+
+	if conf.Host == "" {
+		return base, errors.New("host is required")
+	}
+
+	if port := u.Port(); port != "" {
+		if conf.Port, err = strconv.Atoi(port); err != nil {
+			return base, fmt.Errorf("invalid port %s", port)
+		}
+	}
+
+	if u.User != nil {
+		conf.Username = u.User.Username()
+		if password, ok := u.User.Password(); ok {
+			conf.Password = password
+		} else {
+			// redis://password@host is accepted like redis-cli does
+			conf.Username, conf.Password = "", conf.Username
+		}
+	}
+
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if conf.DB, err = strconv.Atoi(db); err != nil {
+			return base, fmt.Errorf("invalid db %s", db)
+		}
+	}
+
+	return conf, nil
+}
@@ -19,6 +19,12 @@ var keyBindings = KeyBindings{
 	"command_result":   {tcell.KeyF5, tcell.KeyCtrlR},
 	"quit":             {tcell.KeyEsc, tcell.KeyCtrlQ},
 	"switch_focus":     {tcell.KeyTab},
+	"tools":            {tcell.KeyF10, tcell.KeyCtrlT},
+	"live_refresh":     {tcell.KeyF8, tcell.KeyCtrlL},
+	"watch":            {tcell.KeyF12, tcell.KeyCtrlW},
+	"switch_db":        {tcell.KeyF11, tcell.KeyCtrlD},
+	"next_tab":         {tcell.KeyCtrlP},
+	"connections":      {tcell.KeyCtrlG},
 }
 
 func NewKeyBinding() KeyBindings {
@@ -3,10 +3,11 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/mylxsw/redis-tui/api"
 	"github.com/mylxsw/redis-tui/config"
-	"github.com/mylxsw/redis-tui/core"
 	"github.com/mylxsw/redis-tui/tui"
 )
 
@@ -18,10 +19,14 @@ var GitCommit string
 func main() {
 	flag.StringVar(&conf.Host, "h", "127.0.0.1", "Server hostname")
 	flag.IntVar(&conf.Port, "p", 6379, "Server port")
+	flag.StringVar(&conf.Username, "user", "", "Username to use when connecting to the server with ACL (redis 6+)")
 	flag.StringVar(&conf.Password, "a", "", "Password to use when connecting to the server")
 	flag.IntVar(&conf.DB, "n", 0, "Database number")
 	flag.BoolVar(&conf.Cluster, "c", false, "Enable cluster mode")
+	flag.StringVar(&conf.RDBFile, "rdb", "", "Browse an RDB file offline (read-only) instead of connecting to a server")
 	flag.BoolVar(&conf.Debug, "vvv", false, "Enable debug mode")
+	flag.DurationVar(&conf.WatchInterval, "watch-interval", 2*time.Second, "Refresh interval of the watched key")
+	flag.DurationVar(&conf.DashboardInterval, "dashboard-interval", 2*time.Second, "Refresh interval of the dashboard page")
 
 	var showVersion bool
 	flag.BoolVar(&showVersion, "v", false, "Show version and exit")
@@ -38,8 +43,16 @@ func main() {
 		return
 	}
 
-	outputChan := make(chan core.OutputMessage, 100)
-	if err := tui.NewRedisTUI(api.NewRedisClient(conf, outputChan), 100, Version, GitCommit, outputChan, conf).Start(); err != nil {
+	if conf.RDBFile != "" {
+		// the file is loaded before starting, so that errors are printed instead of being shown in every command
+		if _, err := api.OpenRDB(conf.RDBFile); err != nil {
+			fmt.Printf("Load RDB file %s failed: %s\n", conf.RDBFile, err)
+			os.Exit(1)
+		}
+		conf.Cluster = false
+	}
+
+	if err := tui.NewWorkspace(100, Version, GitCommit, conf).Start(); err != nil {
 		panic(err)
 	}
 }
@@ -0,0 +1,324 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+const aclLogCount = 50
+
+// createACLTool create a page for managing ACL users
+func (ui *RedisTUI) createACLTool() *toolPage {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.SetBorder(true).SetTitle(" ACL ")
+
+	userTable := tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	userTable.SetBorder(true).SetTitle(" Users ")
+	detailView := tview.NewTextView().SetDynamicColors(true)
+	detailView.SetBorder(true).SetTitle(" Detail ")
+	categoryList := tview.NewList().ShowSecondaryText(false)
+	categoryList.SetBorder(true).SetTitle(" Categories (Enter: +/-/none) ")
+	nameInputField := tview.NewInputField().SetLabel(" User ")
+	rulesInputField := tview.NewInputField().SetLabel(" Rules ").SetPlaceholder("such as on >password ~cache:* +@read, Enter to save the user")
+	keyPatternInputField := tview.NewInputField().SetLabel(" Key pattern ").SetPlaceholder("Enter to add ~pattern to rules")
+	dryRunInputField := tview.NewInputField().SetLabel(" DRYRUN ").SetPlaceholder("command to check for the user, such as SET foo bar")
+	tipView := tview.NewTextView().SetTextColor(tcell.ColorOrange).
+		SetText(" Users: Enter - edit, n - new, d - delete, l - ACL LOG, c - reset log, S - ACL SAVE, L - ACL LOAD, r - refresh")
+
+	var users []api.ACLUser
+	var categories []string
+
+	renderCategories := func() {
+		current := categoryList.GetCurrentItem()
+		categoryList.Clear()
+
+		rules := rulesInputField.GetText()
+		for _, category := range categories {
+			text := fmt.Sprintf("  @%s", category)
+			switch api.ACLCategoryRule(rules, category) {
+			case "+":
+				text = fmt.Sprintf("[green]+@%s", category)
+			case "-":
+				text = fmt.Sprintf("[red]-@%s", category)
+			}
+
+			categoryList.AddItem(text, "", 0, nil)
+		}
+
+		if current < categoryList.GetItemCount() {
+			categoryList.SetCurrentItem(current)
+		}
+	}
+
+	render := func() {
+		row, _ := userTable.GetSelection()
+		userTable.Clear()
+		for i, title := range []string{"User", "Rules"} {
+			userTable.SetCell(0, i, tview.NewTableCell(title).SetTextColor(tcell.ColorYellow).SetSelectable(false))
+		}
+
+		for i, u := range users {
+			color := tcell.ColorGreen
+			if !u.Enabled() {
+				color = tcell.ColorGray
+			}
+
+			userTable.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(u.Name)).SetTextColor(color).SetReference(u))
+			userTable.SetCell(i+1, 1, tview.NewTableCell(tview.Escape(u.Rules)).SetMaxWidth(60).SetExpansion(1))
+		}
+
+		if row > len(users) {
+			row = len(users)
+		}
+		if row < 1 {
+			row = 1
+		}
+		userTable.Select(row, 0)
+
+		flex.SetTitle(fmt.Sprintf(" ACL (%d users) ", len(users)))
+	}
+
+	refresh := func() {
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			res, err := api.RedisACLList(client)
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
+
+			cats, err := api.RedisACLCategories(client)
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+			}
+
+			ui.uiViewUpdateChan <- func() {
+				users, categories = res, cats
+				render()
+				renderCategories()
+			}
+		}()
+	}
+
+	selected := func() (api.ACLUser, bool) {
+		row, _ := userTable.GetSelection()
+		u, ok := userTable.GetCell(row, 0).GetReference().(api.ACLUser)
+		return u, ok
+	}
+
+	showUser := func(name string) {
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			fields, err := api.RedisACLGetUser(client, name)
+			ui.uiViewUpdateChan <- func() {
+				detailView.SetTitle(fmt.Sprintf(" Detail (%s) ", tview.Escape(name)))
+				if err != nil {
+					detailView.SetText(fmt.Sprintf("[red]%s", tview.Escape(err.Error())))
+					return
+				}
+
+				lines := make([]string, 0, len(fields))
+				for _, f := range fields {
+					lines = append(lines, fmt.Sprintf("[yellow]%s:[white] %s", f.Name, tview.Escape(f.Value)))
+				}
+				detailView.SetText(strings.Join(lines, "\n")).ScrollToBeginning()
+			}
+		}()
+	}
+
+	showLog := func() {
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			entries, err := api.RedisACLLog(client, aclLogCount)
+			ui.uiViewUpdateChan <- func() {
+				detailView.SetTitle(fmt.Sprintf(" ACL LOG (%d) ", len(entries)))
+				if err != nil {
+					detailView.SetText(fmt.Sprintf("[red]%s", tview.Escape(err.Error())))
+					return
+				}
+
+				lines := make([]string, 0, len(entries))
+				for _, e := range entries {
+					lines = append(lines, fmt.Sprintf("[yellow]%s[white] %s %s [gray](%s, %d times, %.0fs ago)[white]\n  %s",
+						tview.Escape(e.Username), e.Reason, tview.Escape(e.Object), e.Context, e.Count, e.AgeSeconds, tview.Escape(e.ClientInfo)))
+				}
+				detailView.SetText(strings.Join(lines, "\n")).ScrollToBeginning()
+			}
+		}()
+	}
+
+	// nodeAction run an ACL command changing every node after confirmed
+	nodeAction := func(question string, message string, fn func(client api.RedisClient) error) {
+		ui.confirm(question, func() {
+			client, release := ui.retainClient()
+			go func() {
+				defer release()
+
+				if err := fn(client); err != nil {
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+					return
+				}
+
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: message}
+				ui.uiViewUpdateChan <- refresh
+			}()
+		})
+	}
+
+	userTable.SetSelectionChangedFunc(func(row, column int) {
+		if u, ok := selected(); ok {
+			showUser(u.Name)
+		}
+	})
+
+	userTable.SetSelectedFunc(func(row, column int) {
+		if u, ok := selected(); ok {
+			nameInputField.SetText(u.Name)
+			rulesInputField.SetText(u.Rules)
+			renderCategories()
+			ui.setFocus(rulesInputField)
+		}
+	})
+
+	userTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+
+		switch event.Rune() {
+		case 'n':
+			nameInputField.SetText("")
+			rulesInputField.SetText("on")
+			renderCategories()
+			ui.setFocus(nameInputField)
+		case 'd':
+			if u, ok := selected(); ok {
+				name := u.Name
+				nodeAction(fmt.Sprintf("Delete user %s?", name), fmt.Sprintf("user %s deleted", name), func(client api.RedisClient) error {
+					return api.RedisACLDelUser(client, name)
+				})
+			}
+		case 'l':
+			showLog()
+		case 'c':
+			nodeAction("Reset the ACL LOG?", "ACL LOG reset", api.RedisACLLogReset)
+		case 'S':
+			nodeAction("Save ACLs to the acl file (ACL SAVE)?", "ACLs saved", api.RedisACLSave)
+		case 'L':
+			nodeAction("Reload ACLs from the acl file (ACL LOAD)? Unsaved changes will be lost", "ACLs loaded", api.RedisACLLoad)
+		case 'r':
+			refresh()
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	nameInputField.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			ui.setFocus(rulesInputField)
+		}
+	})
+
+	rulesInputField.SetChangedFunc(func(text string) {
+		renderCategories()
+	})
+
+	rulesInputField.SetDoneFunc(func(key tcell.Key) {
+		name, rules := strings.TrimSpace(nameInputField.GetText()), strings.TrimSpace(rulesInputField.GetText())
+		if key != tcell.KeyEnter || name == "" {
+			return
+		}
+
+		nodeAction(fmt.Sprintf("Set user %s with rules: %s?", name, rules), fmt.Sprintf("user %s saved", name), func(client api.RedisClient) error {
+			if err := api.RedisACLSetUser(client, name, rules); err != nil {
+				return err
+			}
+
+			showUser(name)
+			return nil
+		})
+	})
+
+	categoryList.SetSelectedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
+		if index < len(categories) {
+			rulesInputField.SetText(api.ToggleACLCategory(rulesInputField.GetText(), categories[index]))
+		}
+	})
+
+	keyPatternInputField.SetDoneFunc(func(key tcell.Key) {
+		pattern := strings.TrimSpace(keyPatternInputField.GetText())
+		if key != tcell.KeyEnter || pattern == "" {
+			return
+		}
+
+		rulesInputField.SetText(api.AddACLKeyPattern(rulesInputField.GetText(), pattern))
+		keyPatternInputField.SetText("")
+	})
+
+	dryRunInputField.SetDoneFunc(func(key tcell.Key) {
+		name, command := strings.TrimSpace(nameInputField.GetText()), strings.TrimSpace(dryRunInputField.GetText())
+		if key != tcell.KeyEnter || command == "" {
+			return
+		}
+
+		if name == "" {
+			if u, ok := selected(); ok {
+				name = u.Name
+			}
+		}
+
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			allowed, message, err := api.RedisACLDryRun(client, name, command)
+			switch {
+			case err != nil:
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+			case allowed:
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("user %s is allowed to run: %s", name, command)}
+			default:
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorYellow, Message: fmt.Sprintf("user %s is denied: %s", name, message)}
+			}
+		}()
+	})
+
+	right := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(detailView, 0, 1, false).
+		AddItem(categoryList, 0, 1, false)
+
+	body := tview.NewFlex().
+		AddItem(userTable, 0, 3, false).
+		AddItem(right, 0, 2, false)
+
+	flex.AddItem(body, 0, 1, false).
+		AddItem(nameInputField, 1, 0, false).
+		AddItem(rulesInputField, 1, 0, false).
+		AddItem(keyPatternInputField, 1, 0, false).
+		AddItem(dryRunInputField, 1, 0, false).
+		AddItem(tipView, 1, 0, false)
+
+	return &toolPage{
+		name:      "acl",
+		title:     "ACL",
+		primitive: flex,
+		focus: []tview.Primitive{
+			userTable, nameInputField, rulesInputField, categoryList,
+			keyPatternInputField, dryRunInputField, detailView,
+		},
+		onOpen: refresh,
+	}
+}
@@ -0,0 +1,329 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+const clientListRefreshInterval = 3 * time.Second
+
+// clientSorters are the orders can be used for client list, switched by pressing s
+var clientSorters = []struct {
+	name string
+	less func(a, b api.ClientInfo) bool
+}{
+	{name: "id", less: func(a, b api.ClientInfo) bool { return a.ID < b.ID }},
+	{name: "age", less: func(a, b api.ClientInfo) bool { return a.Age > b.Age }},
+	{name: "idle", less: func(a, b api.ClientInfo) bool { return a.Idle > b.Idle }},
+	{name: "memory", less: func(a, b api.ClientInfo) bool { return a.Memory > b.Memory }},
+	{name: "addr", less: func(a, b api.ClientInfo) bool { return a.Addr < b.Addr }},
+	{name: "name", less: func(a, b api.ClientInfo) bool { return a.Name < b.Name }},
+}
+
+// createClientListTool create a page for CLIENT LIST, CLIENT KILL and CLIENT PAUSE
+func (ui *RedisTUI) createClientListTool() *toolPage {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.SetBorder(true).SetTitle(" Clients ")
+
+	filterInputField := tview.NewInputField().SetLabel(" Filter ")
+	pauseInputField := tview.NewInputField().SetLabel(" Pause (ms) ").SetText("5000").
+		SetAcceptanceFunc(tview.InputFieldInteger)
+	resultTable := tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	tipView := tview.NewTextView().SetTextColor(tcell.ColorOrange).
+		SetText(" Space - mark, k - kill marked or selected, p - pause, u - unpause, s - sort, a - auto refresh, r - refresh")
+
+	var clients []api.ClientInfo
+	marked := make(map[string]bool)
+	sorterIndex := 0
+	autoRefresh := true
+
+	clientID := func(c api.ClientInfo) string {
+		return fmt.Sprintf("%s/%d", c.Node, c.ID)
+	}
+
+	render := func() {
+		filter := strings.ToLower(filterInputField.GetText())
+		sorter := clientSorters[sorterIndex]
+
+		matched := make([]api.ClientInfo, 0, len(clients))
+		for _, c := range clients {
+			if filter == "" ||
+				strings.Contains(strings.ToLower(c.Name), filter) ||
+				strings.Contains(strings.ToLower(c.Addr), filter) ||
+				strings.Contains(strings.ToLower(c.Cmd), filter) {
+				matched = append(matched, c)
+			}
+		}
+		sort.SliceStable(matched, func(i, j int) bool {
+			return sorter.less(matched[i], matched[j])
+		})
+
+		autoRefreshStatus := "off"
+		if autoRefresh {
+			autoRefreshStatus = clientListRefreshInterval.String()
+		}
+		flex.SetTitle(fmt.Sprintf(" Clients (%d/%d, sorted by %s, %d marked, auto refresh: %s) ", len(matched), len(clients), sorter.name, len(marked), autoRefreshStatus))
+
+		row, _ := resultTable.GetSelection()
+		resultTable.Clear()
+		for i, title := range []string{" ", "Node", "ID", "Addr", "Name", "Age", "Idle", "DB", "Cmd", "Memory", "Flags"} {
+			resultTable.SetCell(0, i, tview.NewTableCell(title).SetTextColor(tcell.ColorYellow).SetSelectable(false))
+		}
+
+		for i, c := range matched {
+			color := tcell.ColorWhite
+			mark := " "
+			if marked[clientID(c)] {
+				color = tcell.ColorRed
+				mark = "*"
+			}
+
+			cells := []string{
+				mark,
+				c.Node,
+				strconv.FormatInt(c.ID, 10),
+				c.Addr,
+				c.Name,
+				c.Age.String(),
+				c.Idle.String(),
+				strconv.Itoa(c.DB),
+				c.Cmd,
+				formatBytes(float64(c.Memory)),
+				c.Flags,
+			}
+			for j, text := range cells {
+				cell := tview.NewTableCell(text).SetTextColor(color)
+				if j == 0 {
+					cell.SetReference(c)
+				}
+				resultTable.SetCell(i+1, j, cell)
+			}
+		}
+
+		if row >= resultTable.GetRowCount() {
+			row = resultTable.GetRowCount() - 1
+		}
+		if row > 0 {
+			resultTable.Select(row, 0)
+		}
+	}
+
+	refresh := func() {
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			res, err := api.RedisClientList(client)
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
+
+			ui.uiViewUpdateChan <- func() {
+				clients = res
+
+				// remove marks of the connections already closed
+				exists := make(map[string]bool)
+				for _, c := range clients {
+					exists[clientID(c)] = true
+				}
+				for id := range marked {
+					if !exists[id] {
+						delete(marked, id)
+					}
+				}
+
+				render()
+			}
+		}()
+	}
+
+	selectedClient := func() (api.ClientInfo, bool) {
+		row, _ := resultTable.GetSelection()
+		c, ok := resultTable.GetCell(row, 0).GetReference().(api.ClientInfo)
+		return c, ok
+	}
+
+	kill := func() {
+		targets := make([]api.ClientInfo, 0)
+		for _, c := range clients {
+			if marked[clientID(c)] {
+				targets = append(targets, c)
+			}
+		}
+		if len(targets) == 0 {
+			if c, ok := selectedClient(); ok {
+				targets = append(targets, c)
+			}
+		}
+		if len(targets) == 0 {
+			return
+		}
+
+		addrs := make([]string, len(targets))
+		for i, c := range targets {
+			addrs[i] = c.Addr
+		}
+
+		ui.confirm(fmt.Sprintf("Kill %d connection(s)?\n%s", len(targets), strings.Join(limit(addrs, 5), ", ")), func() {
+			client, release := ui.retainClient()
+			go func() {
+				defer release()
+
+				for _, c := range targets {
+					if err := api.RedisClientKill(client, c); err != nil {
+						ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: kill %s failed: %s", c.Addr, err)}
+						continue
+					}
+
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("client %s (id=%d) killed", c.Addr, c.ID)}
+				}
+
+				ui.uiViewUpdateChan <- func() {
+					for _, c := range targets {
+						delete(marked, clientID(c))
+					}
+					refresh()
+				}
+			}()
+		})
+	}
+
+	pause := func() {
+		ms, _ := strconv.Atoi(pauseInputField.GetText())
+		if ms <= 0 {
+			ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: "errors: pause duration must be a positive number"}
+			return
+		}
+
+		duration := time.Duration(ms) * time.Millisecond
+		ui.confirm(fmt.Sprintf("Pause all clients for %s?", duration), func() {
+			client, release := ui.retainClient()
+			go func() {
+				defer release()
+
+				if err := api.RedisClientPause(client, duration, false); err != nil {
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+					return
+				}
+
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("clients paused for %s", duration)}
+			}()
+		})
+	}
+
+	unpause := func() {
+		ui.confirm("Resume all paused clients?", func() {
+			client, release := ui.retainClient()
+			go func() {
+				defer release()
+
+				if err := api.RedisClientUnpause(client); err != nil {
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+					return
+				}
+
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: "clients unpaused"}
+			}()
+		})
+	}
+
+	filterInputField.SetChangedFunc(func(text string) {
+		render()
+	})
+	filterInputField.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			ui.setFocus(resultTable)
+		}
+	})
+	pauseInputField.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			pause()
+		}
+	})
+
+	resultTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+
+		switch event.Rune() {
+		case ' ':
+			if c, ok := selectedClient(); ok {
+				if marked[clientID(c)] {
+					delete(marked, clientID(c))
+				} else {
+					marked[clientID(c)] = true
+				}
+				render()
+			}
+		case 'k':
+			kill()
+		case 'p':
+			pause()
+		case 'u':
+			unpause()
+		case 's':
+			sorterIndex = (sorterIndex + 1) % len(clientSorters)
+			render()
+		case 'a':
+			autoRefresh = !autoRefresh
+			render()
+		case 'r':
+			refresh()
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	inputs := tview.NewFlex().
+		AddItem(filterInputField, 0, 2, false).
+		AddItem(pauseInputField, 0, 1, false)
+
+	flex.AddItem(inputs, 1, 0, false).
+		AddItem(resultTable, 0, 1, false).
+		AddItem(tipView, 1, 0, false)
+
+	var stopChan chan struct{}
+	return &toolPage{
+		name:      "clients",
+		title:     "Clients",
+		primitive: flex,
+		focus:     []tview.Primitive{resultTable, filterInputField, pauseInputField},
+		onOpen: func() {
+			refresh()
+
+			stopChan = make(chan struct{})
+			go func(stop chan struct{}) {
+				ticker := time.NewTicker(clientListRefreshInterval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-stop:
+						return
+					case <-ticker.C:
+						ui.uiViewUpdateChan <- func() {
+							if autoRefresh {
+								refresh()
+							}
+						}
+					}
+				}
+			}(stopChan)
+		},
+		onClose: func() {
+			close(stopChan)
+		},
+	}
+}
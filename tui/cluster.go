@@ -0,0 +1,472 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+// slotMapColors are the colors of masters in slot map
+var slotMapColors = []string{"green", "blue", "fuchsia", "teal", "olive", "purple", "navy", "lime", "aqua", "maroon"}
+
+const (
+	slotStable = iota
+	slotMigrating
+	slotFailing
+)
+
+// slotMap is a primitive which draws the owners of all the hash slots in cluster
+type slotMap struct {
+	*tview.Box
+
+	owners [api.ClusterSlots]int
+	states [api.ClusterSlots]int
+	colors []string
+}
+
+// newSlotMap create an empty slot map
+func newSlotMap() *slotMap {
+	m := &slotMap{Box: tview.NewBox()}
+	m.SetBorder(true).SetTitle(" Slots ")
+	m.SetNodes(nil)
+
+	return m
+}
+
+// SetNodes update the slot owners by the masters of cluster, the index of master in nodes is used
+// for choosing the color of its slots
+func (m *slotMap) SetNodes(masters []api.ClusterNode) {
+	for i := range m.owners {
+		m.owners[i] = -1
+		m.states[i] = slotStable
+	}
+
+	m.colors = make([]string, len(masters))
+	for i, n := range masters {
+		m.colors[i] = slotMapColors[i%len(slotMapColors)]
+		for _, r := range n.Slots {
+			for s := r.Start; s <= r.End && s < api.ClusterSlots; s++ {
+				m.owners[s] = i
+				if n.Failing() {
+					m.states[s] = slotFailing
+				}
+			}
+		}
+	}
+
+	for _, n := range masters {
+		for s := range n.Migrating {
+			m.states[s] = slotMigrating
+		}
+		for s := range n.Importing {
+			m.states[s] = slotMigrating
+		}
+	}
+}
+
+// Color return the color name of the master at index i
+func (m *slotMap) Color(i int) string {
+	return m.colors[i]
+}
+
+// Draw draws this primitive onto the screen
+func (m *slotMap) Draw(screen tcell.Screen) {
+	m.Box.Draw(screen)
+
+	x, y, width, height := m.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	cells := width * height
+	slotsPerCell := (api.ClusterSlots + cells - 1) / cells
+	m.SetTitle(fmt.Sprintf(" Slots (%d per cell) ", slotsPerCell))
+
+	for cell := 0; cell*slotsPerCell < api.ClusterSlots; cell++ {
+		start, end := cell*slotsPerCell, (cell+1)*slotsPerCell
+		if end > api.ClusterSlots {
+			end = api.ClusterSlots
+		}
+
+		// a cell is drawn by the most serious state of its slots, then by the owner of its first slot
+		ch, style := '·', tcell.StyleDefault.Foreground(tcell.ColorGray)
+		if owner := m.owners[start]; owner >= 0 {
+			ch, style = '█', tcell.StyleDefault.Foreground(tcell.GetColor(m.colors[owner]))
+		}
+
+		for s := start; s < end; s++ {
+			if m.owners[s] < 0 {
+				ch, style = '·', tcell.StyleDefault.Foreground(tcell.ColorGray)
+			}
+			if m.states[s] == slotMigrating {
+				ch, style = '▒', tcell.StyleDefault.Foreground(tcell.ColorYellow)
+				break
+			}
+			if m.states[s] == slotFailing {
+				ch, style = '▓', tcell.StyleDefault.Foreground(tcell.ColorRed)
+			}
+		}
+
+		screen.SetContent(x+cell%width, y+cell/width, ch, nil, style)
+	}
+}
+
+// shortNodeID return the first 8 characters of a node id
+func shortNodeID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+
+	return id
+}
+
+// createClusterTool create a page for displaying the topology and slot map of cluster
+func (ui *RedisTUI) createClusterTool() *toolPage {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.SetBorder(true).SetTitle(" Cluster ")
+
+	nodeTable := tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	slots := newSlotMap()
+	legendView := tview.NewTextView().SetDynamicColors(true)
+	legendView.SetBorder(true).SetTitle(" Legend ")
+	tipView := tview.NewTextView().SetTextColor(tcell.ColorOrange).
+		SetText(" r - refresh, s - migrate from, t - migrate to, R - resume migration of node, x - stop migration; red rows are failing nodes, yellow rows have migrating or importing slots")
+
+	sourceInputField := tview.NewInputField().SetLabel(" From ")
+	targetInputField := tview.NewInputField().SetLabel(" To ")
+	slotsInputField := tview.NewInputField().SetLabel(" Slots ").SetPlaceholder("0-100,200")
+	batchInputField := tview.NewInputField().SetLabel(" Batch ").SetText("100").
+		SetAcceptanceFunc(tview.InputFieldInteger)
+	throttleInputField := tview.NewInputField().SetLabel(" Throttle (ms) ").SetText("0").
+		SetAcceptanceFunc(tview.InputFieldInteger)
+	dryRunCheckbox := tview.NewCheckbox().SetLabel(" Dry-run ").SetChecked(true)
+	migrationGauge := newGauge("Migration", tcell.ColorGreen)
+	migrationLogView := tview.NewTextView().SetDynamicColors(true)
+	migrationLogView.SetBorder(true).SetTitle(" Migration Log ")
+
+	var loading sync.Mutex
+
+	render := func(nodes []api.ClusterNode, infos map[string]*api.ServerInfo) {
+		masters := make([]api.ClusterNode, 0)
+		for _, n := range nodes {
+			if n.IsMaster() {
+				masters = append(masters, n)
+			}
+		}
+		slots.SetNodes(masters)
+
+		nodeTable.Clear()
+		for i, title := range []string{"ID", "Addr", "Role", "Replica Of", "Slots", "Slot Ranges", "Link", "Epoch", "Health", "Memory", "Keys", "Flags"} {
+			nodeTable.SetCell(0, i, tview.NewTableCell(title).SetTextColor(tcell.ColorYellow).SetSelectable(false))
+		}
+
+		ids := make(map[string]string)
+		for _, n := range nodes {
+			ids[n.ID] = n.Addr
+		}
+
+		legend := make([]string, 0)
+		unassigned := api.ClusterSlots
+		for i, n := range masters {
+			unassigned -= n.SlotCount()
+			legend = append(legend, fmt.Sprintf("[%s]█[white] %s (%d)", slots.Color(i), n.Addr, n.SlotCount()))
+		}
+		legend = append(legend, fmt.Sprintf("[gray]·[white] unassigned (%d)", unassigned))
+		legend = append(legend, "[yellow]▒[white] migrating/importing", "[red]▓[white] failing")
+		legendView.SetText(strings.Join(legend, "\n"))
+
+		for i, n := range nodes {
+			color := tcell.ColorWhite
+			if len(n.Migrating) > 0 || len(n.Importing) > 0 {
+				color = tcell.ColorYellow
+			}
+			if n.Failing() {
+				color = tcell.ColorRed
+			}
+
+			replicaOf := ""
+			if n.MasterID != "" {
+				replicaOf = ids[n.MasterID]
+				if replicaOf == "" {
+					replicaOf = shortNodeID(n.MasterID)
+				}
+			}
+
+			slotRanges := n.SlotRanges()
+			for s, target := range n.Migrating {
+				slotRanges += fmt.Sprintf(" [%d->%s]", s, shortNodeID(target))
+			}
+			for s, source := range n.Importing {
+				slotRanges += fmt.Sprintf(" [%d<-%s]", s, shortNodeID(source))
+			}
+
+			memory, keys := "", ""
+			if info, ok := infos[n.Addr]; ok {
+				memory = formatBytes(float64(info.Int64("used_memory")))
+				total := int64(0)
+				for _, ks := range info.Keyspace() {
+					total += ks.Keys
+				}
+				keys = formatNumber(float64(total))
+			}
+
+			cells := []string{
+				shortNodeID(n.ID), n.Addr, n.Role(), replicaOf, fmt.Sprintf("%d", n.SlotCount()), slotRanges,
+				n.LinkState, fmt.Sprintf("%d", n.ConfigEpoch), n.Health, memory, keys, strings.Join(n.Flags, ","),
+			}
+			for j, text := range cells {
+				cell := tview.NewTableCell(tview.Escape(text)).SetTextColor(color)
+				if j == 0 {
+					cell.SetReference(n)
+				}
+				if j == 5 {
+					cell.SetMaxWidth(40)
+				}
+				nodeTable.SetCell(i+1, j, cell)
+			}
+		}
+
+		flex.SetTitle(fmt.Sprintf(" Cluster (%d masters, %d nodes) ", len(masters), len(nodes)))
+	}
+
+	refresh := func() {
+		if !ui.config.Cluster {
+			legendView.SetText("[red]cluster mode is not enabled, start with -c to connect to a cluster")
+			return
+		}
+
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			loading.Lock()
+			defer loading.Unlock()
+
+			nodes, err := api.RedisClusterNodes(client)
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
+
+			infos := make(map[string]*api.ServerInfo)
+			nodeInfos, err := api.RedisAllNodeServerInfos(client)
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+			}
+			for _, n := range nodeInfos {
+				infos[n.Addr] = n.Info
+			}
+
+			ui.uiViewUpdateChan <- func() {
+				render(nodes, infos)
+			}
+		}()
+	}
+
+	var migrationStop chan struct{}
+	migrationLogs := make([]string, 0)
+	migrationLog := func(color string, format string, args ...interface{}) {
+		migrationLogs = append(migrationLogs, fmt.Sprintf("[gray]%s[%s] %s", time.Now().Format("15:04:05"), color, tview.Escape(fmt.Sprintf(format, args...))))
+		if len(migrationLogs) > 1000 {
+			migrationLogs = migrationLogs[len(migrationLogs)-1000:]
+		}
+		migrationLogView.SetText(strings.Join(migrationLogs, "\n")).ScrollToEnd()
+	}
+
+	runMigration := func(migration api.SlotMigration) {
+		stop := make(chan struct{})
+		migrationStop = stop
+		migrationGauge.SetRatio(0, "")
+		migrationLog("white", "migrate %d slots from %s to %s (dry-run: %v)", len(migration.Slots), migration.Source, migration.Target, migration.DryRun)
+
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			err := migration.Run(client, func(p api.SlotMigrationProgress) {
+				ui.uiViewUpdateChan <- func() {
+					migrationGauge.SetRatio(float64(p.SlotsDone)/float64(p.SlotsTotal), fmt.Sprintf("%d/%d slots, %d keys", p.SlotsDone, p.SlotsTotal, p.KeysMoved))
+					migrationLog("white", "%s", p.Message)
+				}
+			}, stop)
+
+			ui.uiViewUpdateChan <- func() {
+				if migrationStop == stop {
+					migrationStop = nil
+				}
+
+				if err != nil {
+					migrationLog("red", "errors: %s", err)
+				} else {
+					migrationLog("green", "migration finished")
+				}
+
+				if !migration.DryRun {
+					refresh()
+				}
+			}
+		}()
+	}
+
+	startMigration := func() {
+		if migrationStop != nil {
+			migrationLog("red", "errors: a migration is running, press x on node table to stop it")
+			return
+		}
+
+		slotList, err := api.ParseSlots(slotsInputField.GetText())
+		if err != nil {
+			migrationLog("red", "errors: %s", err)
+			return
+		}
+
+		batch, _ := strconv.Atoi(batchInputField.GetText())
+		throttle, _ := strconv.Atoi(throttleInputField.GetText())
+		migration := api.SlotMigration{
+			Source:    strings.TrimSpace(sourceInputField.GetText()),
+			Target:    strings.TrimSpace(targetInputField.GetText()),
+			Slots:     slotList,
+			BatchSize: batch,
+			Throttle:  time.Duration(throttle) * time.Millisecond,
+			Username:  ui.config.Username,
+			Password:  ui.config.Password,
+			DryRun:    dryRunCheckbox.IsChecked(),
+		}
+
+		if migration.DryRun {
+			runMigration(migration)
+			return
+		}
+
+		ui.confirm(fmt.Sprintf("Migrate %d slots from %s to %s?", len(slotList), migration.Source, migration.Target), func() {
+			runMigration(migration)
+		})
+	}
+
+	for _, field := range []*tview.InputField{sourceInputField, targetInputField, slotsInputField, batchInputField, throttleInputField} {
+		field.SetDoneFunc(func(key tcell.Key) {
+			if key == tcell.KeyEnter {
+				startMigration()
+			}
+		})
+	}
+
+	nodeTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+
+		row, _ := nodeTable.GetSelection()
+		node, selected := nodeTable.GetCell(row, 0).GetReference().(api.ClusterNode)
+
+		switch event.Rune() {
+		case 'r':
+			refresh()
+		case 's':
+			if selected {
+				sourceInputField.SetText(node.Addr)
+			}
+		case 't':
+			if selected {
+				targetInputField.SetText(node.Addr)
+			}
+		case 'R':
+			// a migration stopped after the importing state is set on target but before the
+			// migrating state is set on source is resumed from the target
+			states, source, target := node.Migrating, node.Addr, ""
+			if len(states) == 0 {
+				states, target = node.Importing, node.Addr
+			}
+			if !selected || len(states) == 0 {
+				migrationLog("red", "errors: the selected node has no migrating or importing slot")
+				return nil
+			}
+
+			pending := make([]int, 0, len(states))
+			for s := range states {
+				pending = append(pending, s)
+			}
+			sort.Ints(pending)
+
+			// all the migrating slots of a node are usually moved to the same target, and the
+			// importing slots are usually from the same source
+			peer := states[pending[0]]
+			slotTexts := make([]string, 0, len(pending))
+			for _, s := range pending {
+				if states[s] == peer {
+					slotTexts = append(slotTexts, strconv.Itoa(s))
+				}
+			}
+
+			if target == "" {
+				target = peer
+			} else {
+				source = peer
+			}
+
+			sourceInputField.SetText(source)
+			targetInputField.SetText(target)
+			slotsInputField.SetText(strings.Join(slotTexts, ","))
+			dryRunCheckbox.SetChecked(false)
+			ui.setFocus(slotsInputField)
+		case 'x':
+			if migrationStop != nil {
+				close(migrationStop)
+				migrationStop = nil
+				migrationLog("yellow", "stopping migration after current batch")
+			}
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	body := tview.NewFlex().
+		AddItem(slots, 0, 1, false).
+		AddItem(legendView, 36, 0, false)
+
+	migrationForm := tview.NewFlex().
+		AddItem(sourceInputField, 0, 2, false).
+		AddItem(targetInputField, 0, 2, false).
+		AddItem(slotsInputField, 0, 2, false).
+		AddItem(batchInputField, 14, 0, false).
+		AddItem(throttleInputField, 22, 0, false).
+		AddItem(dryRunCheckbox, 12, 0, false)
+
+	migrationPanel := tview.NewFlex().
+		AddItem(migrationLogView, 0, 1, false).
+		AddItem(migrationGauge, 40, 0, false)
+
+	flex.AddItem(nodeTable, 0, 2, false).
+		AddItem(body, 0, 2, false).
+		AddItem(migrationForm, 1, 0, false).
+		AddItem(migrationPanel, 0, 1, false).
+		AddItem(tipView, 1, 0, false)
+
+	return &toolPage{
+		name:      "cluster",
+		title:     "Cluster",
+		primitive: flex,
+		focus: []tview.Primitive{
+			nodeTable, sourceInputField, targetInputField, slotsInputField,
+			batchInputField, throttleInputField, dryRunCheckbox, migrationLogView,
+		},
+		onOpen: refresh,
+		busy: func() string {
+			if migrationStop != nil {
+				return "slot migration"
+			}
+
+			return ""
+		},
+	}
+}
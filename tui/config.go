@@ -0,0 +1,231 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+// createConfigTool create a page for viewing and editing config parameters by CONFIG GET/SET
+func (ui *RedisTUI) createConfigTool() *toolPage {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.SetBorder(true).SetTitle(" Config ")
+
+	filterInputField := tview.NewInputField().SetLabel(" Filter ")
+	resultTable := tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	hintView := tview.NewTextView().SetDynamicColors(true)
+	valueInputField := tview.NewInputField().SetLabel(" Value ")
+	tipView := tview.NewTextView().SetTextColor(tcell.ColorOrange).
+		SetText(" Enter - edit, d - modified only, w - CONFIG REWRITE, R - CONFIG RESETSTAT, r - refresh")
+
+	var params []api.ConfigParameter
+	// node is the address of the node which params are read from, CONFIG SET is sent to every node
+	node := ""
+	modifiedOnly := false
+	editing := ""
+
+	render := func() {
+		filter := strings.ToLower(filterInputField.GetText())
+
+		row, _ := resultTable.GetSelection()
+		resultTable.Clear()
+		for i, title := range []string{"Name", "Value", "Default", "Type"} {
+			resultTable.SetCell(0, i, tview.NewTableCell(title).SetTextColor(tcell.ColorYellow).SetSelectable(false))
+		}
+
+		count := 0
+		for _, p := range params {
+			if filter != "" && !strings.Contains(p.Name, filter) && !strings.Contains(strings.ToLower(p.Value), filter) {
+				continue
+			}
+			if modifiedOnly && !p.Modified() {
+				continue
+			}
+
+			color := tcell.ColorWhite
+			if p.Modified() {
+				color = tcell.ColorYellow
+			}
+
+			defaultValue, typ := "-", "-"
+			if p.Known {
+				defaultValue, typ = p.Spec.Default, string(p.Spec.Type)
+			}
+
+			count++
+			resultTable.SetCell(count, 0, tview.NewTableCell(p.Name).SetTextColor(color).SetReference(p))
+			resultTable.SetCell(count, 1, tview.NewTableCell(tview.Escape(p.Value)).SetTextColor(color).SetMaxWidth(50).SetExpansion(1))
+			resultTable.SetCell(count, 2, tview.NewTableCell(tview.Escape(defaultValue)).SetMaxWidth(30))
+			resultTable.SetCell(count, 3, tview.NewTableCell(typ))
+		}
+
+		if row > count {
+			row = count
+		}
+		if row < 1 {
+			row = 1
+		}
+		resultTable.Select(row, 0)
+
+		flex.SetTitle(fmt.Sprintf(" Config of %s (%d/%d, modified only: %v) ", node, count, len(params), modifiedOnly))
+	}
+
+	refresh := func() {
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			res, addr, err := api.RedisConfigs(client)
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
+
+			ui.uiViewUpdateChan <- func() {
+				params, node = res, addr
+				render()
+			}
+		}()
+	}
+
+	selected := func() (api.ConfigParameter, bool) {
+		row, _ := resultTable.GetSelection()
+		p, ok := resultTable.GetCell(row, 0).GetReference().(api.ConfigParameter)
+		return p, ok
+	}
+
+	showHint := func(p api.ConfigParameter) {
+		if !p.Known {
+			hintView.SetText(fmt.Sprintf(" [yellow]%s[white]: no type information, the value is sent as it is", p.Name))
+			return
+		}
+
+		hint := fmt.Sprintf(" [yellow]%s[white]: %s, default %q", p.Name, p.Spec.Type, p.Spec.Default)
+		switch p.Spec.Type {
+		case api.ConfigTypeBool:
+			hint += ", yes or no"
+		case api.ConfigTypeMemory:
+			hint += ", bytes or with unit such as 100mb, 2gb"
+		case api.ConfigTypeEnum:
+			hint += ", one of " + strings.Join(p.Spec.Options, ", ")
+		}
+
+		hintView.SetText(hint)
+	}
+
+	filterInputField.SetChangedFunc(func(text string) {
+		render()
+	})
+
+	resultTable.SetSelectionChangedFunc(func(row, column int) {
+		if p, ok := selected(); ok {
+			showHint(p)
+		}
+	})
+
+	resultTable.SetSelectedFunc(func(row, column int) {
+		if p, ok := selected(); ok {
+			editing = p.Name
+			valueInputField.SetLabel(fmt.Sprintf(" %s = ", p.Name)).SetText(p.Value)
+			ui.setFocus(valueInputField)
+		}
+	})
+
+	valueInputField.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter || editing == "" {
+			return
+		}
+
+		name, value := editing, valueInputField.GetText()
+		if err := api.ValidateConfigValue(name, value); err != nil {
+			hintView.SetText(fmt.Sprintf(" [red]errors: %s", tview.Escape(err.Error())))
+			return
+		}
+
+		editing = ""
+		valueInputField.SetLabel(" Value ").SetText("")
+		ui.setFocus(resultTable)
+
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			if err := api.RedisConfigSet(client, name, value); err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
+
+			ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("config %s is set to %s", name, value)}
+			ui.uiViewUpdateChan <- refresh
+		}()
+	})
+
+	resultTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+
+		switch event.Rune() {
+		case 'd':
+			modifiedOnly = !modifiedOnly
+			render()
+		case 'r':
+			refresh()
+		case 'w':
+			question := "Rewrite the config file with current config (CONFIG REWRITE)?"
+			if ui.config.Cluster {
+				question = "Rewrite the config files of every node in cluster, replicas included, with their own current config (CONFIG REWRITE)?"
+			}
+
+			ui.confirm(question, func() {
+				client, release := ui.retainClient()
+				go func() {
+					defer release()
+
+					if err := api.RedisConfigRewrite(client); err != nil {
+						ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+						return
+					}
+
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: "config file rewritten"}
+				}()
+			})
+		case 'R':
+			ui.confirm("Reset the statistics reported by INFO (CONFIG RESETSTAT)?", func() {
+				client, release := ui.retainClient()
+				go func() {
+					defer release()
+
+					if err := api.RedisConfigResetStat(client); err != nil {
+						ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+						return
+					}
+
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: "statistics reset"}
+				}()
+			})
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	flex.AddItem(filterInputField, 1, 0, false).
+		AddItem(resultTable, 0, 1, false).
+		AddItem(hintView, 1, 0, false).
+		AddItem(valueInputField, 1, 0, false).
+		AddItem(tipView, 1, 0, false)
+
+	return &toolPage{
+		name:      "config",
+		title:     "Config",
+		primitive: flex,
+		focus:     []tview.Primitive{resultTable, filterInputField, valueInputField},
+		onOpen:    refresh,
+	}
+}
@@ -0,0 +1,265 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+const sparklineCapacity = 300
+
+// memoryUsage return the memory usage ratio and its description for the memory gauge. maxmemory and
+// system memory are limits of every node, so the node using the most of its limit is displayed in
+// cluster mode
+func memoryUsage(info *api.ServerInfo) (float64, string) {
+	ratio, text := 0.0, ""
+	limited := false
+	for _, node := range info.Nodes {
+		used := node.Info.Float64("used_memory")
+		limit, limitName := node.Info.Float64("maxmemory"), "maxmemory"
+		if limit <= 0 {
+			limit, limitName = node.Info.Float64("total_system_memory"), "system"
+		}
+
+		if limit <= 0 {
+			if !limited && text == "" {
+				text = formatBytes(used)
+			}
+			continue
+		}
+
+		if r := used / limit; !limited || r > ratio {
+			ratio, limited = r, true
+			text = fmt.Sprintf("%s / %s (%s)", formatBytes(used), formatBytes(limit), limitName)
+			if len(info.Nodes) > 1 {
+				text += " on " + node.Addr
+			}
+		}
+	}
+
+	return ratio, text
+}
+
+// createDashboardTool create a page which polls INFO and draws the server metrics
+func (ui *RedisTUI) createDashboardTool() *toolPage {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.SetBorder(true)
+
+	interval := int64(ui.config.DashboardInterval)
+	if interval <= 0 {
+		interval = int64(2 * time.Second)
+	}
+
+	updateTitle := func() {
+		flex.SetTitle(fmt.Sprintf(" Dashboard (every %s) ", time.Duration(atomic.LoadInt64(&interval))))
+	}
+	updateTitle()
+
+	memoryGauge := newGauge("Memory Usage", tcell.ColorGreen)
+	hitRatioGauge := newGauge("Hit Ratio", tcell.ColorBlue)
+
+	opsLine := newSparkline("Ops/sec", sparklineCapacity, tcell.ColorGreen, formatNumber)
+	memoryLine := newSparkline("Memory", sparklineCapacity, tcell.ColorYellow, formatBytes)
+	hitRatioLine := newSparkline("Hit Ratio %", sparklineCapacity, tcell.ColorBlue, formatNumber)
+	clientsLine := newSparkline("Clients", sparklineCapacity, tcell.ColorOrange, formatNumber)
+	evictedLine := newSparkline("Evictions/sec", sparklineCapacity, tcell.ColorRed, formatNumber)
+	expiredLine := newSparkline("Expired/sec", sparklineCapacity, tcell.ColorPurple, formatNumber)
+	replOffsetLine := newSparkline("Repl Offset", sparklineCapacity, tcell.ColorTeal, formatNumber)
+	netInputLine := newSparkline("Net In KB/s", sparklineCapacity, tcell.ColorLightGreen, formatNumber)
+	netOutputLine := newSparkline("Net Out KB/s", sparklineCapacity, tcell.ColorLightBlue, formatNumber)
+
+	sparklines := []*sparkline{opsLine, memoryLine, hitRatioLine, clientsLine, evictedLine, expiredLine, replOffsetLine, netInputLine, netOutputLine}
+
+	sectionList := tview.NewList().ShowSecondaryText(false)
+	sectionList.SetBorder(true).SetTitle(" Sections ")
+	sectionView := tview.NewTextView().SetDynamicColors(true)
+	sectionView.SetBorder(true).SetTitle(" INFO ")
+
+	tipView := tview.NewTextView().SetTextColor(tcell.ColorOrange).
+		SetText(" +/- - change refresh interval, c - clear history")
+
+	var sections []api.InfoSection
+	var currentSection string
+
+	renderSection := func() {
+		for _, sec := range sections {
+			if sec.Name != currentSection {
+				continue
+			}
+
+			lines := make([]string, len(sec.Fields))
+			for i, f := range sec.Fields {
+				lines[i] = fmt.Sprintf(" [yellow]%s[white]: %s", tview.Escape(f.Key), tview.Escape(f.Value))
+			}
+
+			sectionView.SetText(strings.Join(lines, "\n")).SetTitle(fmt.Sprintf(" INFO %s ", sec.Name))
+			return
+		}
+	}
+
+	sectionList.SetChangedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
+		currentSection = mainText
+		renderSection()
+	})
+
+	var prev *api.ServerInfo
+	var prevTime time.Time
+
+	update := func(info *api.ServerInfo, now time.Time) {
+		elapsed := now.Sub(prevTime).Seconds()
+
+		rate := func(key string) float64 {
+			if prev == nil || elapsed <= 0 {
+				return 0
+			}
+
+			delta := info.Float64(key) - prev.Float64(key)
+			if delta < 0 {
+				return 0
+			}
+
+			return delta / elapsed
+		}
+
+		hits, misses := info.Float64("keyspace_hits"), info.Float64("keyspace_misses")
+		totalRatio := 0.0
+		if hits+misses > 0 {
+			totalRatio = hits / (hits + misses)
+		}
+
+		intervalRatio := totalRatio
+		if prev != nil {
+			deltaHits, deltaMisses := hits-prev.Float64("keyspace_hits"), misses-prev.Float64("keyspace_misses")
+			if deltaHits+deltaMisses > 0 {
+				intervalRatio = deltaHits / (deltaHits + deltaMisses)
+			}
+		}
+
+		usedMemory := info.Float64("used_memory")
+		memoryGauge.SetRatio(memoryUsage(info))
+		hitRatioGauge.SetRatio(totalRatio, fmt.Sprintf("%s hits / %s misses", formatNumber(hits), formatNumber(misses)))
+
+		opsLine.Push(info.Float64("instantaneous_ops_per_sec"))
+		memoryLine.Push(usedMemory)
+		hitRatioLine.Push(intervalRatio * 100)
+		clientsLine.Push(info.Float64("connected_clients"))
+		evictedLine.Push(rate("evicted_keys"))
+		expiredLine.Push(rate("expired_keys"))
+		replOffsetLine.Push(info.Float64("master_repl_offset"))
+		netInputLine.Push(info.Float64("instantaneous_input_kbps"))
+		netOutputLine.Push(info.Float64("instantaneous_output_kbps"))
+
+		prev = info
+		prevTime = now
+
+		sections = info.Sections
+		if sectionList.GetItemCount() != len(sections) {
+			sectionList.Clear()
+			for _, sec := range sections {
+				sectionList.AddItem(sec.Name, "", 0, nil)
+			}
+		}
+		renderSection()
+	}
+
+	var stopChan chan struct{}
+	poll := func(client api.RedisClient, release func(), stop chan struct{}) {
+		defer release()
+
+		for {
+			info, err := api.RedisServerInfo(client, "all")
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+			} else {
+				now := time.Now()
+				ui.uiViewUpdateChan <- func() {
+					update(info, now)
+				}
+			}
+
+			select {
+			case <-stop:
+				return
+			case <-time.After(time.Duration(atomic.LoadInt64(&interval))):
+			}
+		}
+	}
+
+	changeInterval := func(delta time.Duration) {
+		next := time.Duration(atomic.LoadInt64(&interval)) + delta
+		if next < time.Second {
+			next = time.Second
+		}
+
+		atomic.StoreInt64(&interval, int64(next))
+		updateTitle()
+	}
+
+	inputCapture := func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+
+		switch event.Rune() {
+		case '+':
+			changeInterval(time.Second)
+			return nil
+		case '-':
+			changeInterval(-time.Second)
+			return nil
+		case 'c':
+			for _, s := range sparklines {
+				s.Reset()
+			}
+			prev = nil
+			return nil
+		}
+
+		return event
+	}
+	sectionList.SetInputCapture(inputCapture)
+	sectionView.SetInputCapture(inputCapture)
+
+	gauges := tview.NewFlex().
+		AddItem(memoryGauge, 0, 1, false).
+		AddItem(hitRatioGauge, 0, 1, false)
+
+	charts := tview.NewFlex().SetDirection(tview.FlexRow)
+	for i := 0; i < len(sparklines); i += 3 {
+		row := tview.NewFlex()
+		for _, s := range sparklines[i : i+3] {
+			row.AddItem(s, 0, 1, false)
+		}
+		charts.AddItem(row, 0, 1, false)
+	}
+
+	raw := tview.NewFlex().
+		AddItem(sectionList, 20, 0, false).
+		AddItem(sectionView, 0, 1, false)
+
+	flex.AddItem(gauges, 3, 0, false).
+		AddItem(charts, 0, 3, false).
+		AddItem(raw, 0, 2, false).
+		AddItem(tipView, 1, 0, false)
+
+	return &toolPage{
+		name:      "dashboard",
+		title:     "Dashboard",
+		primitive: flex,
+		focus:     []tview.Primitive{sectionList, sectionView},
+		onOpen: func() {
+			stopChan = make(chan struct{})
+			client, release := ui.retainClient()
+			go poll(client, release, stopChan)
+		},
+		onClose: func() {
+			close(stopChan)
+		},
+	}
+}
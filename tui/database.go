@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+// showDBSwitcher display a menu listing databases with their key counts, the selected database is switched to.
+// The menu is closed if it's displayed already
+func (ui *RedisTUI) showDBSwitcher() {
+	pageID := "databases"
+	if ui.pages.HasPage(pageID) {
+		ui.pages.HidePage(pageID).RemovePage(pageID)
+		ui.app.SetFocus(ui.keyItemsPanel)
+		return
+	}
+
+	if ui.config.Cluster {
+		ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: "errors: only db 0 is available in cluster mode"}
+		return
+	}
+
+	client, release := ui.retainClient()
+	go func() {
+		defer release()
+
+		databases, err := api.RedisDatabases(client)
+		if err != nil {
+			ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+			return
+		}
+
+		ui.uiViewUpdateChan <- func() {
+			menu := tview.NewList().ShowSecondaryText(false)
+			menu.SetBorder(true).SetTitle(fmt.Sprintf(" Databases (%s) ", ui.keyBindings.Name("switch_db")))
+
+			closeMenu := func() {
+				ui.pages.HidePage(pageID).RemovePage(pageID)
+			}
+
+			current := 0
+			for i, ks := range databases {
+				text := fmt.Sprintf("db%-3d keys=%d, expires=%d", ks.DB, ks.Keys, ks.Expires)
+				if ks.DB == ui.config.DB {
+					text += " (current)"
+					current = i
+				}
+
+				menu.AddItem(text, "", 0, (func(db int) func() {
+					return func() {
+						closeMenu()
+						ui.switchDB(db)
+					}
+				})(ks.DB))
+			}
+			menu.SetCurrentItem(current)
+
+			menu.SetDoneFunc(func() {
+				closeMenu()
+				ui.app.SetFocus(ui.keyItemsPanel)
+			})
+
+			height := menu.GetItemCount() + 2
+			if height > 20 {
+				height = 20
+			}
+
+			ui.pages.AddPage(pageID, modal(menu, 50, height), true, true)
+			ui.app.SetFocus(menu)
+		}
+	}()
+}
+
+// switchDB reconnect to the database db, then reload the key list and server info
+func (ui *RedisTUI) switchDB(db int) {
+	if db == ui.config.DB {
+		ui.app.SetFocus(ui.keyItemsPanel)
+		return
+	}
+
+	conf := ui.config
+	conf.DB = db
+
+	go func() {
+		client := api.NewRedisClient(conf, ui.outputChan)
+		if err := client.Do("PING").Err(); err != nil {
+			if c, ok := client.(io.Closer); ok {
+				c.Close()
+			}
+
+			ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+			return
+		}
+
+		ui.uiViewUpdateChan <- func() {
+			// live refresh subscribes the keyspace channels of the previous database, and the watched
+			// key belongs to it, live refresh is restarted for the new database if it was on
+			// notify-keyspace-events is a server config, it's kept for the new database
+			live := ui.unsubscribeLiveRefresh()
+			ui.stopWatch()
+
+			// tool pages keep states of the previous database, such as subscriptions, they are closed
+			// before swapping the client so that their jobs are stopped and no new job is started on it
+			ui.showMainPanel()
+
+			// the previous client is closed after the background jobs holding it are finished
+			release := ui.releaseClient
+			ui.redisClient, ui.config = client, conf
+			ui.releaseClient = ui.workspace.clients.retain(client)
+			release()
+
+			if live != nil {
+				ui.startLiveRefresh()
+				ui.live.previousFlags, ui.live.flagsChanged = live.previousFlags, live.flagsChanged
+			}
+
+			ui.reloadKeys()
+		}
+
+		ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("switched to db %d", db)}
+	}()
+}
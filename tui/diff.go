@@ -0,0 +1,267 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+const (
+	// diffTTLTolerance is the max difference of TTLs considered equal
+	diffTTLTolerance = 2 * time.Second
+	// diffMaxElements is the max count of element differences displayed in detail
+	diffMaxElements = 1000
+)
+
+// createDiffTool create a page for comparing keys between this connection and another instance or database
+func (ui *RedisTUI) createDiffTool() *toolPage {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.SetBorder(true).SetTitle(" Key Diff ")
+
+	patternInputField := tview.NewInputField().SetLabel(" Pattern ").SetText("*")
+	targetInputField := tview.NewInputField().SetLabel(" Compare with ").
+		SetPlaceholder("tab number such as 2, or redis://host:port/db")
+	summaryView := tview.NewTextView().SetDynamicColors(true)
+	resultTable := tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	resultTable.SetBorder(true).SetTitle(" Keys ")
+	detailView := tview.NewTextView().SetDynamicColors(true)
+	detailView.SetBorder(true).SetTitle(" Detail ")
+	tipView := tview.NewTextView().SetTextColor(tcell.ColorOrange).
+		SetText(" Enter on inputs - compare; Keys: Enter - drill down, a - show all/differences only, s - stop, r - compare again")
+
+	var diffs []api.KeyDiff
+	var stopChan chan struct{}
+	differencesOnly := true
+	leftName, rightName := "", ""
+
+	ttlText := func(v api.KeyValue) string {
+		if !v.Exists() {
+			return "-"
+		}
+		if v.TTL < 0 {
+			return v.Type
+		}
+
+		return fmt.Sprintf("%s, ttl %s", v.Type, v.TTL.Round(time.Second))
+	}
+
+	kindsText := func(d api.KeyDiff) string {
+		if d.Equal() {
+			return "[gray]equal"
+		}
+
+		kinds := make([]string, 0, len(d.Kinds))
+		for _, k := range d.Kinds {
+			color := "yellow"
+			switch k {
+			case api.DiffOnlyLeft:
+				color = "red"
+			case api.DiffOnlyRight:
+				color = "green"
+			case api.DiffType:
+				color = "fuchsia"
+			}
+			kinds = append(kinds, fmt.Sprintf("[%s]%s", color, k))
+		}
+
+		return strings.Join(kinds, " ")
+	}
+
+	render := func() {
+		resultTable.Clear()
+		for i, title := range []string{"Key", "Difference", "Left", "Right"} {
+			resultTable.SetCell(0, i, tview.NewTableCell(title).SetTextColor(tcell.ColorYellow).SetSelectable(false))
+		}
+
+		row := 0
+		for _, d := range diffs {
+			if differencesOnly && d.Equal() {
+				continue
+			}
+
+			row++
+			resultTable.SetCell(row, 0, tview.NewTableCell(tview.Escape(d.Key)).SetReference(d).SetMaxWidth(50).SetExpansion(1))
+			resultTable.SetCell(row, 1, tview.NewTableCell(kindsText(d)))
+			resultTable.SetCell(row, 2, tview.NewTableCell(ttlText(d.Left)))
+			resultTable.SetCell(row, 3, tview.NewTableCell(ttlText(d.Right)))
+		}
+
+		resultTable.Select(1, 0).ScrollToBeginning()
+		resultTable.SetTitle(fmt.Sprintf(" Keys (%d, differences only: %v) ", row, differencesOnly))
+		if len(diffs) > 0 {
+			summaryView.SetText(fmt.Sprintf(" [yellow]%s[white] vs [yellow]%s[white]: %s", leftName, tview.Escape(rightName), api.SummarizeKeyDiffs(diffs)))
+		}
+	}
+
+	renderDetail := func(d api.KeyDiff) {
+		side := func(name string, v api.KeyValue) string {
+			if !v.Exists() {
+				return fmt.Sprintf("[yellow]%s:[white] not exists", tview.Escape(name))
+			}
+
+			return fmt.Sprintf("[yellow]%s:[white] %s, %d elements", tview.Escape(name), ttlText(v), len(v.Elements))
+		}
+
+		lines := []string{
+			fmt.Sprintf("[yellow]Key:[white] %s", tview.Escape(d.Key)),
+			side(leftName, d.Left),
+			side(rightName, d.Right),
+		}
+
+		if len(d.Elements) > 0 {
+			lines = append(lines, "", fmt.Sprintf("[yellow]Elements (%d differences):", len(d.Elements)))
+		}
+
+		for i, e := range d.Elements {
+			if i >= diffMaxElements {
+				lines = append(lines, fmt.Sprintf("[gray]... %d more", len(d.Elements)-i))
+				break
+			}
+
+			name := e.Element
+			if d.Left.Type == "string" {
+				name = "value"
+			}
+
+			switch {
+			case e.Right == nil:
+				lines = append(lines, fmt.Sprintf("[red]- %s", tview.Escape(diffElementText(name, *e.Left))))
+			case e.Left == nil:
+				lines = append(lines, fmt.Sprintf("[green]+ %s", tview.Escape(diffElementText(name, *e.Right))))
+			default:
+				lines = append(lines, fmt.Sprintf("[yellow]~ %s: %s [white]->[yellow] %s", tview.Escape(name), tview.Escape(*e.Left), tview.Escape(*e.Right)))
+			}
+		}
+
+		detailView.SetText(strings.Join(lines, "\n")).ScrollToBeginning()
+	}
+
+	stop := func() {
+		if stopChan != nil {
+			close(stopChan)
+			stopChan = nil
+		}
+	}
+
+	compare := func() {
+		pattern, target := strings.TrimSpace(patternInputField.GetText()), strings.TrimSpace(targetInputField.GetText())
+		if target == "" {
+			summaryView.SetText(" [red]errors: input the connection to compare with")
+			ui.setFocus(targetInputField)
+			return
+		}
+		if pattern == "" {
+			pattern = "*"
+		}
+
+		right, name, release, err := ui.workspace.resolveConnection(ui.config, target)
+		if err != nil {
+			summaryView.SetText(fmt.Sprintf(" [red]errors: %s", tview.Escape(err.Error())))
+			return
+		}
+		left, releaseLeft := ui.retainClient()
+
+		stop()
+		stopChan = make(chan struct{})
+		leftName, rightName = ui.connectionName(), name
+		summaryView.SetText(" [orange]scanning keys...")
+		ui.setFocus(resultTable)
+
+		go func(stop chan struct{}) {
+			defer release()
+			defer releaseLeft()
+
+			opts := api.KeyDiffOptions{Pattern: pattern, TTLTolerance: diffTTLTolerance}
+			res, err := api.DiffKeys(left, right, opts, func(compared, total int) {
+				ui.uiViewUpdateChan <- func() {
+					summaryView.SetText(fmt.Sprintf(" [orange]comparing %d/%d keys...", compared, total))
+				}
+			}, stop)
+
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+			}
+
+			ui.uiViewUpdateChan <- func() {
+				diffs = res
+				render()
+			}
+		}(stopChan)
+	}
+
+	for _, field := range []*tview.InputField{patternInputField, targetInputField} {
+		field.SetDoneFunc(func(key tcell.Key) {
+			if key == tcell.KeyEnter {
+				compare()
+			}
+		})
+	}
+
+	resultTable.SetSelectionChangedFunc(func(row, column int) {
+		if d, ok := resultTable.GetCell(row, 0).GetReference().(api.KeyDiff); ok {
+			renderDetail(d)
+		}
+	})
+
+	resultTable.SetSelectedFunc(func(row, column int) {
+		if d, ok := resultTable.GetCell(row, 0).GetReference().(api.KeyDiff); ok {
+			renderDetail(d)
+			ui.setFocus(detailView)
+		}
+	})
+
+	resultTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+
+		switch event.Rune() {
+		case 'a':
+			differencesOnly = !differencesOnly
+			render()
+		case 's':
+			stop()
+		case 'r':
+			compare()
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	inputs := tview.NewFlex().
+		AddItem(patternInputField, 0, 1, false).
+		AddItem(targetInputField, 0, 2, false)
+
+	body := tview.NewFlex().
+		AddItem(resultTable, 0, 3, false).
+		AddItem(detailView, 0, 2, false)
+
+	flex.AddItem(inputs, 1, 0, false).
+		AddItem(summaryView, 1, 0, false).
+		AddItem(body, 0, 1, false).
+		AddItem(tipView, 1, 0, false)
+
+	return &toolPage{
+		name:      "diff",
+		title:     "Key Diff",
+		primitive: flex,
+		focus:     []tview.Primitive{patternInputField, targetInputField, resultTable, detailView},
+		onClose:   stop,
+	}
+}
+
+// diffElementText format an element which exists only on one side
+func diffElementText(name, value string) string {
+	if value == "" {
+		return name
+	}
+
+	return fmt.Sprintf("%s: %s", name, value)
+}
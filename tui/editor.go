@@ -0,0 +1,204 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+// textEditor is a primitive for editing multi-line text, such as lua scripts
+type textEditor struct {
+	*tview.Box
+
+	lines [][]rune
+	row   int
+	col   int
+
+	// offsetRow and offsetCol are the first row and column displayed
+	offsetRow int
+	offsetCol int
+
+	changed func()
+}
+
+// newTextEditor create an empty text editor
+func newTextEditor() *textEditor {
+	return &textEditor{
+		Box:   tview.NewBox(),
+		lines: [][]rune{{}},
+	}
+}
+
+// SetText replace the text of editor, the cursor is moved to the beginning
+func (e *textEditor) SetText(text string) *textEditor {
+	e.lines = e.lines[:0]
+	for _, line := range strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n") {
+		e.lines = append(e.lines, []rune(strings.Replace(line, "\t", "    ", -1)))
+	}
+
+	e.row, e.col, e.offsetRow, e.offsetCol = 0, 0, 0, 0
+	return e
+}
+
+// GetText return the text of editor
+func (e *textEditor) GetText() string {
+	lines := make([]string, len(e.lines))
+	for i, line := range e.lines {
+		lines[i] = string(line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// SetChangedFunc set a handler called when the text is changed
+func (e *textEditor) SetChangedFunc(handler func()) *textEditor {
+	e.changed = handler
+	return e
+}
+
+// Draw draws this primitive onto the screen
+func (e *textEditor) Draw(screen tcell.Screen) {
+	e.Box.Draw(screen)
+
+	x, y, width, height := e.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	gutter := len(fmt.Sprintf("%d", len(e.lines))) + 1
+	textWidth := width - gutter - 1
+	if textWidth <= 0 {
+		return
+	}
+
+	// scroll to keep the cursor visible
+	if e.row < e.offsetRow {
+		e.offsetRow = e.row
+	}
+	if e.row >= e.offsetRow+height {
+		e.offsetRow = e.row - height + 1
+	}
+	if e.col < e.offsetCol {
+		e.offsetCol = e.col
+	}
+	if e.col >= e.offsetCol+textWidth {
+		e.offsetCol = e.col - textWidth + 1
+	}
+
+	gutterStyle := tcell.StyleDefault.Foreground(tcell.ColorGray)
+	textStyle := tcell.StyleDefault.Foreground(tview.Styles.PrimaryTextColor)
+	for i := 0; i < height && e.offsetRow+i < len(e.lines); i++ {
+		number := fmt.Sprintf("%*d", gutter, e.offsetRow+i+1)
+		for j, ch := range number {
+			screen.SetContent(x+j, y+i, ch, nil, gutterStyle)
+		}
+
+		line := e.lines[e.offsetRow+i]
+		for j := 0; j < textWidth && e.offsetCol+j < len(line); j++ {
+			screen.SetContent(x+gutter+1+j, y+i, line[e.offsetCol+j], nil, textStyle)
+		}
+	}
+
+	if e.HasFocus() {
+		screen.ShowCursor(x+gutter+1+e.col-e.offsetCol, y+e.row-e.offsetRow)
+	}
+}
+
+// InputHandler returns the handler for this primitive
+func (e *textEditor) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return e.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		line := e.lines[e.row]
+		modified := true
+
+		switch event.Key() {
+		case tcell.KeyRune:
+			e.lines[e.row] = append(line[:e.col], append([]rune{event.Rune()}, line[e.col:]...)...)
+			e.col++
+		case tcell.KeyEnter:
+			// keep the indent of current line
+			indent := 0
+			for indent < len(line) && line[indent] == ' ' {
+				indent++
+			}
+			if indent > e.col {
+				indent = e.col
+			}
+
+			next := append([]rune(strings.Repeat(" ", indent)), line[e.col:]...)
+			e.lines[e.row] = line[:e.col:e.col]
+			e.lines = append(e.lines[:e.row+1], append([][]rune{next}, e.lines[e.row+1:]...)...)
+			e.row++
+			e.col = indent
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			if e.col > 0 {
+				e.lines[e.row] = append(line[:e.col-1], line[e.col:]...)
+				e.col--
+			} else if e.row > 0 {
+				e.col = len(e.lines[e.row-1])
+				e.lines[e.row-1] = append(e.lines[e.row-1], line...)
+				e.lines = append(e.lines[:e.row], e.lines[e.row+1:]...)
+				e.row--
+			}
+		case tcell.KeyDelete:
+			if e.col < len(line) {
+				e.lines[e.row] = append(line[:e.col], line[e.col+1:]...)
+			} else if e.row < len(e.lines)-1 {
+				e.lines[e.row] = append(line, e.lines[e.row+1]...)
+				e.lines = append(e.lines[:e.row+1], e.lines[e.row+2:]...)
+			}
+		default:
+			modified = false
+			e.moveCursor(event.Key())
+		}
+
+		if modified && e.changed != nil {
+			e.changed()
+		}
+	})
+}
+
+// moveCursor move the cursor by navigation keys
+func (e *textEditor) moveCursor(key tcell.Key) {
+	_, _, _, height := e.GetInnerRect()
+
+	switch key {
+	case tcell.KeyLeft:
+		if e.col > 0 {
+			e.col--
+		} else if e.row > 0 {
+			e.row--
+			e.col = len(e.lines[e.row])
+		}
+	case tcell.KeyRight:
+		if e.col < len(e.lines[e.row]) {
+			e.col++
+		} else if e.row < len(e.lines)-1 {
+			e.row++
+			e.col = 0
+		}
+	case tcell.KeyUp:
+		e.row--
+	case tcell.KeyDown:
+		e.row++
+	case tcell.KeyPgUp:
+		e.row -= height
+	case tcell.KeyPgDn:
+		e.row += height
+	case tcell.KeyHome:
+		e.col = 0
+	case tcell.KeyEnd:
+		e.col = len(e.lines[e.row])
+	}
+
+	if e.row < 0 {
+		e.row = 0
+	}
+	if e.row > len(e.lines)-1 {
+		e.row = len(e.lines) - 1
+	}
+	if e.col > len(e.lines[e.row]) {
+		e.col = len(e.lines[e.row])
+	}
+}
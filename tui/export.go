@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+// showExportDialog display a dialog asking for the file which the keys matching the last search are
+// exported to, the format is decided by the extension: .jsonl, .csv, or redis commands for others.
+// All the matched keys are exported by scanning again, not only the ones displayed in the key list
+func (ui *RedisTUI) showExportDialog() {
+	pageID := "export"
+	if ui.pages.HasPage(pageID) {
+		return
+	}
+
+	pattern := ui.keyPattern
+	if len(ui.keyItems) == 0 {
+		ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: "errors: no keys to export, search keys first"}
+		return
+	}
+
+	pathInputField := tview.NewInputField().SetLabel(" File ").
+		SetText(fmt.Sprintf("keys-%s.jsonl", time.Now().Format("20060102150405")))
+	tipView := tview.NewTextView().SetTextColor(tcell.ColorOrange).
+		SetText(" .jsonl, .csv (flat types only) or others for redis commands replayable by redis-cli; Esc - cancel")
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(pathInputField, 1, 0, true).
+		AddItem(tipView, 0, 1, false)
+	flex.SetBorder(true).SetTitle(fmt.Sprintf(" Export keys matching %s ", pattern))
+
+	closeDialog := func() {
+		ui.pages.HidePage(pageID).RemovePage(pageID)
+		ui.app.SetFocus(ui.keyItemsPanel)
+	}
+
+	pathInputField.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			closeDialog()
+			return
+		}
+
+		path := strings.TrimSpace(pathInputField.GetText())
+		if key != tcell.KeyEnter || path == "" {
+			return
+		}
+
+		closeDialog()
+		if _, err := os.Stat(path); err == nil {
+			ui.confirm(fmt.Sprintf("%s exists, overwrite it?", path), func() {
+				client, release := ui.retainClient()
+				go ui.exportKeys(client, release, pattern, path)
+			})
+			return
+		}
+
+		client, release := ui.retainClient()
+		go ui.exportKeys(client, release, pattern, path)
+	})
+
+	ui.pages.AddPage(pageID, modal(flex, 70, 5), true, true)
+	ui.app.SetFocus(pathInputField)
+}
+
+// exportKeys write the keys matching pattern with their values into path, the client is released when finished
+func (ui *RedisTUI) exportKeys(client api.RedisClient, release func(), pattern string, path string) {
+	defer release()
+
+	f, err := os.Create(path)
+	if err != nil {
+		ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+		return
+	}
+	defer f.Close()
+
+	format := api.FormatByPath(path)
+	ui.outputChan <- core.OutputMessage{Color: tcell.ColorOrange, Message: fmt.Sprintf("exporting keys matching %s to %s (%s)", pattern, path, format)}
+
+	res, err := api.ExportKeys(client, api.NewKeyScanner(client, pattern), f, format, func(res api.ExportResult) {
+		ui.outputChan <- core.OutputMessage{Color: tcell.ColorOrange, Message: fmt.Sprintf("exported %d keys", res.Exported)}
+	})
+	if err != nil {
+		ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+		return
+	}
+
+	message := fmt.Sprintf("%d keys exported to %s", res.Exported, path)
+	if res.Skipped > 0 {
+		message += fmt.Sprintf(", %d skipped (not exist or not supported by %s)", res.Skipped, format)
+	}
+
+	ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: message}
+}
@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mylxsw/redis-tui/api"
+)
+
+// formatCommandResult format the reply of a command for displaying in result panel
+func formatCommandResult(res interface{}) string {
+	switch v := res.(type) {
+	case string:
+		return v
+	case []interface{}:
+		var resStrs = make([]string, len(v))
+		for i, item := range v {
+			resStrs[i] = fmt.Sprintf("%v", item)
+		}
+
+		return strings.Join(resStrs, "\n")
+	}
+
+	return fmt.Sprintf("%v", res)
+}
+
+// formatNodeResults format the replies of a command executed on multiple nodes, replies are grouped
+// by node, and the total is appended if all the replies are integers
+func formatNodeResults(results []api.NodeResult) string {
+	lines := make([]string, 0)
+	for _, r := range results {
+		lines = append(lines, fmt.Sprintf("===== %s =====", r.Addr))
+		if r.Err != nil {
+			lines = append(lines, fmt.Sprintf("errors: %s", r.Err))
+		} else {
+			lines = append(lines, formatCommandResult(r.Result))
+		}
+		lines = append(lines, "")
+	}
+
+	if total, ok := api.SumNodeResults(results); ok {
+		lines = append(lines, fmt.Sprintf("===== total (%d nodes) =====", len(results)), fmt.Sprintf("%d", total))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatReply format the reply of a command in the style of redis-cli, the type of every
+// element is shown, such as (integer) 1, "value", (nil) and nested arrays
+func formatReply(res interface{}) string {
+	return strings.Join(formatReplyLines(res), "\n")
+}
+
+func formatReplyLines(res interface{}) []string {
+	switch v := res.(type) {
+	case nil:
+		return []string{"(nil)"}
+	case int64:
+		return []string{fmt.Sprintf("(integer) %d", v)}
+	case float64:
+		return []string{fmt.Sprintf("(double) %v", v)}
+	case bool:
+		return []string{fmt.Sprintf("(boolean) %v", v)}
+	case string:
+		return []string{strconv.Quote(v)}
+	case error:
+		return []string{fmt.Sprintf("(error) %s", v)}
+	case []interface{}:
+		if len(v) == 0 {
+			return []string{"(empty array)"}
+		}
+
+		lines := make([]string, 0, len(v))
+		width := len(strconv.Itoa(len(v)))
+		for i, item := range v {
+			prefix := fmt.Sprintf("%*d) ", width, i+1)
+			for j, line := range formatReplyLines(item) {
+				if j == 0 {
+					lines = append(lines, prefix+line)
+				} else {
+					lines = append(lines, strings.Repeat(" ", len(prefix))+line)
+				}
+			}
+		}
+
+		return lines
+	}
+
+	return []string{fmt.Sprintf("%v", res)}
+}
@@ -0,0 +1,308 @@
+package tui
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+// createFunctionsTool create a page for managing libraries loaded by FUNCTION LOAD
+func (ui *RedisTUI) createFunctionsTool() *toolPage {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.SetBorder(true).SetTitle(" Functions ")
+
+	tree := tview.NewTreeView()
+	tree.SetBorder(true).SetTitle(" Libraries ")
+	codeView := tview.NewTextView()
+	codeView.SetBorder(true).SetTitle(" Code ")
+	keysInputField := tview.NewInputField().SetLabel(" KEYS ")
+	argvInputField := tview.NewInputField().SetLabel(" ARGV ")
+	readOnlyCheckbox := tview.NewCheckbox().SetLabel(" FCALL_RO ")
+	fileInputField := tview.NewInputField().SetLabel(" File ").SetPlaceholder("Enter - load library, Ctrl-X - dump all libraries, Ctrl-U - restore")
+	replaceCheckbox := tview.NewCheckbox().SetLabel(" Replace ")
+	resultView := tview.NewTextView()
+	resultView.SetBorder(true).SetTitle(" Result ")
+	tipView := tview.NewTextView().SetTextColor(tcell.ColorOrange).
+		SetText(" Libraries: Enter - call function, d - delete library, r - refresh; KEYS/ARGV: Enter - call function")
+
+	var current *api.FunctionInfo
+
+	showError := func(err error) {
+		resultView.SetTextColor(tcell.ColorRed).SetText(fmt.Sprintf("(error) %s", err))
+	}
+
+	selectedLibrary := func() (api.FunctionLibrary, bool) {
+		node := tree.GetCurrentNode()
+		if node == nil {
+			return api.FunctionLibrary{}, false
+		}
+
+		switch ref := node.GetReference().(type) {
+		case api.FunctionLibrary:
+			return ref, true
+		case functionNode:
+			return ref.library, true
+		}
+
+		return api.FunctionLibrary{}, false
+	}
+
+	refresh := func() {
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			libraries, err := api.RedisFunctionList(client)
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
+
+			ui.uiViewUpdateChan <- func() {
+				root := tview.NewTreeNode(fmt.Sprintf("Libraries (%d)", len(libraries))).SetSelectable(false)
+				for _, lib := range libraries {
+					libNode := tview.NewTreeNode(fmt.Sprintf("%s (%s)", lib.Name, lib.Engine)).
+						SetColor(tcell.ColorGreen).SetReference(lib)
+					for _, f := range lib.Functions {
+						text := f.Name
+						if len(f.Flags) > 0 {
+							text += fmt.Sprintf(" (%s)", strings.Join(f.Flags, ","))
+						}
+						libNode.AddChild(tview.NewTreeNode(text).SetReference(functionNode{library: lib, function: f}))
+					}
+					root.AddChild(libNode)
+				}
+
+				tree.SetRoot(root)
+				if children := root.GetChildren(); len(children) > 0 {
+					tree.SetCurrentNode(children[0])
+					codeView.SetText(libraries[0].Code).ScrollToBeginning()
+				} else {
+					codeView.SetText("")
+				}
+			}
+		}()
+	}
+
+	call := func() {
+		if current == nil {
+			showError(fmt.Errorf("select a function in libraries first"))
+			return
+		}
+
+		name := current.Name
+		keys, args := strings.Fields(keysInputField.GetText()), strings.Fields(argvInputField.GetText())
+		readOnly := readOnlyCheckbox.IsChecked()
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			res, err := api.RedisFCall(client, name, keys, args, readOnly)
+			ui.uiViewUpdateChan <- func() {
+				if err != nil {
+					showError(err)
+					return
+				}
+
+				resultView.SetTextColor(tview.Styles.PrimaryTextColor).SetText(formatReply(res)).ScrollToBeginning()
+				resultView.SetTitle(fmt.Sprintf(" Result (%s) ", name))
+			}
+		}()
+	}
+
+	tree.SetChangedFunc(func(node *tview.TreeNode) {
+		if lib, ok := selectedLibrary(); ok {
+			codeView.SetText(lib.Code).ScrollToBeginning()
+		}
+	})
+
+	tree.SetSelectedFunc(func(node *tview.TreeNode) {
+		if ref, ok := node.GetReference().(functionNode); ok {
+			f := ref.function
+			current = &f
+			readOnlyCheckbox.SetChecked(f.ReadOnly())
+			keysInputField.SetLabel(fmt.Sprintf(" %s KEYS ", f.Name))
+			ui.setFocus(keysInputField)
+		}
+	})
+
+	tree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+
+		switch event.Rune() {
+		case 'r':
+			refresh()
+		case 'd':
+			lib, ok := selectedLibrary()
+			if !ok {
+				return nil
+			}
+
+			ui.confirm(fmt.Sprintf("Delete library %s?", lib.Name), func() {
+				client, release := ui.retainClient()
+				go func() {
+					defer release()
+
+					if err := api.RedisFunctionDelete(client, lib.Name); err != nil {
+						ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+						return
+					}
+
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("library %s deleted", lib.Name)}
+					ui.uiViewUpdateChan <- refresh
+				}()
+			})
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	for _, field := range []*tview.InputField{keysInputField, argvInputField} {
+		field.SetDoneFunc(func(key tcell.Key) {
+			if key == tcell.KeyEnter {
+				call()
+			}
+		})
+	}
+
+	fileInputField.SetDoneFunc(func(key tcell.Key) {
+		path := strings.TrimSpace(fileInputField.GetText())
+		if key != tcell.KeyEnter || path == "" {
+			return
+		}
+
+		replace := replaceCheckbox.IsChecked()
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			code, err := ioutil.ReadFile(path)
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
+
+			name, err := api.RedisFunctionLoad(client, string(code), replace)
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
+
+			ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("library %s loaded from %s", name, path)}
+			ui.uiViewUpdateChan <- refresh
+		}()
+	})
+
+	fileInputField.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		path := strings.TrimSpace(fileInputField.GetText())
+
+		switch event.Key() {
+		case tcell.KeyCtrlX:
+			if path == "" {
+				return nil
+			}
+
+			dump := func() {
+				client, release := ui.retainClient()
+				go func() {
+					defer release()
+
+					payload, err := api.RedisFunctionDump(client)
+					if err == nil {
+						err = ioutil.WriteFile(path, []byte(payload), 0644)
+					}
+					if err != nil {
+						ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+						return
+					}
+
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("libraries dumped to %s", path)}
+				}()
+			}
+
+			if _, err := os.Stat(path); err == nil {
+				ui.confirm(fmt.Sprintf("%s exists, overwrite it?", path), dump)
+			} else {
+				dump()
+			}
+		case tcell.KeyCtrlU:
+			if path == "" {
+				return nil
+			}
+
+			policy := "APPEND"
+			if replaceCheckbox.IsChecked() {
+				policy = "REPLACE"
+			}
+
+			ui.confirm(fmt.Sprintf("Restore libraries from %s with policy %s?", path, policy), func() {
+				client, release := ui.retainClient()
+				go func() {
+					defer release()
+
+					payload, err := ioutil.ReadFile(path)
+					if err == nil {
+						err = api.RedisFunctionRestore(client, string(payload), policy)
+					}
+					if err != nil {
+						ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+						return
+					}
+
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("libraries restored from %s", path)}
+					ui.uiViewUpdateChan <- refresh
+				}()
+			})
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	body := tview.NewFlex().
+		AddItem(tree, 0, 1, false).
+		AddItem(codeView, 0, 2, false)
+
+	callForm := tview.NewFlex().
+		AddItem(keysInputField, 0, 1, false).
+		AddItem(argvInputField, 0, 1, false).
+		AddItem(readOnlyCheckbox, 13, 0, false)
+
+	fileForm := tview.NewFlex().
+		AddItem(fileInputField, 0, 1, false).
+		AddItem(replaceCheckbox, 12, 0, false)
+
+	flex.AddItem(body, 0, 3, false).
+		AddItem(callForm, 1, 0, false).
+		AddItem(fileForm, 1, 0, false).
+		AddItem(resultView, 0, 1, false).
+		AddItem(tipView, 1, 0, false)
+
+	return &toolPage{
+		name:      "functions",
+		title:     "Functions",
+		primitive: flex,
+		focus: []tview.Primitive{
+			tree, codeView, keysInputField, argvInputField, readOnlyCheckbox,
+			fileInputField, replaceCheckbox, resultView,
+		},
+		onOpen: refresh,
+	}
+}
+
+// functionNode is the reference of function nodes in the library tree
+type functionNode struct {
+	library  api.FunctionLibrary
+	function api.FunctionInfo
+}
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+// createHotKeysTool create a page which ranks keys by OBJECT FREQ or OBJECT IDLETIME
+func (ui *RedisTUI) createHotKeysTool() *toolPage {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.SetBorder(true).SetTitle(" Hot Keys ")
+
+	patternInputField := tview.NewInputField().SetLabel(" Pattern ").SetText("*")
+	statusView := tview.NewTextView().SetDynamicColors(true).SetWordWrap(true)
+	resultTable := tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	tipView := tview.NewTextView().SetTextColor(tcell.ColorOrange).
+		SetText(" Enter - open key in value panel, r - rescan")
+
+	scan := func() {
+		pattern := patternInputField.GetText()
+		if pattern == "" {
+			pattern = "*"
+		}
+
+		statusView.SetText(fmt.Sprintf(" Scanning keys matched [green]%s[white] ...", tview.Escape(pattern)))
+		resultTable.Clear()
+
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			metric, hotKeys, err := api.HotKeys(client, pattern, ui.maxKeyLimit)
+			if err != nil {
+				if _, ok := err.(api.HotKeysUnsupportedError); !ok {
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				}
+
+				ui.uiViewUpdateChan <- func() {
+					statusView.SetText(fmt.Sprintf(" [red]%s", tview.Escape(err.Error())))
+				}
+				return
+			}
+
+			ui.uiViewUpdateChan <- func() {
+				statusView.SetText(fmt.Sprintf(" %d keys ranked by %s", len(hotKeys), metric))
+
+				resultTable.SetCell(0, 0, tview.NewTableCell(" # ").SetTextColor(tcell.ColorYellow).SetSelectable(false))
+				resultTable.SetCell(0, 1, tview.NewTableCell(metric.String()).SetTextColor(tcell.ColorYellow).SetSelectable(false))
+				resultTable.SetCell(0, 2, tview.NewTableCell("Key").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetExpansion(1))
+
+				for i, hk := range hotKeys {
+					if i >= ui.maxKeyLimit {
+						break
+					}
+
+					resultTable.SetCell(i+1, 0, tview.NewTableCell(fmt.Sprintf(" %3d ", i+1)))
+					resultTable.SetCell(i+1, 1, tview.NewTableCell(fmt.Sprintf("%d", hk.Value)).SetAlign(tview.AlignRight))
+					resultTable.SetCell(i+1, 2, tview.NewTableCell(hk.Key).SetReference(hk.Key))
+				}
+
+				resultTable.ScrollToBeginning()
+			}
+		}()
+	}
+
+	patternInputField.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+
+		scan()
+		ui.setFocus(resultTable)
+	})
+
+	resultTable.SetSelectedFunc(func(row, column int) {
+		if key, ok := resultTable.GetCell(row, 2).GetReference().(string); ok {
+			ui.showKey(key)
+		}
+	})
+	resultTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == 'r' {
+			scan()
+			return nil
+		}
+
+		return event
+	})
+
+	flex.AddItem(patternInputField, 1, 0, false).
+		AddItem(statusView, 2, 0, false).
+		AddItem(resultTable, 0, 1, false).
+		AddItem(tipView, 1, 0, false)
+
+	return &toolPage{
+		name:      "hot_keys",
+		title:     "Hot Keys",
+		primitive: flex,
+		focus:     []tview.Primitive{patternInputField, resultTable},
+		onOpen:    scan,
+	}
+}
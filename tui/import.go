@@ -0,0 +1,255 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/rivo/tview"
+)
+
+// importPreviewCommands is the max count of commands displayed in the preview
+const importPreviewCommands = 200
+
+// createImportTool create a page for importing keys from files of JSON Lines, csv or redis commands
+func (ui *RedisTUI) createImportTool() *toolPage {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.SetBorder(true).SetTitle(" Import ")
+
+	fileInputField := tview.NewInputField().SetLabel(" File ").
+		SetPlaceholder("keys.jsonl, keys.csv or commands such as keys.redis")
+	formatInputField := tview.NewInputField().SetLabel(" Format ").
+		SetPlaceholder("jsonl, csv or commands, by extension if empty")
+	pipelineInputField := tview.NewInputField().SetLabel(" Pipeline ").SetText("1000").
+		SetAcceptanceFunc(tview.InputFieldInteger)
+	replaceCheckbox := tview.NewCheckbox().SetLabel(" Replace ")
+	ignoreTTLCheckbox := tview.NewCheckbox().SetLabel(" Ignore TTL ")
+	dryRunCheckbox := tview.NewCheckbox().SetLabel(" Dry-run ").SetChecked(true)
+	statusView := tview.NewTextView().SetDynamicColors(true)
+	previewView := tview.NewTextView().SetDynamicColors(true)
+	previewView.SetBorder(true).SetTitle(" Preview ")
+	logView := tview.NewTextView().SetDynamicColors(true)
+	logView.SetBorder(true).SetTitle(" Import Log ")
+	tipView := tview.NewTextView().SetTextColor(tcell.ColorOrange).
+		SetText(" Enter on File - preview, Enter on other inputs - import, x on log - stop; Replace deletes keys first, both are ignored for command files")
+
+	var importStop chan struct{}
+	logs := make([]string, 0)
+	log := func(color string, format string, args ...interface{}) {
+		logs = append(logs, fmt.Sprintf("[gray]%s[%s] %s", time.Now().Format("15:04:05"), color, tview.Escape(fmt.Sprintf(format, args...))))
+		if len(logs) > 1000 {
+			logs = logs[len(logs)-1000:]
+		}
+		logView.SetText(strings.Join(logs, "\n")).ScrollToEnd()
+	}
+
+	logErrors := func(res api.ImportResult) {
+		for _, e := range res.Errors {
+			log("red", "%s", e)
+		}
+		if res.Failed > len(res.Errors) {
+			log("red", "... %d more errors", res.Failed-len(res.Errors))
+		}
+	}
+
+	options := func() (string, api.ImportOptions, error) {
+		path := strings.TrimSpace(fileInputField.GetText())
+		if path == "" {
+			return "", api.ImportOptions{}, fmt.Errorf("input the file to import")
+		}
+
+		format := api.FormatByPath(path)
+		switch text := strings.ToLower(strings.TrimSpace(formatInputField.GetText())); text {
+		case "":
+		case string(api.FormatJSONLines), string(api.FormatCSV), string(api.FormatCommands):
+			format = api.ExportFormat(text)
+		default:
+			return "", api.ImportOptions{}, fmt.Errorf("invalid format %s, it should be one of jsonl, csv and commands", text)
+		}
+
+		pipeline, _ := strconv.Atoi(pipelineInputField.GetText())
+		return path, api.ImportOptions{
+			Format:    format,
+			Replace:   replaceCheckbox.IsChecked(),
+			IgnoreTTL: ignoreTTLCheckbox.IsChecked(),
+			Pipeline:  pipeline,
+			DryRun:    dryRunCheckbox.IsChecked(),
+		}, nil
+	}
+
+	preview := func() {
+		path, opts, err := options()
+		if err != nil {
+			log("red", "errors: %s", err)
+			return
+		}
+
+		previewView.SetText(" [orange]validating...")
+		go func() {
+			f, err := os.Open(path)
+			if err != nil {
+				ui.uiViewUpdateChan <- func() { log("red", "errors: %s", err) }
+				return
+			}
+			defer f.Close()
+
+			commands, res, err := api.PreviewImport(f, opts, importPreviewCommands)
+			ui.uiViewUpdateChan <- func() {
+				lines := make([]string, 0, len(commands)+1)
+				for _, c := range commands {
+					lines = append(lines, fmt.Sprintf("[gray]%5d[white] %s", c.Line, tview.Escape(c.String())))
+				}
+				if res.Commands > len(commands) {
+					lines = append(lines, fmt.Sprintf("[gray]... %d more commands", res.Commands-len(commands)))
+				}
+				previewView.SetText(strings.Join(lines, "\n")).ScrollToBeginning()
+
+				if err != nil {
+					log("red", "errors: %s", err)
+					return
+				}
+
+				color := "green"
+				if res.Failed > 0 {
+					color = "yellow"
+				}
+				log(color, "validated %s (%s): %s", path, opts.Format, res)
+				logErrors(res)
+			}
+		}()
+	}
+
+	runImport := func(path string, opts api.ImportOptions) {
+		f, err := os.Open(path)
+		if err != nil {
+			log("red", "errors: %s", err)
+			return
+		}
+
+		stop := make(chan struct{})
+		importStop = stop
+		client, release := ui.retainClient()
+		log("white", "import %s (%s) into %s (replace: %v, ignore ttl: %v, dry-run: %v)", path, opts.Format, ui.connectionName(), opts.Replace, opts.IgnoreTTL, opts.DryRun)
+
+		go func() {
+			defer f.Close()
+			defer release()
+
+			start := time.Now()
+			res, err := api.Import(client, f, opts, func(res api.ImportResult) {
+				ui.uiViewUpdateChan <- func() {
+					statusView.SetText(fmt.Sprintf(" [orange]%s", res))
+				}
+			}, stop)
+
+			ui.uiViewUpdateChan <- func() {
+				if importStop == stop {
+					importStop = nil
+				}
+
+				statusView.SetText(" " + res.String())
+				if err != nil {
+					log("red", "errors: %s", err)
+				}
+
+				logErrors(res)
+
+				color := "green"
+				if res.Failed > 0 {
+					color = "yellow"
+				}
+				log(color, "import finished in %s: %s", time.Since(start).Round(time.Millisecond), res)
+			}
+		}()
+	}
+
+	startImport := func() {
+		if importStop != nil {
+			log("red", "errors: an import is running, press x on log to stop it")
+			return
+		}
+
+		path, opts, err := options()
+		if err != nil {
+			log("red", "errors: %s", err)
+			return
+		}
+
+		if opts.DryRun {
+			runImport(path, opts)
+			return
+		}
+
+		ui.confirm(fmt.Sprintf("Import %s into %s?", path, ui.connectionName()), func() {
+			runImport(path, opts)
+		})
+	}
+
+	fileInputField.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			preview()
+		}
+	})
+
+	for _, field := range []*tview.InputField{formatInputField, pipelineInputField} {
+		field.SetDoneFunc(func(key tcell.Key) {
+			if key == tcell.KeyEnter {
+				startImport()
+			}
+		})
+	}
+
+	logView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == 'x' {
+			if importStop != nil {
+				close(importStop)
+				importStop = nil
+				log("yellow", "stopping import after the pipeline being sent")
+			}
+			return nil
+		}
+
+		return event
+	})
+
+	inputs := tview.NewFlex().
+		AddItem(fileInputField, 0, 3, false).
+		AddItem(formatInputField, 0, 2, false).
+		AddItem(pipelineInputField, 18, 0, false)
+
+	checkboxes := tview.NewFlex().
+		AddItem(replaceCheckbox, 12, 0, false).
+		AddItem(ignoreTTLCheckbox, 15, 0, false).
+		AddItem(dryRunCheckbox, 12, 0, false).
+		AddItem(nil, 0, 1, false)
+
+	body := tview.NewFlex().
+		AddItem(previewView, 0, 1, false).
+		AddItem(logView, 0, 1, false)
+
+	flex.AddItem(inputs, 1, 0, false).
+		AddItem(checkboxes, 1, 0, false).
+		AddItem(statusView, 1, 0, false).
+		AddItem(body, 0, 1, false).
+		AddItem(tipView, 1, 0, false)
+
+	return &toolPage{
+		name:      "import",
+		title:     "Import",
+		primitive: flex,
+		focus: []tview.Primitive{
+			fileInputField, formatInputField, pipelineInputField, replaceCheckbox,
+			ignoreTTLCheckbox, dryRunCheckbox, previewView, logView,
+		},
+		onClose: func() {
+			if importStop != nil {
+				close(importStop)
+				importStop = nil
+			}
+		},
+	}
+}
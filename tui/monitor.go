@@ -0,0 +1,250 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/config"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+const (
+	monitorBufferCapacity = 10000
+	monitorDisplayLines   = 500
+)
+
+// createMonitorTool create a page which streams the commands processed by server using MONITOR
+func (ui *RedisTUI) createMonitorTool() *toolPage {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.SetBorder(true).SetTitle(" Monitor ")
+
+	regexInputField := tview.NewInputField().SetLabel(" Regex ")
+	commandsInputField := tview.NewInputField().SetLabel(" Commands ").SetPlaceholder("get,set")
+	saveInputField := tview.NewInputField().SetLabel(" Save to ")
+	statusView := tview.NewTextView().SetDynamicColors(true)
+	streamView := tview.NewTextView().SetDynamicColors(true)
+	streamView.SetBorder(true)
+	tipView := tview.NewTextView().SetTextColor(tcell.ColorOrange).
+		SetText(" p - pause/resume, c - clear, w - save captured commands matched filters to file")
+
+	buffer := api.NewMonitorBuffer(monitorBufferCapacity)
+
+	var monitor *api.Monitor
+	var stopChan chan struct{}
+	var received int64
+	var paused bool
+	var rate float64
+	var filterErr error
+
+	filter := func() func(e api.MonitorEvent) bool {
+		filterErr = nil
+
+		var re *regexp.Regexp
+		if text := regexInputField.GetText(); text != "" {
+			re, filterErr = regexp.Compile(text)
+		}
+
+		commands := make(map[string]bool)
+		for _, c := range strings.Split(commandsInputField.GetText(), ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				commands[strings.ToUpper(c)] = true
+			}
+		}
+
+		return func(e api.MonitorEvent) bool {
+			if len(commands) > 0 && !commands[e.Command()] {
+				return false
+			}
+
+			return re == nil || re.MatchString(e.String())
+		}
+	}
+
+	renderStatus := func() {
+		state := "[green]running"
+		if monitor == nil {
+			state = "[red]stopped"
+		} else if paused {
+			state = "[yellow]paused"
+		}
+
+		status := fmt.Sprintf(" %s[white]    Rate: %.1f cmd/s    Captured: %d/%d", state, rate, buffer.Len(), monitorBufferCapacity)
+		if filterErr != nil {
+			status += fmt.Sprintf("    [red]%s", tview.Escape(filterErr.Error()))
+		}
+		statusView.SetText(status)
+	}
+
+	render := func() {
+		match := filter()
+		lines := make([]string, 0, monitorDisplayLines)
+		events := buffer.Events()
+		for i := len(events) - 1; i >= 0 && len(lines) < monitorDisplayLines; i-- {
+			if match(events[i]) {
+				lines = append(lines, tview.Escape(events[i].String()))
+			}
+		}
+
+		for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+			lines[i], lines[j] = lines[j], lines[i]
+		}
+
+		streamView.SetText(strings.Join(lines, "\n")).ScrollToEnd()
+		renderStatus()
+	}
+
+	save := func() {
+		path := saveInputField.GetText()
+		if path == "" {
+			path = fmt.Sprintf("monitor-%s.log", time.Now().Format("20060102150405"))
+			saveInputField.SetText(path)
+		}
+
+		match := filter()
+		events := buffer.Events()
+		go func() {
+			f, err := os.Create(path)
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
+			defer f.Close()
+
+			w := bufio.NewWriter(f)
+			count := 0
+			for _, e := range events {
+				if match(e) {
+					fmt.Fprintf(w, "%s %s\n", e.Node, e.String())
+					count++
+				}
+			}
+
+			if err := w.Flush(); err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
+
+			ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("%d commands saved to %s", count, path)}
+		}()
+	}
+
+	start := func(client api.RedisClient, release func(), conf config.Config, stop chan struct{}) {
+		m, err := api.StartMonitor(client, conf)
+		release()
+		if err != nil {
+			ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+			return
+		}
+
+		ui.uiViewUpdateChan <- func() {
+			monitor = m
+			renderStatus()
+		}
+
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+
+		lastCount, lastTime := int64(0), time.Now()
+		for {
+			select {
+			case <-stop:
+				m.Stop()
+				return
+			case err := <-m.Errors:
+				m.Stop()
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: monitor stopped: %s", err)}
+				ui.uiViewUpdateChan <- func() {
+					monitor = nil
+					renderStatus()
+				}
+				return
+			case e := <-m.Events:
+				buffer.Push(e)
+				atomic.AddInt64(&received, 1)
+			case now := <-ticker.C:
+				count := atomic.LoadInt64(&received)
+				currentRate := float64(count-lastCount) / now.Sub(lastTime).Seconds()
+				lastCount, lastTime = count, now
+
+				ui.uiViewUpdateChan <- func() {
+					rate = currentRate
+					if paused {
+						renderStatus()
+					} else {
+						render()
+					}
+				}
+			}
+		}
+	}
+
+	streamView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+
+		switch event.Rune() {
+		case 'p':
+			paused = !paused
+			render()
+		case 'c':
+			buffer.Clear()
+			render()
+		case 'w':
+			save()
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	for _, field := range []*tview.InputField{regexInputField, commandsInputField} {
+		field.SetDoneFunc(func(key tcell.Key) {
+			if key == tcell.KeyEnter {
+				render()
+				ui.setFocus(streamView)
+			}
+		})
+	}
+	saveInputField.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			save()
+		}
+	})
+
+	inputs := tview.NewFlex().
+		AddItem(regexInputField, 0, 2, false).
+		AddItem(commandsInputField, 0, 1, false).
+		AddItem(saveInputField, 0, 1, false)
+
+	flex.AddItem(inputs, 1, 0, false).
+		AddItem(statusView, 1, 0, false).
+		AddItem(streamView, 0, 1, false).
+		AddItem(tipView, 1, 0, false)
+
+	return &toolPage{
+		name:      "monitor",
+		title:     "Monitor",
+		primitive: flex,
+		focus:     []tview.Primitive{streamView, regexInputField, commandsInputField, saveInputField},
+		onOpen: func() {
+			paused = false
+			stopChan = make(chan struct{})
+			client, release := ui.retainClient()
+			go start(client, release, ui.config, stopChan)
+		},
+		onClose: func() {
+			close(stopChan)
+			monitor = nil
+		},
+	}
+}
@@ -0,0 +1,244 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/core"
+)
+
+const keyHighlightDuration = 3 * time.Second
+
+// liveRefresh keeps the state of keyspace notification driven refreshing
+type liveRefresh struct {
+	subscriber *api.Subscriber
+	stop       chan struct{}
+	highlights map[string]time.Time
+
+	// previousFlags is notify-keyspace-events before live refresh changed it, it's restored when
+	// live refresh is turned off if flagsChanged is true
+	previousFlags string
+	flagsChanged  bool
+}
+
+// toggleLiveRefresh turn on or off the live refresh of key list and value panel, it offers to enable
+// keyspace notifications when they are disabled on server
+func (ui *RedisTUI) toggleLiveRefresh() {
+	if ui.live != nil {
+		ui.stopLiveRefresh()
+		ui.renderKeyItems()
+
+		ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: "live refresh disabled"}
+		return
+	}
+
+	client, release := ui.retainClient()
+	go func() {
+		defer release()
+
+		flags, err := api.RedisKeyspaceEventsFlags(client)
+		if err != nil {
+			ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+			return
+		}
+
+		newFlags, changed := api.KeyspaceEventsFlags(flags)
+		ui.uiViewUpdateChan <- func() {
+			if !changed {
+				ui.startLiveRefresh()
+				return
+			}
+
+			ui.confirm(fmt.Sprintf("Keyspace notifications are off (notify-keyspace-events=%q), set it to %q to enable live refresh? It is restored when live refresh is turned off", flags, newFlags), func() {
+				client, release := ui.retainClient()
+				go func() {
+					defer release()
+
+					if err := api.RedisSetKeyspaceEventsFlags(client, newFlags); err != nil {
+						ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+						return
+					}
+
+					ui.uiViewUpdateChan <- func() {
+						ui.startLiveRefresh()
+						ui.live.previousFlags, ui.live.flagsChanged = flags, true
+					}
+				}()
+			})
+		}
+	}()
+}
+
+// stopLiveRefresh unsubscribe keyspace notifications if live refresh is on, notify-keyspace-events
+// is restored in background if it was changed for live refresh
+func (ui *RedisTUI) stopLiveRefresh() {
+	live := ui.unsubscribeLiveRefresh()
+	if live == nil || !live.flagsChanged {
+		return
+	}
+
+	client, release := ui.retainClient()
+	go func() {
+		defer release()
+
+		if err := api.RedisSetKeyspaceEventsFlags(client, live.previousFlags); err != nil {
+			ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: restore notify-keyspace-events failed: %s", err)}
+			return
+		}
+
+		ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("notify-keyspace-events is restored to %q", live.previousFlags)}
+	}()
+}
+
+// unsubscribeLiveRefresh unsubscribe keyspace notifications without restoring notify-keyspace-events,
+// the stopped live refresh is returned, it's nil if live refresh is off
+func (ui *RedisTUI) unsubscribeLiveRefresh() *liveRefresh {
+	live := ui.live
+	if live == nil {
+		return nil
+	}
+
+	close(live.stop)
+	live.subscriber.Close()
+	ui.live = nil
+
+	return live
+}
+
+// restoreKeyspaceEvents restore notify-keyspace-events changed by live refresh synchronously, it's
+// called when the program quits
+func (ui *RedisTUI) restoreKeyspaceEvents() {
+	if ui.live != nil && ui.live.flagsChanged {
+		_ = api.RedisSetKeyspaceEventsFlags(ui.redisClient, ui.live.previousFlags)
+	}
+}
+
+// startLiveRefresh subscribe keyspace notifications of current database
+func (ui *RedisTUI) startLiveRefresh() {
+	if ui.live != nil {
+		return
+	}
+
+	client, release := ui.retainClient()
+	live := &liveRefresh{
+		subscriber: api.NewSubscriber(client, ui.config),
+		stop:       make(chan struct{}),
+		highlights: make(map[string]time.Time),
+	}
+	ui.live = live
+	ui.renderKeyItems()
+
+	db := ui.config.DB
+	if ui.config.Cluster {
+		db = 0
+	}
+
+	go func() {
+		defer release()
+
+		if err := live.subscriber.SubscribeMasters(api.SubscribePattern, api.KeyspaceChannelPattern(db)); err != nil {
+			ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+			return
+		}
+
+		ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: "live refresh enabled"}
+
+		ticker := time.NewTicker(300 * time.Millisecond)
+		defer ticker.Stop()
+
+		pending := make([]api.KeyspaceEvent, 0)
+		for {
+			select {
+			case <-live.stop:
+				return
+			case err := <-live.subscriber.Errors:
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: keyspace notification connection closed: %s", err)}
+			case m := <-live.subscriber.Messages:
+				if e, ok := api.ParseKeyspaceEvent(m.Channel, m.Payload); ok && e.DB == db {
+					pending = append(pending, e)
+				}
+			case <-ticker.C:
+				events := pending
+				pending = make([]api.KeyspaceEvent, 0)
+				ui.uiViewUpdateChan <- func() {
+					if ui.live == live {
+						ui.applyKeyspaceEvents(events)
+					}
+				}
+			}
+		}
+	}()
+}
+
+// applyKeyspaceEvents update the key list and value panel according to the keyspace events
+func (ui *RedisTUI) applyKeyspaceEvents(events []api.KeyspaceEvent) {
+	if len(events) == 0 && len(ui.live.highlights) == 0 {
+		return
+	}
+
+	refreshCurrent := false
+	var currentRemovedBy string
+	for _, e := range events {
+		if e.Key == ui.currentKey {
+			if e.Removed() {
+				refreshCurrent, currentRemovedBy = false, e.Event
+			} else {
+				refreshCurrent, currentRemovedBy = true, ""
+			}
+		}
+
+		if e.Removed() {
+			delete(ui.live.highlights, e.Key)
+			ui.removeKeyItem(e.Key)
+		} else {
+			ui.live.highlights[e.Key] = time.Now()
+		}
+	}
+
+	for key, t := range ui.live.highlights {
+		if time.Since(t) > keyHighlightDuration {
+			delete(ui.live.highlights, key)
+		}
+	}
+
+	ui.renderKeyItems()
+
+	if currentRemovedBy != "" {
+		ui.metaPanel.SetText(fmt.Sprintf("KeyID: %s\n[red]Key is removed (%s)", ui.currentKey, currentRemovedBy))
+	} else if refreshCurrent {
+		ui.itemSelectedHandler(0, ui.currentKey)()
+	}
+}
+
+// removeKeyItem remove the key from key items panel
+func (ui *RedisTUI) removeKeyItem(key string) {
+	for i, k := range ui.keyItems {
+		if k == key {
+			ui.keyItems = append(ui.keyItems[:i], ui.keyItems[i+1:]...)
+			ui.keyItemsPanel.RemoveItem(i)
+			return
+		}
+	}
+}
+
+// renderKeyItems refresh the text of key items, keys modified recently are highlighted
+func (ui *RedisTUI) renderKeyItems() {
+	title := fmt.Sprintf(" Keys (%s) ", ui.keyBindings.Name("keys"))
+	if ui.live != nil {
+		title = fmt.Sprintf(" Keys (%s) [live] ", ui.keyBindings.Name("keys"))
+	}
+	ui.keyItemsPanel.SetTitle(title)
+
+	for i, k := range ui.keyItems {
+		text := ui.keyItemsFormat(i, k)
+		if ui.live != nil {
+			if _, ok := ui.live.highlights[k]; ok {
+				text = "[yellow]" + text
+			}
+		}
+
+		ui.keyItemsPanel.SetItemText(i, text, "")
+	}
+}
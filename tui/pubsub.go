@@ -0,0 +1,383 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+const pubSubMaxMessages = 5000
+
+// pubSubSubscription is a channel or pattern subscribed in the pub/sub page
+type pubSubSubscription struct {
+	kind    string
+	channel string
+	count   int
+}
+
+// pubSubKindLabels are short labels of subscription kinds displayed in subscription list
+var pubSubKindLabels = map[string]string{
+	api.SubscribeChannel: "C",
+	api.SubscribePattern: "P",
+	api.SubscribeShard:   "S",
+}
+
+// createPubSubTool create a page for subscribing and publishing messages
+func (ui *RedisTUI) createPubSubTool() *toolPage {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.SetBorder(true).SetTitle(" Pub/Sub ")
+
+	kinds := []string{api.SubscribeChannel, api.SubscribePattern, api.SubscribeShard}
+	kindDropDown := tview.NewDropDown().SetLabel(" ").SetOptions(kinds, nil).SetCurrentOption(0)
+	subscribeInputField := tview.NewInputField().SetLabel(" Channel ")
+
+	subscriptionList := tview.NewList().ShowSecondaryText(false)
+	subscriptionList.SetBorder(true).SetTitle(" Subscriptions ")
+	messageView := tview.NewTextView().SetDynamicColors(true)
+	messageView.SetBorder(true).SetTitle(" Messages ")
+	channelTable := tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	channelTable.SetBorder(true).SetTitle(" Active Channels ")
+
+	publishChannelInputField := tview.NewInputField().SetLabel(" Publish to ")
+	publishMessageInputField := tview.NewInputField().SetLabel(" Message ")
+	shardedCheckbox := tview.NewCheckbox().SetLabel(" Sharded ")
+
+	tipView := tview.NewTextView().SetTextColor(tcell.ColorOrange).
+		SetText(" Subscriptions: d - unsubscribe, c - clear messages; Active Channels: Enter - subscribe, r - refresh")
+
+	var subscriber *api.Subscriber
+	var stopChan chan struct{}
+	subscriptions := make([]*pubSubSubscription, 0)
+	messages := make([]api.PubSubMessage, 0)
+	selected := -1
+
+	renderMessages := func() {
+		var current *pubSubSubscription
+		if selected >= 0 && selected < len(subscriptions) {
+			current = subscriptions[selected]
+		}
+
+		lines := make([]string, 0)
+		for _, m := range messages {
+			if current != nil && (current.kind != m.Kind || current.channel != m.Subscription()) {
+				continue
+			}
+
+			lines = append(lines, fmt.Sprintf(
+				"[gray]%s[white] [green]%s[white] %s",
+				m.Time.Format("15:04:05.000"),
+				tview.Escape(m.Channel),
+				tview.Escape(api.PrintablePayload(m.Payload)),
+			))
+		}
+
+		title := " Messages (all) "
+		if current != nil {
+			title = fmt.Sprintf(" Messages (%s) ", tview.Escape(current.channel))
+		}
+
+		messageView.SetText(strings.Join(lines, "\n")).ScrollToEnd()
+		messageView.SetTitle(title)
+	}
+
+	renderSubscriptions := func() {
+		// adding items into an empty list changes the current item, so keep the selection here
+		current := selected
+		subscriptionList.Clear()
+		subscriptionList.AddItem(fmt.Sprintf("All (%d)", len(messages)), "", 0, nil)
+		for _, s := range subscriptions {
+			subscriptionList.AddItem(fmt.Sprintf("%s %s (%d)", pubSubKindLabels[s.kind], tview.Escape(s.channel), s.count), "", 0, nil)
+		}
+		selected = current
+		subscriptionList.SetCurrentItem(selected + 1)
+	}
+
+	subscriptionList.SetChangedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
+		selected = index - 1
+		renderMessages()
+	})
+
+	// subscribeAll send the subscriptions in background, the ones failed are removed from the list
+	subscribeAll := func(targets []*pubSubSubscription) {
+		sub := subscriber
+		_, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			failed := make(map[*pubSubSubscription]bool)
+			for _, s := range targets {
+				if err := sub.Subscribe(s.kind, s.channel); err != nil {
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s %s failed: %s", s.kind, s.channel, err)}
+					failed[s] = true
+					continue
+				}
+
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("%s %s succeed", s.kind, s.channel)}
+			}
+
+			if len(failed) == 0 {
+				return
+			}
+
+			ui.uiViewUpdateChan <- func() {
+				if subscriber != sub {
+					return
+				}
+
+				remains := make([]*pubSubSubscription, 0, len(subscriptions))
+				for _, s := range subscriptions {
+					if !failed[s] {
+						remains = append(remains, s)
+					}
+				}
+				subscriptions = remains
+				selected = -1
+				renderSubscriptions()
+				renderMessages()
+			}
+		}()
+	}
+
+	receive := func(sub *api.Subscriber, release func(), stop chan struct{}) {
+		defer release()
+
+		ticker := time.NewTicker(300 * time.Millisecond)
+		defer ticker.Stop()
+
+		pending := make([]api.PubSubMessage, 0)
+		for {
+			select {
+			case <-stop:
+				return
+			case err := <-sub.Errors:
+				// subscriptions on the closed connection are lost, they are sent again on a new connection,
+				// sending them again on the connections alive is harmless
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: pub/sub connection closed: %s, resubscribing", err)}
+				ui.uiViewUpdateChan <- func() {
+					if subscriber == sub {
+						subscribeAll(append([]*pubSubSubscription{}, subscriptions...))
+					}
+				}
+			case m := <-sub.Messages:
+				pending = append(pending, m)
+			case <-ticker.C:
+				if len(pending) == 0 {
+					continue
+				}
+
+				received := pending
+				pending = make([]api.PubSubMessage, 0)
+				ui.uiViewUpdateChan <- func() {
+					for _, m := range received {
+						for _, s := range subscriptions {
+							if s.kind == m.Kind && s.channel == m.Subscription() {
+								s.count++
+							}
+						}
+					}
+
+					messages = append(messages, received...)
+					if len(messages) > pubSubMaxMessages {
+						messages = messages[len(messages)-pubSubMaxMessages:]
+					}
+
+					renderSubscriptions()
+					renderMessages()
+				}
+			}
+		}
+	}
+
+	subscribe := func(kind, channel string) {
+		if channel == "" {
+			return
+		}
+
+		for _, s := range subscriptions {
+			if s.kind == kind && s.channel == channel {
+				return
+			}
+		}
+
+		// the subscription is listed before it's sent, so that it's not sent twice by quick Enters,
+		// it's removed if failed
+		s := &pubSubSubscription{kind: kind, channel: channel}
+		subscriptions = append(subscriptions, s)
+		renderSubscriptions()
+		subscribeAll([]*pubSubSubscription{s})
+	}
+
+	refreshChannels := func() {
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			channels, err := api.RedisPubSubChannels(client, "*")
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
+
+			ui.uiViewUpdateChan <- func() {
+				channelTable.Clear()
+				for i, title := range []string{"Channel", "Subscribers"} {
+					channelTable.SetCell(0, i, tview.NewTableCell(title).SetTextColor(tcell.ColorYellow).SetSelectable(false))
+				}
+
+				for i, c := range channels {
+					name := c.Channel
+					if c.Sharded {
+						name += " (sharded)"
+					}
+
+					channelTable.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(name)).SetReference(c).SetExpansion(1))
+					channelTable.SetCell(i+1, 1, tview.NewTableCell(fmt.Sprintf("%d", c.Subscribers)).SetAlign(tview.AlignRight))
+				}
+			}
+		}()
+	}
+
+	subscribeInputField.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+
+		_, kind := kindDropDown.GetCurrentOption()
+		subscribe(kind, strings.TrimSpace(subscribeInputField.GetText()))
+		subscribeInputField.SetText("")
+	})
+
+	subscriptionList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+
+		switch event.Rune() {
+		case 'd':
+			if selected < 0 || selected >= len(subscriptions) {
+				return nil
+			}
+
+			s := subscriptions[selected]
+			subscriptions = append(subscriptions[:selected], subscriptions[selected+1:]...)
+			selected = -1
+			renderSubscriptions()
+
+			sub := subscriber
+			_, release := ui.retainClient()
+			go func() {
+				defer release()
+
+				if err := sub.Unsubscribe(s.kind, s.channel); err != nil {
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				}
+			}()
+		case 'c':
+			messages = messages[:0]
+			for _, s := range subscriptions {
+				s.count = 0
+			}
+			renderSubscriptions()
+			renderMessages()
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	channelTable.SetSelectedFunc(func(row, column int) {
+		if c, ok := channelTable.GetCell(row, 0).GetReference().(api.PubSubChannel); ok {
+			kind := api.SubscribeChannel
+			if c.Sharded {
+				kind = api.SubscribeShard
+			}
+			subscribe(kind, c.Channel)
+		}
+	})
+	channelTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == 'r' {
+			refreshChannels()
+			return nil
+		}
+
+		return event
+	})
+
+	publishMessageInputField.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+
+		channel, message, sharded := publishChannelInputField.GetText(), publishMessageInputField.GetText(), shardedCheckbox.IsChecked()
+		if channel == "" {
+			ui.setFocus(publishChannelInputField)
+			return
+		}
+
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			receivers, err := api.RedisPublish(client, channel, message, sharded)
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
+
+			ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("message published to %s, received by %d clients", channel, receivers)}
+		}()
+
+		publishMessageInputField.SetText("")
+	})
+
+	subscribeForm := tview.NewFlex().
+		AddItem(kindDropDown, 14, 0, false).
+		AddItem(subscribeInputField, 0, 1, false)
+
+	body := tview.NewFlex().
+		AddItem(subscriptionList, 30, 0, false).
+		AddItem(messageView, 0, 1, false).
+		AddItem(channelTable, 40, 0, false)
+
+	publishForm := tview.NewFlex().
+		AddItem(publishChannelInputField, 0, 1, false).
+		AddItem(publishMessageInputField, 0, 2, false).
+		AddItem(shardedCheckbox, 12, 0, false)
+
+	flex.AddItem(subscribeForm, 1, 0, false).
+		AddItem(body, 0, 1, false).
+		AddItem(publishForm, 1, 0, false).
+		AddItem(tipView, 1, 0, false)
+
+	return &toolPage{
+		name:      "pubsub",
+		title:     "Pub/Sub",
+		primitive: flex,
+		focus: []tview.Primitive{
+			subscribeInputField, kindDropDown, subscriptionList, messageView, channelTable,
+			publishChannelInputField, publishMessageInputField, shardedCheckbox,
+		},
+		onOpen: func() {
+			client, release := ui.retainClient()
+			subscriber = api.NewSubscriber(client, ui.config)
+			subscriptions = subscriptions[:0]
+			selected = -1
+			renderSubscriptions()
+			renderMessages()
+			refreshChannels()
+
+			stopChan = make(chan struct{})
+			go receive(subscriber, release, stopChan)
+		},
+		onClose: func() {
+			close(stopChan)
+			subscriber.Close()
+		},
+	}
+}
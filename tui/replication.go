@@ -0,0 +1,295 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+const replicationRefreshInterval = 2 * time.Second
+
+// createReplicationTool create a page for inspecting the replication topology
+func (ui *RedisTUI) createReplicationTool() *toolPage {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.SetBorder(true).SetTitle(" Replication ")
+
+	tree := tview.NewTreeView()
+	tree.SetBorder(true).SetTitle(" Topology ")
+	detailView := tview.NewTextView().SetDynamicColors(true)
+	detailView.SetBorder(true).SetTitle(" Detail ")
+	masterInputField := tview.NewInputField().SetLabel(" Replicate of ").SetPlaceholder("host:port, Enter to apply to selected node")
+	tipView := tview.NewTextView().SetTextColor(tcell.ColorOrange).
+		SetText(" o - promote selected node to master, f - edit master of selected node, a - auto refresh, r - refresh")
+
+	var loading sync.Mutex
+	var stopChan chan struct{}
+	var nodes []api.ReplicationInfo
+	autoRefresh := true
+	selectedAddr := ""
+
+	replicaLag := func(replica api.ReplicationInfo) (int64, int64, bool) {
+		for _, n := range nodes {
+			if n.Addr != replica.MasterAddr {
+				continue
+			}
+
+			for _, r := range n.Replicas {
+				if r.Addr == replica.Addr {
+					return r.LagBytes(n.MasterReplOffset), r.Lag, true
+				}
+			}
+
+			return api.ReplicaInfo{Offset: replica.SlaveReplOffset}.LagBytes(n.MasterReplOffset), replica.MasterLastIOSecondsAgo, true
+		}
+
+		return 0, 0, false
+	}
+
+	nodeText := func(n api.ReplicationInfo) (string, tcell.Color) {
+		if n.Err != nil {
+			return fmt.Sprintf("%s  errors: %s", n.Addr, n.Err), tcell.ColorRed
+		}
+
+		if !n.IsReplica() {
+			return fmt.Sprintf("%s  master  offset=%d  replicas=%d  backlog=%.1f%%", n.Addr, n.MasterReplOffset, len(n.Replicas), n.BacklogUsage()*100), tcell.ColorGreen
+		}
+
+		text := fmt.Sprintf("%s  replica  offset=%d  link=%s", n.Addr, n.SlaveReplOffset, n.MasterLinkStatus)
+		if n.ReplicaState != "" {
+			text += "  state=" + n.ReplicaState
+		}
+
+		color := tcell.ColorWhite
+		if lagBytes, lagSeconds, ok := replicaLag(n); ok {
+			text += fmt.Sprintf("  lag=%s/%ds", formatBytes(float64(lagBytes)), lagSeconds)
+			if lagSeconds > 10 {
+				color = tcell.ColorYellow
+			}
+		}
+
+		if n.MasterLinkStatus != "up" || n.MasterSyncInProgress {
+			color = tcell.ColorRed
+		}
+
+		return text, color
+	}
+
+	renderDetail := func(n api.ReplicationInfo) {
+		if n.Err != nil {
+			detailView.SetText(fmt.Sprintf("[red]%s", tview.Escape(n.Err.Error())))
+			return
+		}
+
+		lines := []string{
+			fmt.Sprintf("[yellow]Addr:[white] %s", n.Addr),
+			fmt.Sprintf("[yellow]Role:[white] %s", n.Role),
+		}
+
+		if n.IsReplica() {
+			lines = append(lines,
+				fmt.Sprintf("[yellow]Master:[white] %s", n.MasterAddr),
+				fmt.Sprintf("[yellow]Link:[white] %s (last io %ds ago)", n.MasterLinkStatus, n.MasterLastIOSecondsAgo),
+				fmt.Sprintf("[yellow]State:[white] %s", n.ReplicaState),
+				fmt.Sprintf("[yellow]Sync in progress:[white] %v", n.MasterSyncInProgress),
+				fmt.Sprintf("[yellow]Replica offset:[white] %d", n.SlaveReplOffset),
+			)
+		}
+
+		lines = append(lines,
+			fmt.Sprintf("[yellow]Master offset:[white] %d", n.MasterReplOffset),
+			fmt.Sprintf("[yellow]Backlog:[white] active=%v, size=%s, used=%s (%.1f%%), first byte offset=%d",
+				n.BacklogActive, formatBytes(float64(n.BacklogSize)), formatBytes(float64(n.BacklogHistlen)), n.BacklogUsage()*100, n.BacklogFirstByteOffset),
+		)
+
+		if len(n.Replicas) > 0 {
+			lines = append(lines, "", "[yellow]Replicas:")
+			for _, r := range n.Replicas {
+				lines = append(lines, fmt.Sprintf("  %s state=%s offset=%d lag=%s/%ds", r.Addr, r.State, r.Offset, formatBytes(float64(r.LagBytes(n.MasterReplOffset))), r.Lag))
+			}
+		}
+
+		detailView.SetText(strings.Join(lines, "\n"))
+	}
+
+	render := func() {
+		root := tview.NewTreeNode(fmt.Sprintf("Replication (%d nodes)", len(nodes))).SetSelectable(false)
+		treeNodes := make(map[string]*tview.TreeNode)
+		for _, n := range nodes {
+			text, color := nodeText(n)
+			treeNodes[n.Addr] = tview.NewTreeNode(text).SetColor(color).SetReference(n)
+		}
+
+		// replicas are attached to their masters, nodes whose master is unknown are placed at top level
+		for _, n := range nodes {
+			parent := root
+			if p, ok := treeNodes[n.MasterAddr]; ok && n.IsReplica() {
+				parent = p
+			}
+			parent.AddChild(treeNodes[n.Addr])
+		}
+
+		tree.SetRoot(root)
+		if current, ok := treeNodes[selectedAddr]; ok {
+			tree.SetCurrentNode(current)
+			renderDetail(current.GetReference().(api.ReplicationInfo))
+		} else if children := root.GetChildren(); len(children) > 0 {
+			tree.SetCurrentNode(children[0])
+			renderDetail(children[0].GetReference().(api.ReplicationInfo))
+		}
+
+		autoRefreshStatus := "off"
+		if autoRefresh {
+			autoRefreshStatus = replicationRefreshInterval.String()
+		}
+		flex.SetTitle(fmt.Sprintf(" Replication (auto refresh: %s) ", autoRefreshStatus))
+	}
+
+	refresh := func() {
+		client, release := ui.retainClient()
+		conf := ui.config
+		go func() {
+			defer release()
+
+			loading.Lock()
+			defer loading.Unlock()
+
+			res, err := api.RedisReplicationTopology(client, conf)
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
+
+			ui.uiViewUpdateChan <- func() {
+				nodes = res
+				render()
+			}
+		}()
+	}
+
+	selected := func() (api.ReplicationInfo, bool) {
+		if node := tree.GetCurrentNode(); node != nil {
+			n, ok := node.GetReference().(api.ReplicationInfo)
+			return n, ok
+		}
+
+		return api.ReplicationInfo{}, false
+	}
+
+	replicaOf := func(n api.ReplicationInfo, master string) {
+		action := fmt.Sprintf("make %s a replica of %s", n.Addr, master)
+		if master == "" {
+			action = fmt.Sprintf("promote %s to master", n.Addr)
+		}
+
+		ui.confirm(fmt.Sprintf("Are you sure to %s?", action), func() {
+			client, release := ui.retainClient()
+			conf := ui.config
+			go func() {
+				defer release()
+
+				if err := api.RedisReplicaOf(client, conf, n.Addr, master); err != nil {
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+					return
+				}
+
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("%s succeed", action)}
+				ui.uiViewUpdateChan <- refresh
+			}()
+		})
+	}
+
+	tree.SetChangedFunc(func(node *tview.TreeNode) {
+		if n, ok := node.GetReference().(api.ReplicationInfo); ok {
+			selectedAddr = n.Addr
+			renderDetail(n)
+		}
+	})
+
+	tree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+
+		switch event.Rune() {
+		case 'o':
+			if n, ok := selected(); ok {
+				replicaOf(n, "")
+			}
+		case 'f':
+			if n, ok := selected(); ok {
+				masterInputField.SetText(n.MasterAddr)
+				ui.setFocus(masterInputField)
+			}
+		case 'a':
+			autoRefresh = !autoRefresh
+			render()
+		case 'r':
+			refresh()
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	masterInputField.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+
+		master := strings.TrimSpace(masterInputField.GetText())
+		n, ok := selected()
+		if !ok || master == "" {
+			return
+		}
+
+		replicaOf(n, master)
+		masterInputField.SetText("")
+	})
+
+	body := tview.NewFlex().
+		AddItem(tree, 0, 3, false).
+		AddItem(detailView, 0, 2, false)
+
+	flex.AddItem(body, 0, 1, false).
+		AddItem(masterInputField, 1, 0, false).
+		AddItem(tipView, 1, 0, false)
+
+	return &toolPage{
+		name:      "replication",
+		title:     "Replication",
+		primitive: flex,
+		focus:     []tview.Primitive{tree, masterInputField, detailView},
+		onOpen: func() {
+			refresh()
+
+			stopChan = make(chan struct{})
+			go func(stop chan struct{}) {
+				ticker := time.NewTicker(replicationRefreshInterval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-stop:
+						return
+					case <-ticker.C:
+						ui.uiViewUpdateChan <- func() {
+							if autoRefresh {
+								refresh()
+							}
+						}
+					}
+				}
+			}(stopChan)
+		},
+		onClose: func() {
+			close(stopChan)
+		},
+	}
+}
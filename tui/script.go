@@ -0,0 +1,188 @@
+package tui
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+const defaultScript = `-- KEYS and ARGV are filled from the inputs below, separated by spaces
+return redis.call('GET', KEYS[1])
+`
+
+// createScriptTool create a page for editing and running lua scripts by EVAL/EVALSHA
+func (ui *RedisTUI) createScriptTool() *toolPage {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.SetBorder(true).SetTitle(" Script ")
+
+	editor := newTextEditor().SetText(defaultScript)
+	editor.SetBorder(true).SetTitle(" Lua ")
+	keysInputField := tview.NewInputField().SetLabel(" KEYS ")
+	argvInputField := tview.NewInputField().SetLabel(" ARGV ")
+	evalSHACheckbox := tview.NewCheckbox().SetLabel(" EVALSHA ").SetChecked(true)
+	fileInputField := tview.NewInputField().SetLabel(" File ").SetPlaceholder("path of lua script, Enter to load")
+	resultView := tview.NewTextView()
+	resultView.SetBorder(true).SetTitle(" Result ")
+	tipView := tview.NewTextView().SetTextColor(tcell.ColorOrange).
+		SetText(" Ctrl-E - run script (also Enter on KEYS/ARGV), Ctrl-X in File - save to file; EVALSHA loads the script by SCRIPT LOAD when it's not cached")
+
+	file := ""
+	modified := false
+	running := false
+
+	setTitle := func() {
+		title := " Lua "
+		if file != "" {
+			title = fmt.Sprintf(" Lua (%s) ", file)
+		}
+		if modified {
+			title += "* "
+		}
+
+		editor.SetTitle(title + fmt.Sprintf("(sha1 %s) ", api.ScriptSHA(editor.GetText())[:12]))
+	}
+	setTitle()
+
+	editor.SetChangedFunc(func() {
+		modified = true
+		setTitle()
+	})
+
+	run := func() {
+		if running {
+			return
+		}
+
+		script := editor.GetText()
+		keys, args := strings.Fields(keysInputField.GetText()), strings.Fields(argvInputField.GetText())
+		useSHA := evalSHACheckbox.IsChecked()
+
+		running = true
+		resultView.SetTextColor(tcell.ColorOrange).SetText("running...")
+
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			start := time.Now()
+			res, err := api.RedisEval(client, script, keys, args, useSHA)
+			elapsed := time.Since(start)
+
+			ui.uiViewUpdateChan <- func() {
+				running = false
+				if err != nil {
+					resultView.SetTextColor(tcell.ColorRed).SetText(fmt.Sprintf("(error) %s", err))
+					return
+				}
+
+				resultView.SetTextColor(tview.Styles.PrimaryTextColor).SetText(formatReply(res))
+				resultView.ScrollToBeginning()
+				resultView.SetTitle(fmt.Sprintf(" Result (%s) ", elapsed.Round(time.Microsecond)))
+			}
+		}()
+	}
+
+	editor.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlE {
+			run()
+			return nil
+		}
+
+		return event
+	})
+
+	for _, field := range []*tview.InputField{keysInputField, argvInputField} {
+		field.SetDoneFunc(func(key tcell.Key) {
+			if key == tcell.KeyEnter {
+				run()
+			}
+		})
+	}
+
+	fileInputField.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+
+		path := strings.TrimSpace(fileInputField.GetText())
+		if path == "" {
+			return
+		}
+
+		load := func() {
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
+
+			editor.SetText(string(data))
+			file, modified = filepath.Base(path), false
+			setTitle()
+			ui.setFocus(editor)
+		}
+
+		if modified {
+			ui.confirm("The script is modified, discard the changes and load the file?", load)
+			return
+		}
+
+		load()
+	})
+
+	fileInputField.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyCtrlX {
+			return event
+		}
+
+		path := strings.TrimSpace(fileInputField.GetText())
+		if path == "" {
+			return nil
+		}
+
+		save := func() {
+			if err := ioutil.WriteFile(path, []byte(editor.GetText()), 0644); err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
+
+			file, modified = filepath.Base(path), false
+			setTitle()
+			ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("script saved to %s", path)}
+		}
+
+		if _, err := os.Stat(path); err == nil {
+			ui.confirm(fmt.Sprintf("%s exists, overwrite it?", path), save)
+			return nil
+		}
+
+		save()
+		return nil
+	})
+
+	inputs := tview.NewFlex().
+		AddItem(keysInputField, 0, 1, false).
+		AddItem(argvInputField, 0, 1, false).
+		AddItem(evalSHACheckbox, 12, 0, false)
+
+	flex.AddItem(editor, 0, 3, false).
+		AddItem(inputs, 1, 0, false).
+		AddItem(fileInputField, 1, 0, false).
+		AddItem(resultView, 0, 2, false).
+		AddItem(tipView, 1, 0, false)
+
+	return &toolPage{
+		name:      "script",
+		title:     "Lua Script",
+		primitive: flex,
+		focus:     []tview.Primitive{editor, keysInputField, argvInputField, evalSHACheckbox, fileInputField, resultView},
+	}
+}
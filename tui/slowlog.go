@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+// slowLogSorters are the orders can be used for slow logs, switched by pressing s
+var slowLogSorters = []struct {
+	name string
+	less func(a, b api.SlowLogEntry) bool
+}{
+	{name: "time", less: func(a, b api.SlowLogEntry) bool { return a.Time.After(b.Time) }},
+	{name: "duration", less: func(a, b api.SlowLogEntry) bool { return a.Duration > b.Duration }},
+	{name: "command", less: func(a, b api.SlowLogEntry) bool { return a.Command() < b.Command() }},
+	{name: "client", less: func(a, b api.SlowLogEntry) bool { return a.ClientAddr < b.ClientAddr }},
+}
+
+// createSlowLogTool create a page for SLOWLOG GET and SLOWLOG RESET
+func (ui *RedisTUI) createSlowLogTool() *toolPage {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.SetBorder(true).SetTitle(" Slow Log ")
+
+	filterInputField := tview.NewInputField().SetLabel(" Filter ")
+	resultTable := tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	detailView := tview.NewTextView().SetWrap(true)
+	detailView.SetBorder(true).SetTitle(" Command ")
+	tipView := tview.NewTextView().SetTextColor(tcell.ColorOrange).
+		SetText(" Enter - show full command, s - change sort order, r - refresh, R - SLOWLOG RESET")
+
+	var entries []api.SlowLogEntry
+	sorterIndex := 0
+
+	render := func() {
+		filter := strings.ToLower(filterInputField.GetText())
+		sorter := slowLogSorters[sorterIndex]
+
+		matched := make([]api.SlowLogEntry, 0, len(entries))
+		for _, e := range entries {
+			if filter == "" ||
+				strings.Contains(strings.ToLower(e.Command()), filter) ||
+				strings.Contains(strings.ToLower(e.ClientAddr), filter) ||
+				strings.Contains(strings.ToLower(e.ClientName), filter) {
+				matched = append(matched, e)
+			}
+		}
+		sort.SliceStable(matched, func(i, j int) bool {
+			return sorter.less(matched[i], matched[j])
+		})
+
+		flex.SetTitle(fmt.Sprintf(" Slow Log (%d/%d, sorted by %s) ", len(matched), len(entries), sorter.name))
+
+		resultTable.Clear()
+		for i, title := range []string{"Node", "ID", "Time", "Duration", "Client", "Name", "Command"} {
+			cell := tview.NewTableCell(title).SetTextColor(tcell.ColorYellow).SetSelectable(false)
+			if i == 6 {
+				cell.SetExpansion(1)
+			}
+			resultTable.SetCell(0, i, cell)
+		}
+
+		for i, e := range matched {
+			resultTable.SetCell(i+1, 0, tview.NewTableCell(e.Node))
+			resultTable.SetCell(i+1, 1, tview.NewTableCell(fmt.Sprintf("%d", e.ID)).SetAlign(tview.AlignRight))
+			resultTable.SetCell(i+1, 2, tview.NewTableCell(e.Time.Format("2006-01-02 15:04:05")))
+			resultTable.SetCell(i+1, 3, tview.NewTableCell(e.Duration.String()).SetAlign(tview.AlignRight))
+			resultTable.SetCell(i+1, 4, tview.NewTableCell(e.ClientAddr))
+			resultTable.SetCell(i+1, 5, tview.NewTableCell(tview.Escape(e.ClientName)))
+			resultTable.SetCell(i+1, 6, tview.NewTableCell(tview.Escape(e.Command())).SetMaxWidth(80).SetReference(e))
+		}
+
+		resultTable.ScrollToBeginning()
+	}
+
+	refresh := func() {
+		client, release := ui.retainClient()
+		go func() {
+			defer release()
+
+			res, err := api.RedisSlowLogs(client, 1024)
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
+
+			ui.uiViewUpdateChan <- func() {
+				entries = res
+				render()
+			}
+		}()
+	}
+
+	filterInputField.SetChangedFunc(func(text string) {
+		render()
+	})
+	filterInputField.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			ui.setFocus(resultTable)
+		}
+	})
+
+	resultTable.SetSelectedFunc(func(row, column int) {
+		if e, ok := resultTable.GetCell(row, 6).GetReference().(api.SlowLogEntry); ok {
+			detailView.SetText(fmt.Sprintf("%s\n\n%s %s (%s)", e.Command(), e.ClientAddr, e.ClientName, e.Node))
+		}
+	})
+	resultTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+
+		switch event.Rune() {
+		case 's':
+			sorterIndex = (sorterIndex + 1) % len(slowLogSorters)
+			render()
+			return nil
+		case 'r':
+			refresh()
+			return nil
+		case 'R':
+			ui.confirm("Reset slow logs? (SLOWLOG RESET will be sent to all masters in cluster mode)", func() {
+				client, release := ui.retainClient()
+				go func() {
+					defer release()
+
+					if err := api.RedisSlowLogReset(client); err != nil {
+						ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+						return
+					}
+
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: "SLOWLOG RESET succeed"}
+					ui.uiViewUpdateChan <- refresh
+				}()
+			})
+			return nil
+		}
+
+		return event
+	})
+
+	flex.AddItem(filterInputField, 1, 0, false).
+		AddItem(resultTable, 0, 3, false).
+		AddItem(detailView, 0, 1, false).
+		AddItem(tipView, 1, 0, false)
+
+	return &toolPage{
+		name:      "slow_log",
+		title:     "Slow Log",
+		primitive: flex,
+		focus:     []tview.Primitive{resultTable, filterInputField, detailView},
+		onOpen:    refresh,
+	}
+}
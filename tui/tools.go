@@ -0,0 +1,198 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/rivo/tview"
+)
+
+// toolPage is a page which can be opened from the tools menu, it takes
+// the place of the value panel while it is active
+type toolPage struct {
+	name      string
+	title     string
+	primitive tview.Primitive
+	focus     []tview.Primitive
+
+	onOpen  func()
+	onClose func()
+	// busy return the name of the running job which must not be interrupted by closing the tab,
+	// it's empty if there is none
+	busy func() string
+}
+
+// showToolsMenu display a menu for switching between the value panel and tool pages, the menu is
+// closed if it's displayed already
+func (ui *RedisTUI) showToolsMenu() {
+	pageID := "tools"
+	if ui.pages.HasPage(pageID) {
+		ui.pages.HidePage(pageID).RemovePage(pageID)
+		ui.app.SetFocus(ui.keyItemsPanel)
+		return
+	}
+
+	menu := tview.NewList().ShowSecondaryText(false)
+	menu.SetBorder(true).SetTitle(fmt.Sprintf(" Tools (%s) ", ui.keyBindings.Name("tools")))
+
+	closeMenu := func() {
+		ui.pages.HidePage(pageID).RemovePage(pageID)
+	}
+
+	menu.AddItem("Key Value", "", 0, func() {
+		closeMenu()
+		ui.showMainPanel()
+	})
+	for _, tool := range ui.tools {
+		menu.AddItem(tool.title, "", 0, (func(tool *toolPage) func() {
+			return func() {
+				closeMenu()
+				ui.openTool(tool)
+			}
+		})(tool))
+	}
+
+	menu.SetDoneFunc(func() {
+		closeMenu()
+		ui.app.SetFocus(ui.keyItemsPanel)
+	})
+
+	ui.pages.AddPage(pageID, modal(menu, 40, menu.GetItemCount()+2), true, true)
+	ui.app.SetFocus(menu)
+}
+
+// openTool replace the center of the right panel with the tool page
+func (ui *RedisTUI) openTool(tool *toolPage) {
+	ui.closeTool()
+
+	ui.commandMode = false
+	ui.currentTool = tool
+	ui.redrawRightPanel(tool.primitive)
+
+	for _, p := range tool.focus {
+		ui.focusPrimitives = append(ui.focusPrimitives, primitiveKey{Primitive: p, Key: "tool:" + tool.name})
+	}
+
+	if tool.onOpen != nil {
+		tool.onOpen()
+	}
+
+	if len(tool.focus) > 0 {
+		ui.setFocus(tool.focus[0])
+	}
+}
+
+// closeTool close the current tool page if there is one
+func (ui *RedisTUI) closeTool() {
+	tool := ui.currentTool
+	if tool == nil {
+		return
+	}
+
+	ui.currentTool = nil
+	ui.focusPrimitives = ui.primitivesFilter(ui.focusPrimitives, func(item primitiveKey) bool {
+		return item.Key != "tool:"+tool.name
+	})
+
+	if tool.onClose != nil {
+		tool.onClose()
+	}
+}
+
+// busyJob return the name of a running job of tools which must not be interrupted by closing the tab
+func (ui *RedisTUI) busyJob() string {
+	for _, tool := range ui.tools {
+		if tool.busy == nil {
+			continue
+		}
+
+		if job := tool.busy(); job != "" {
+			return job
+		}
+	}
+
+	return ""
+}
+
+// showMainPanel close the current tool page or command panel and show the value panel
+func (ui *RedisTUI) showMainPanel() {
+	ui.closeTool()
+	ui.commandMode = false
+	ui.redrawRightPanel(ui.mainPanel)
+	ui.setFocus(ui.keyItemsPanel)
+}
+
+// showKey open the key in the value panel
+func (ui *RedisTUI) showKey(key string) {
+	ui.showMainPanel()
+	ui.itemSelectedHandler(0, key)()
+}
+
+// setFocus set focus to the primitive and keep the focus index in sync
+func (ui *RedisTUI) setFocus(p tview.Primitive) {
+	for i, pv := range ui.focusPrimitives {
+		if pv.Primitive == p {
+			ui.currentFocusIndex = i
+			break
+		}
+	}
+
+	ui.app.SetFocus(p)
+}
+
+// confirm display a modal dialog, the confirmed func will be called only when OK is pressed
+func (ui *RedisTUI) confirm(text string, confirmed func()) {
+	ui.confirmOrCancel(text, confirmed, nil)
+}
+
+// confirmOrCancel display a modal dialog like confirm, the cancelled func will be called when the
+// dialog is closed without pressing OK, it can be nil
+func (ui *RedisTUI) confirmOrCancel(text string, confirmed func(), cancelled func()) {
+	pageID := "confirm"
+	focused := ui.app.GetFocus()
+	ui.pages.AddPage(
+		pageID,
+		tview.NewModal().
+			SetText(text).
+			AddButtons([]string{"Cancel", "OK"}).
+			SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+				ui.pages.HidePage(pageID).RemovePage(pageID)
+				if focused != nil {
+					ui.app.SetFocus(focused)
+				}
+
+				if buttonLabel == "OK" {
+					confirmed()
+				} else if cancelled != nil {
+					cancelled()
+				}
+			}),
+		false,
+		true,
+	)
+}
+
+// dialogPages are the pages displayed in front of the tab, Esc is passed to them for closing
+// instead of quitting the program
+var dialogPages = []string{"confirm", "alert", "export", "tools", "databases"}
+
+// hasDialog check whether a dialog is displayed in front of the tab
+func (ui *RedisTUI) hasDialog() bool {
+	for _, pageID := range dialogPages {
+		if ui.pages.HasPage(pageID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// modal create a primitive which places p in the center of the screen
+func modal(p tview.Primitive, width, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 1, false).
+			AddItem(nil, 0, 1, false), width, 1, false).
+		AddItem(nil, 0, 1, false)
+}
@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/rivo/tview"
+)
+
+// createTransferTool create a page for copying keys from this connection to another instance or database
+func (ui *RedisTUI) createTransferTool() *toolPage {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.SetBorder(true).SetTitle(" Transfer ")
+
+	patternInputField := tview.NewInputField().SetLabel(" Pattern ").SetText("*")
+	targetInputField := tview.NewInputField().SetLabel(" To ").
+		SetPlaceholder("tab number such as 2, or redis://host:port/db")
+	conflictInputField := tview.NewInputField().SetLabel(" Conflict ").SetText(string(api.ConflictSkip)).
+		SetPlaceholder("skip, overwrite or rename")
+	suffixInputField := tview.NewInputField().SetLabel(" Rename suffix ").SetText(":copy")
+	concurrencyInputField := tview.NewInputField().SetLabel(" Concurrency ").SetText("4").
+		SetAcceptanceFunc(tview.InputFieldInteger)
+	rateInputField := tview.NewInputField().SetLabel(" Keys/s ").SetText("0").
+		SetAcceptanceFunc(tview.InputFieldInteger)
+	dryRunCheckbox := tview.NewCheckbox().SetLabel(" Dry-run ").SetChecked(true)
+	statusView := tview.NewTextView().SetDynamicColors(true)
+	logView := tview.NewTextView().SetDynamicColors(true)
+	logView.SetBorder(true).SetTitle(" Transfer Log ")
+	tipView := tview.NewTextView().SetTextColor(tcell.ColorOrange).
+		SetText(" Enter on inputs - start, x on log - stop; keys are copied by DUMP/RESTORE with TTL, type-aware commands are used when the payload is incompatible; Keys/s 0 means unlimited")
+
+	var transferStop chan struct{}
+	logs := make([]string, 0)
+	log := func(color string, format string, args ...interface{}) {
+		logs = append(logs, fmt.Sprintf("[gray]%s[%s] %s", time.Now().Format("15:04:05"), color, tview.Escape(fmt.Sprintf(format, args...))))
+		if len(logs) > 1000 {
+			logs = logs[len(logs)-1000:]
+		}
+		logView.SetText(strings.Join(logs, "\n")).ScrollToEnd()
+	}
+
+	// runTransfer copy keys to the target in background, release is called after the transfer finished
+	runTransfer := func(transfer api.KeyTransfer, target api.RedisClient, targetName string, release func()) {
+		source, releaseSource := ui.retainClient()
+
+		stop := make(chan struct{})
+		transferStop = stop
+		log("white", "copy keys matching %s from %s to %s (conflict: %s, dry-run: %v)", transfer.Pattern, ui.connectionName(), targetName, transfer.Conflict, transfer.DryRun)
+
+		go func() {
+			defer release()
+			defer releaseSource()
+
+			start := time.Now()
+			p, err := transfer.Run(source, target, func(p api.KeyTransferProgress) {
+				ui.uiViewUpdateChan <- func() {
+					statusView.SetText(fmt.Sprintf(" [orange]%s", tview.Escape(p.String())))
+				}
+			}, stop)
+
+			ui.uiViewUpdateChan <- func() {
+				if transferStop == stop {
+					transferStop = nil
+				}
+
+				statusView.SetText(" " + tview.Escape(p.String()))
+				if err != nil {
+					log("red", "errors: %s", err)
+				}
+
+				color := "green"
+				if p.Failed > 0 {
+					color = "yellow"
+				}
+				log(color, "transfer finished in %s: %s", time.Since(start).Round(time.Millisecond), p)
+			}
+		}()
+	}
+
+	startTransfer := func() {
+		if transferStop != nil {
+			log("red", "errors: a transfer is running, press x on log to stop it")
+			return
+		}
+
+		conflict, err := api.ParseConflictPolicy(conflictInputField.GetText())
+		if err != nil {
+			log("red", "errors: %s", err)
+			return
+		}
+
+		target := strings.TrimSpace(targetInputField.GetText())
+		if target == "" {
+			log("red", "errors: input the connection to copy to")
+			return
+		}
+
+		concurrency, _ := strconv.Atoi(concurrencyInputField.GetText())
+		rate, _ := strconv.Atoi(rateInputField.GetText())
+		transfer := api.KeyTransfer{
+			Pattern:      strings.TrimSpace(patternInputField.GetText()),
+			Conflict:     conflict,
+			RenameSuffix: suffixInputField.GetText(),
+			Concurrency:  concurrency,
+			RateLimit:    rate,
+			DryRun:       dryRunCheckbox.IsChecked(),
+		}
+		if transfer.Pattern == "" {
+			transfer.Pattern = "*"
+		}
+
+		targetClient, targetName, release, err := ui.workspace.resolveConnection(ui.config, target)
+		if err != nil {
+			log("red", "errors: %s", err)
+			return
+		}
+
+		if targetName == ui.connectionName() {
+			release()
+			log("red", "errors: the target is the current connection")
+			return
+		}
+
+		if transfer.DryRun {
+			runTransfer(transfer, targetClient, targetName, release)
+			return
+		}
+
+		ui.confirmOrCancel(fmt.Sprintf("Copy keys matching %s to %s (conflict: %s)?", transfer.Pattern, targetName, conflict), func() {
+			// another transfer may be started while the dialog is displayed
+			if transferStop != nil {
+				release()
+				log("red", "errors: a transfer is running, press x on log to stop it")
+				return
+			}
+
+			runTransfer(transfer, targetClient, targetName, release)
+		}, release)
+	}
+
+	for _, field := range []*tview.InputField{patternInputField, targetInputField, conflictInputField, suffixInputField, concurrencyInputField, rateInputField} {
+		field.SetDoneFunc(func(key tcell.Key) {
+			if key == tcell.KeyEnter {
+				startTransfer()
+			}
+		})
+	}
+
+	logView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == 'x' {
+			// transferStop is cleared when the transfer finished, so that no other transfer can be
+			// started while this one is stopping
+			select {
+			case <-transferStop:
+			default:
+				if transferStop != nil {
+					close(transferStop)
+					log("yellow", "stopping transfer after keys being copied")
+				}
+			}
+			return nil
+		}
+
+		return event
+	})
+
+	inputs := tview.NewFlex().
+		AddItem(patternInputField, 0, 2, false).
+		AddItem(targetInputField, 0, 3, false).
+		AddItem(dryRunCheckbox, 12, 0, false)
+
+	options := tview.NewFlex().
+		AddItem(conflictInputField, 0, 1, false).
+		AddItem(suffixInputField, 0, 1, false).
+		AddItem(concurrencyInputField, 20, 0, false).
+		AddItem(rateInputField, 16, 0, false)
+
+	flex.AddItem(inputs, 1, 0, false).
+		AddItem(options, 1, 0, false).
+		AddItem(statusView, 1, 0, false).
+		AddItem(logView, 0, 1, false).
+		AddItem(tipView, 1, 0, false)
+
+	return &toolPage{
+		name:      "transfer",
+		title:     "Transfer",
+		primitive: flex,
+		focus: []tview.Primitive{
+			patternInputField, targetInputField, dryRunCheckbox, conflictInputField,
+			suffixInputField, concurrencyInputField, rateInputField, logView,
+		},
+		onClose: func() {
+			if transferStop != nil {
+				select {
+				case <-transferStop:
+				default:
+					close(transferStop)
+				}
+			}
+		},
+	}
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -36,6 +37,9 @@ type RedisTUI struct {
 	commandResultPanel *tview.TextView
 	commandMode        bool
 
+	tools       []*toolPage
+	currentTool *toolPage
+
 	leftPanel  *tview.Flex
 	rightPanel *tview.Flex
 
@@ -46,8 +50,28 @@ type RedisTUI struct {
 	redisClient      api.RedisClient
 	outputChan       chan core.OutputMessage
 	uiViewUpdateChan chan func()
+	// releaseClient releases the holding of redisClient by this tab, see clientRefs
+	releaseClient func()
+
+	// workspace is the workspace holding the tab of this connection
+	workspace *Workspace
+	// active is true when the tab of this connection is displayed
+	active bool
+	// stop is closed when the tab of this connection is closed
+	stop chan struct{}
 
 	itemSelectedHandler func(index int, key string) func()
+	keyItems            []string
+	currentKey          string
+	// renderKeyContent display the content of a key in value panel
+	renderKeyContent func(c keyContent)
+	// keyPattern is the pattern of the last search, the key list only displays the first maxKeyLimit matched keys
+	keyPattern string
+	// slotRanges is used for displaying the node of keys in cluster mode
+	slotRanges []api.SlotRange
+
+	live  *liveRefresh
+	watch *keyWatch
 
 	maxKeyLimit       int
 	maxCharacterLimit int
@@ -65,9 +89,10 @@ type RedisTUI struct {
 	commandKeyHistories []string
 }
 
-// NewRedisTUI create a RedisTUI object
-func NewRedisTUI(redisClient api.RedisClient, maxKeyLimit int, version string, gitCommit string, outputChan chan core.OutputMessage, conf config.Config) *RedisTUI {
+// NewRedisTUI create a RedisTUI object for a connection, it's displayed as a tab in app
+func NewRedisTUI(app *tview.Application, redisClient api.RedisClient, maxKeyLimit int, version string, gitCommit string, outputChan chan core.OutputMessage, conf config.Config) *RedisTUI {
 	ui := &RedisTUI{
+		app:                 app,
 		redisClient:         redisClient,
 		maxKeyLimit:         maxKeyLimit,
 		maxCharacterLimit:   maxKeyLimit * 20,
@@ -79,6 +104,7 @@ func NewRedisTUI(redisClient api.RedisClient, maxKeyLimit int, version string, g
 		config:              conf,
 		keyBindings:         core.NewKeyBinding(),
 		uiViewUpdateChan:    make(chan func()),
+		stop:                make(chan struct{}),
 		searchKeyHistories:  make([]string, 0),
 		commandKeyHistories: make([]string, 0),
 	}
@@ -96,6 +122,24 @@ func NewRedisTUI(redisClient api.RedisClient, maxKeyLimit int, version string, g
 
 	ui.commandPanel = ui.createCommandPanel()
 
+	ui.tools = []*toolPage{
+		ui.createHotKeysTool(),
+		ui.createDashboardTool(),
+		ui.createSlowLogTool(),
+		ui.createClientListTool(),
+		ui.createMonitorTool(),
+		ui.createPubSubTool(),
+		ui.createClusterTool(),
+		ui.createReplicationTool(),
+		ui.createConfigTool(),
+		ui.createScriptTool(),
+		ui.createFunctionsTool(),
+		ui.createACLTool(),
+		ui.createDiffTool(),
+		ui.createTransferTool(),
+		ui.createImportTool(),
+	}
+
 	ui.leftPanel = tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(ui.searchPanel, 3, 0, false).
 		AddItem(ui.keyItemsPanel, 0, 1, false).
@@ -104,7 +148,6 @@ func NewRedisTUI(redisClient api.RedisClient, maxKeyLimit int, version string, g
 	ui.rightPanel = tview.NewFlex().SetDirection(tview.FlexRow)
 	ui.redrawRightPanel(ui.mainPanel)
 
-	ui.app = tview.NewApplication()
 	ui.layout = tview.NewFlex().
 		AddItem(ui.leftPanel, 0, 3, false).
 		AddItem(ui.rightPanel, 0, 8, false)
@@ -112,76 +155,109 @@ func NewRedisTUI(redisClient api.RedisClient, maxKeyLimit int, version string, g
 	ui.focusPrimitives = append(ui.focusPrimitives, primitiveKey{Primitive: ui.searchPanel, Key: ui.keyBindings.KeyID("search")})
 	ui.focusPrimitives = append(ui.focusPrimitives, primitiveKey{Primitive: ui.keyItemsPanel, Key: ui.keyBindings.KeyID("keys")})
 
-	ui.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+	ui.pages = tview.NewPages()
+	ui.pages.AddPage("base", ui.layout, true, true)
 
-		if ui.config.Debug {
-			keyName := event.Name()
-			if event.Key() == tcell.KeyRune {
-				keyName = string(event.Rune())
-			}
-			ui.outputChan <- core.OutputMessage{Message: fmt.Sprintf("Key %s pressed", keyName)}
+	return ui
+}
+
+// handleKey handle the global key bindings when the tab of this connection is active
+func (ui *RedisTUI) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	if ui.config.Debug {
+		keyName := event.Name()
+		if event.Key() == tcell.KeyRune {
+			keyName = string(event.Rune())
 		}
+		ui.outputChan <- core.OutputMessage{Message: fmt.Sprintf("Key %s pressed", keyName)}
+	}
 
-		name := ui.keyBindings.SearchKey(event.Key())
-		switch name {
-		case "switch_focus":
-			nextFocusIndex := ui.currentFocusIndex + 1
-			if nextFocusIndex > len(ui.focusPrimitives)-1 {
-				nextFocusIndex = 0
-			}
+	name := ui.keyBindings.SearchKey(event.Key())
+	switch name {
+	case "switch_focus":
+		nextFocusIndex := ui.currentFocusIndex + 1
+		if nextFocusIndex > len(ui.focusPrimitives)-1 {
+			nextFocusIndex = 0
+		}
 
-			ui.app.SetFocus(ui.focusPrimitives[nextFocusIndex].Primitive)
-			ui.currentFocusIndex = nextFocusIndex
+		ui.app.SetFocus(ui.focusPrimitives[nextFocusIndex].Primitive)
+		ui.currentFocusIndex = nextFocusIndex
+
+		return nil
+	case "quit":
+		ui.app.Stop()
+	case "tools":
+		ui.showToolsMenu()
+		return nil
+	case "live_refresh":
+		ui.toggleLiveRefresh()
+		return nil
+	case "watch":
+		ui.toggleWatch()
+		return nil
+	case "switch_db":
+		ui.showDBSwitcher()
+		return nil
+	case "command":
+		if ui.commandMode {
+			ui.commandMode = false
+			ui.redrawRightPanel(ui.mainPanel)
+			ui.app.SetFocus(ui.searchPanel)
+			ui.currentFocusIndex = 0
+		} else {
+			ui.closeTool()
+			ui.commandMode = true
+			ui.redrawRightPanel(ui.commandPanel)
 
-			return nil
-		case "quit":
-			ui.app.Stop()
-		case "command":
-			if ui.commandMode {
-				ui.commandMode = false
-				ui.redrawRightPanel(ui.mainPanel)
-				ui.app.SetFocus(ui.searchPanel)
-				ui.currentFocusIndex = 0
-			} else {
-				ui.commandMode = true
-				ui.redrawRightPanel(ui.commandPanel)
-
-				for i, p := range ui.focusPrimitives {
-					if p.Primitive == ui.commandInputField {
-						ui.app.SetFocus(ui.commandInputField)
-						ui.currentFocusIndex = i
-						break
-					}
-				}
-			}
-		default:
-			for i, pv := range ui.focusPrimitives {
-				if pv.Key == name {
-					ui.app.SetFocus(pv.Primitive)
+			for i, p := range ui.focusPrimitives {
+				if p.Primitive == ui.commandInputField {
+					ui.app.SetFocus(ui.commandInputField)
 					ui.currentFocusIndex = i
 					break
 				}
 			}
 		}
+	default:
+		for i, pv := range ui.focusPrimitives {
+			if pv.Key == name {
+				ui.app.SetFocus(pv.Primitive)
+				ui.currentFocusIndex = i
+				break
+			}
+		}
+	}
 
-		return event
-	})
-
-	return ui
+	return event
 }
 
-// Start create the ui and start the program
-func (ui *RedisTUI) Start() error {
+// start load the keys and start the background jobs of this connection, they are stopped by close
+func (ui *RedisTUI) start() {
 	go func() {
 		for {
 			select {
 			case f := <-ui.uiViewUpdateChan:
+				// updates are discarded after closed, so that the senders are never blocked
+				select {
+				case <-ui.stop:
+					continue
+				default:
+				}
+
 				(func() {
 					defer func() {
 						if err := recover(); err != nil {
 						}
 					}()
-					ui.app.QueueUpdateDraw(f)
+					ui.app.QueueUpdateDraw(func() {
+						// the tab may be closed before the update is executed, the client must not be
+						// retained again after released by close
+						select {
+						case <-ui.stop:
+							return
+						default:
+						}
+
+						f()
+					})
 				})()
 			}
 		}
@@ -192,6 +268,8 @@ func (ui *RedisTUI) Start() error {
 
 		for {
 			select {
+			case <-ui.stop:
+				return
 			case out := <-ui.outputChan:
 				ui.uiViewUpdateChan <- func() {
 					// clear outputPanel to avoid to many message caused ui hangup
@@ -204,70 +282,116 @@ func (ui *RedisTUI) Start() error {
 					ui.outputPanel.SetCurrentItem(-1)
 				}
 			case <-ticker.C:
-				// update server info
-				info, err := api.RedisServerInfo(ui.config, ui.redisClient)
-				if err != nil {
-					ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
-				}
+				ui.uiViewUpdateChan <- ui.refreshServerInfo
+			}
+		}
+	}()
 
-				ui.uiViewUpdateChan <- func() {
-					ui.helpServerInfoPanel.SetText(info)
-				}
+	ui.reloadKeys()
+}
+
+// refreshServerInfo update the server info in help panel in background
+func (ui *RedisTUI) refreshServerInfo() {
+	client, release := ui.retainClient()
+	go func() {
+		defer release()
+
+		info, err := api.RedisServerInfo(client)
+		if err != nil {
+			ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+		}
+
+		ui.uiViewUpdateChan <- func() {
+			// the database may be switched while loading
+			if ui.redisClient == client {
+				ui.helpServerInfoPanel.SetText(ui.serverInfoText(info))
 			}
 		}
 	}()
+}
+
+// close stop the background jobs and release the client of this connection
+func (ui *RedisTUI) close() {
+	ui.closeTool()
+	ui.stopWatch()
+	ui.stopLiveRefresh()
+	close(ui.stop)
+
+	ui.releaseClient()
+}
+
+// retainClient return the client of this connection for a background job, the client is not closed
+// by switching db or closing the tab until the returned func is called. It's called in the UI goroutine
+func (ui *RedisTUI) retainClient() (api.RedisClient, func()) {
+	return ui.redisClient, ui.workspace.clients.retain(ui.redisClient)
+}
+
+// focused return the primitive which has focus when the tab of this connection is displayed
+func (ui *RedisTUI) focused() tview.Primitive {
+	if ui.currentFocusIndex < len(ui.focusPrimitives) {
+		return ui.focusPrimitives[ui.currentFocusIndex].Primitive
+	}
+
+	return ui.keyItemsPanel
+}
+
+// connectionName return the address of this connection, such as 127.0.0.1:6379/0
+func (ui *RedisTUI) connectionName() string {
+	return connectionName(ui.config)
+}
+
+// connectionName return the address of the connection of conf, such as 127.0.0.1:6379/0
+func connectionName(conf config.Config) string {
+	if conf.RDBFile != "" {
+		return fmt.Sprintf("%s/%d (rdb)", filepath.Base(conf.RDBFile), conf.DB)
+	}
+
+	if conf.Cluster {
+		return fmt.Sprintf("%s:%d (cluster)", conf.Host, conf.Port)
+	}
+
+	return fmt.Sprintf("%s:%d/%d", conf.Host, conf.Port, conf.DB)
+}
 
+// reloadKeys load the server info and all keys in background, it's called when started or the database is switched
+func (ui *RedisTUI) reloadKeys() {
+	client, release := ui.retainClient()
+	cluster := ui.config.Cluster
 	go func() {
-		info, err := api.RedisServerInfo(ui.config, ui.redisClient)
+		defer release()
+
+		info, err := api.RedisServerInfo(client)
 		if err != nil {
 			ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
 		}
-		ui.app.QueueUpdateDraw(func() {
-			ui.helpServerInfoPanel.SetText(info)
-		})
+		ui.uiViewUpdateChan <- func() {
+			if ui.redisClient == client {
+				ui.helpServerInfoPanel.SetText(ui.serverInfoText(info))
+			}
+		}
 
-		keys, err := api.RedisAllKeys(ui.redisClient, false)
+		ranges := ui.clusterSlotRanges(client, cluster)
+		keys, err := api.RedisAllKeys(client, false)
 		if err != nil {
 			ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
 			return
 		}
-		ui.app.QueueUpdateDraw(func() {
+		ui.uiViewUpdateChan <- func() {
+			// keys of the previous database are discarded if the database is switched while loading
+			if ui.redisClient != client {
+				return
+			}
+
+			ui.slotRanges = ranges
+			ui.keyPattern = "*"
 			ui.summaryPanel.SetText(fmt.Sprintf(" Total matched: %d", len(keys)))
+			ui.setKeyItems(keys)
 
-			for i, k := range limit(keys, ui.maxKeyLimit) {
-				ui.keyItemsPanel.AddItem(ui.keyItemsFormat(i, k), "", 0, ui.itemSelectedHandler(i, k))
+			if ui.active {
+				ui.setFocus(ui.keyItemsPanel)
 			}
-
-			ui.app.SetFocus(ui.keyItemsPanel)
-		})
+		}
 	}()
-
-	ui.pages = tview.NewPages()
-	ui.pages.AddPage("base", ui.layout, true, true)
-	// welcomeScreen := tview.NewInputField().SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (i int, i2 int, i3 int, i4 int) {
-	// 	// Draw a horizontal line across the middle of the box.
-	// 	centerY := y + height/2
-	// 	for cx := x + 1; cx < x+width-1; cx++ {
-	// 		screen.SetContent(cx, centerY, tview.BoxDrawingsDoubleDownAndHorizontal, nil, tcell.StyleDefault.Foreground(tcell.ColorWhite))
-	// 	}
-	//
-	// 	// Write some text along the horizontal line.
-	// 	tview.Print(screen, " Hello, world! ", x+1, centerY, width-2, tview.AlignCenter, tcell.ColorYellow)
-	//
-	// 	// Space for other content.
-	// 	return x + 1, centerY + 1, width - 2, height - (centerY + 1 - y)
-	// })
-	//
-	// ui.pages.AddPage("welcome_screen", welcomeScreen, true, true)
-	//
-	// go func() {
-	// 	time.Sleep(2 * time.Second)
-	// 	ui.app.QueueUpdateDraw(func() {
-	// 		ui.pages.RemovePage("welcome_screen")
-	// 	})
-	// }()
-
-	return ui.app.SetRoot(ui.pages, true).Run()
 }
 
 func (ui *RedisTUI) redrawRightPanel(center tview.Primitive) {
@@ -277,6 +401,10 @@ func (ui *RedisTUI) redrawRightPanel(center tview.Primitive) {
 		RemoveItem(ui.commandPanel).
 		RemoveItem(ui.helpPanel)
 
+	for _, tool := range ui.tools {
+		ui.rightPanel.RemoveItem(tool.primitive)
+	}
+
 	ui.rightPanel.AddItem(ui.helpPanel, 5, 1, false).
 		AddItem(ui.metaPanel, 4, 1, false).
 		AddItem(center, 0, 7, false).
@@ -290,7 +418,37 @@ func (ui *RedisTUI) createSummaryPanel() *tview.TextView {
 }
 
 func (ui *RedisTUI) keyItemsFormat(index int, key string) string {
-	return fmt.Sprintf("%3d | %s", index+1, key)
+	text := fmt.Sprintf("%3d | %s", index+1, key)
+	if ui.config.Cluster {
+		slot := api.KeySlot(key)
+		node, _ := api.SlotAddr(ui.slotRanges, slot)
+		text += fmt.Sprintf(" [gray](%d@%s)", slot, node)
+	}
+
+	return text
+}
+
+// clusterSlotRanges query the slot ranges of masters in cluster mode
+func (ui *RedisTUI) clusterSlotRanges(client api.RedisClient, cluster bool) []api.SlotRange {
+	if !cluster {
+		return nil
+	}
+
+	ranges, err := api.RedisClusterSlots(client)
+	if err != nil {
+		ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+	}
+
+	return ranges
+}
+
+// setKeyItems replace the items of key items panel, at most maxKeyLimit keys are displayed
+func (ui *RedisTUI) setKeyItems(keys []string) {
+	ui.keyItemsPanel.Clear()
+	ui.keyItems = limit(keys, ui.maxKeyLimit)
+	for i, k := range ui.keyItems {
+		ui.keyItemsPanel.AddItem(ui.keyItemsFormat(i, k), "", 0, ui.itemSelectedHandler(i, k))
+	}
 }
 
 func (ui *RedisTUI) createCommandPanel() *tview.Flex {
@@ -336,14 +494,18 @@ func (ui *RedisTUI) createCommandPanel() *tview.Flex {
 	})
 
 	commandInputField.SetAutocompleteFunc(func(currentText string) (entries []string) {
-		currentText = strings.ToLower(strings.TrimSpace(currentText))
+		target, currentText := api.ParseCommandTarget(strings.ToLower(currentText))
 		if currentText == "" || len(strings.Split(currentText, " ")) > 2 {
 			return
 		}
 
 		cmds := api.RedisMatchedCommands(currentText)
 		for _, c := range cmds {
-			entries = append(entries, c.Command)
+			if target != "" {
+				entries = append(entries, target+" "+c.Command)
+			} else {
+				entries = append(entries, c.Command)
+			}
 		}
 
 		return entries
@@ -376,31 +538,29 @@ func (ui *RedisTUI) createCommandPanel() *tview.Flex {
 		cmdText := commandInputField.GetText()
 		ui.outputChan <- core.OutputMessage{Color: tcell.ColorOrange, Message: fmt.Sprintf("Command %s is processing...", cmdText)}
 
+		client, release := ui.retainClient()
 		go func(cmdText string) {
 			defer func() {
+				release()
 				locked <- struct{}{}
 			}()
-			res, err := api.RedisExecute(ui.redisClient, cmdText)
-			if err != nil {
-				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
-				return
-			}
-
-			// format redis output
 			var output string
+			if target, command := api.ParseCommandTarget(cmdText); target != "" {
+				results, err := api.RedisExecuteOnNodes(client, target, command)
+				if err != nil {
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+					return
+				}
 
-			switch res.(type) {
-			case string:
-				output = res.(string)
-			case []interface{}:
-				var resStrs = make([]string, len(res.([]interface{})))
-				for i, v := range res.([]interface{}) {
-					resStrs[i] = fmt.Sprintf("%v", v)
+				output = formatNodeResults(results)
+			} else {
+				res, err := api.RedisExecute(client, cmdText)
+				if err != nil {
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+					return
 				}
 
-				output = strings.Join(resStrs, "\n")
-			default:
-				output = fmt.Sprintf("%v", res)
+				output = formatCommandResult(res)
 			}
 
 			// If the output content is too long, the interface will be suspended for a long time
@@ -434,6 +594,15 @@ func (ui *RedisTUI) createCommandPanel() *tview.Flex {
 			}
 		}
 	}).SetChangedFunc(func(text string) {
+		target, text := api.ParseCommandTarget(text)
+		if target != "" && text == "" {
+			commandTipView.SetTextColor(tcell.ColorOrange).SetText(fmt.Sprintf(
+				"\nrun on %s, %s, %s or a node by @<node-id>/@<addr>",
+				api.TargetAll, api.TargetMasters, api.TargetReplicas,
+			))
+			return
+		}
+
 		if text == "" {
 			commandTipView.Clear()
 			return
@@ -515,7 +684,7 @@ func (ui *RedisTUI) createSearchPanel() *tview.InputField {
 		}
 		var text = searchArea.GetText()
 
-		ui.keyItemsPanel.Clear()
+		ui.setKeyItems(nil)
 		searchArea.SetText("")
 
 		currentIndex = 0
@@ -539,19 +708,20 @@ func (ui *RedisTUI) createSearchPanel() *tview.InputField {
 		// 	keys, err = ui.redisClient.Keys(text).Result()
 		// }
 		keys, err = api.RedisKeys(ui.redisClient, text)
+		ui.slotRanges = ui.clusterSlotRanges(ui.redisClient, ui.config.Cluster)
 
 		if err != nil {
 			ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
 			return
 		}
 
-		ui.summaryPanel.SetText(fmt.Sprintf(" Total matched: %d", len(keys)))
-		for i, k := range keys {
-			if i > int(ui.maxKeyLimit) {
-				break
-			}
-			ui.keyItemsPanel.AddItem(ui.keyItemsFormat(i, k), "", 0, ui.itemSelectedHandler(i, k))
+		ui.keyPattern = text
+		if text == "" {
+			ui.keyPattern = "*"
 		}
+
+		ui.summaryPanel.SetText(fmt.Sprintf(" Total matched: %d", len(keys)))
+		ui.setKeyItems(keys)
 	})
 	searchArea.SetAutocompleteFunc(func(currentText string) (entries []string) {
 		currentText = strings.TrimSpace(currentText)
@@ -587,6 +757,15 @@ func (ui *RedisTUI) createSearchPanel() *tview.InputField {
 func (ui *RedisTUI) createKeyItemsPanel() *tview.List {
 	keyItemsList := tview.NewList().ShowSecondaryText(false)
 	keyItemsList.SetBorder(true).SetTitle(fmt.Sprintf(" Keys (%s) ", ui.keyBindings.Name("keys")))
+	keyItemsList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == 'e' {
+			ui.showExportDialog()
+			return nil
+		}
+
+		return event
+	})
+
 	return keyItemsList
 }
 
@@ -640,8 +819,12 @@ func (ui *RedisTUI) createHelpPanel() *tview.Flex {
 
 	ui.helpMessagePanel = tview.NewTextView()
 	ui.helpMessagePanel.SetTextColor(tcell.ColorOrange).SetText(fmt.Sprintf(
-		" ❈ %s - open command panel, %s - switch focus, %s - quit",
+		" ❈ %s - open command panel, %s - tools, %s - switch db, %s - live refresh, %s - watch key, e on keys - export, %s - switch focus, %s - quit",
 		ui.keyBindings.Name("command"),
+		ui.keyBindings.Name("tools"),
+		ui.keyBindings.Name("switch_db"),
+		ui.keyBindings.Name("live_refresh"),
+		ui.keyBindings.Name("watch"),
 		ui.keyBindings.Name("switch_focus"),
 		ui.keyBindings.Name("quit"),
 	))
@@ -651,11 +834,107 @@ func (ui *RedisTUI) createHelpPanel() *tview.Flex {
 	return helpPanel
 }
 
-// createKeySelectedHandler create a handler for item selected event
+// serverInfoText format the server info for help panel
+func (ui *RedisTUI) serverInfoText(info *api.ServerInfo) string {
+	if info == nil {
+		return fmt.Sprintf(" Server: %s", ui.connectionName())
+	}
+
+	server := ui.connectionName()
+	if ui.config.Cluster {
+		server = fmt.Sprintf("%s:%d (%d masters)", ui.config.Host, ui.config.Port, len(info.Nodes))
+	}
+
+	keySpace := "-"
+	if ks, ok := info.DBKeyspace(ui.config.DB); ok {
+		keySpace = fmt.Sprintf("keys=%d, expires=%d, avg_ttl=%dms", ks.Keys, ks.Expires, ks.AvgTTL)
+	}
+
+	role := "-"
+	if !ui.config.Cluster {
+		replication := api.ParseReplicationInfo("", info)
+		if replication.IsReplica() {
+			role = fmt.Sprintf("replica of %s (link %s)", replication.MasterAddr, replication.MasterLinkStatus)
+		} else if replication.Role != "" {
+			role = fmt.Sprintf("master (%d replicas)", len(replication.Replicas))
+		}
+	}
+
+	return fmt.Sprintf(
+		" RedisVersion: %s    Memory: %s    Server: %s\n KeySpace: %s    Role: %s",
+		info.String("redis_version"),
+		info.String("used_memory_human"),
+		server,
+		keySpace,
+		role,
+	)
+}
+
+// keyContent is the value of a key loaded for the value panel, at most 1000 elements are loaded for list and zset
+type keyContent struct {
+	key     string
+	keyType string
+	ttl     time.Duration
+
+	value   string
+	values  []string
+	members []api.ZMember
+	// hashValues are only loaded in watch mode for comparing
+	hashValues map[string]string
+
+	// encoding and memory are only loaded for RDB files
+	encoding string
+	memory   int64
+}
+
+// loadKeyContent load the value of key by client, it's called in background by the key watch
+func loadKeyContent(client api.RedisClient, key string, withHashValues bool) (keyContent, error) {
+	c := keyContent{key: key}
+
+	var err error
+	if c.keyType, err = client.Type(key).Result(); err != nil {
+		return c, err
+	}
+	if c.ttl, err = client.TTL(key).Result(); err != nil {
+		return c, err
+	}
+
+	switch c.keyType {
+	case "string":
+		c.value, err = client.Get(key).Result()
+	case "list":
+		c.values, err = client.LRange(key, 0, 1000).Result()
+	case "set":
+		c.values, err = client.SMembers(key).Result()
+	case "zset":
+		values, e := client.ZRangeWithScores(key, 0, 1000).Result()
+		for _, z := range values {
+			c.members = append(c.members, api.ZMember{Member: fmt.Sprintf("%v", z.Member), Score: z.Score})
+		}
+		err = e
+	case "hash":
+		if c.values, err = client.HKeys(key).Result(); err == nil && withHashValues {
+			c.hashValues, err = api.RedisHashValues(client, key)
+		}
+	}
+	if err != nil {
+		return c, err
+	}
+
+	if _, ok := client.(*api.RDBClient); ok {
+		c.memory, _ = client.Do("MEMORY", "USAGE", key).Int64()
+		c.encoding, _ = client.Do("OBJECT", "ENCODING", key).String()
+	}
+
+	return c, nil
+}
+
+// createKeySelectedHandler create a handler for item selected event, and the renderer of key contents
+// used by the key watch
 func (ui *RedisTUI) createKeySelectedHandler() func(index int, key string) func() {
 
 	// 用于KV展示的视图
-	mainStringView := tview.NewTextView()
+	mainStringView := tview.NewTextView().SetDynamicColors(true)
 	mainStringView.SetBorder(true).SetTitle(fmt.Sprintf(" Value (%s) ", ui.keyBindings.Name("key_string_value")))
 
 	mainHashView := tview.NewList().ShowSecondaryText(false)
@@ -664,20 +943,49 @@ func (ui *RedisTUI) createKeySelectedHandler() func(index int, key string) func(
 	mainListView := tview.NewList().ShowSecondaryText(false).SetSecondaryTextColor(tcell.ColorOrangeRed)
 	mainListView.SetBorder(true).SetTitle(fmt.Sprintf(" Value (%s) ", ui.keyBindings.Name("key_list_value")))
 
-	return func(index int, key string) func() {
-		return func() {
-			keyType, err := ui.redisClient.Type(key).Result()
-			if err != nil {
-				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
-				return
-			}
+	// fields of the hash displayed in mainHashView, the value of the selected field is loaded by HGET
+	var hashKey string
+	var hashFields []string
+	mainHashView.SetSelectedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
+		if index >= len(hashFields) {
+			return
+		}
 
-			ttl, err := ui.redisClient.TTL(key).Result()
-			if err != nil {
-				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
-				return
+		k := hashFields[index]
+		val, err := ui.redisClient.HGet(hashKey, k).Result()
+		if err != nil {
+			ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+			return
+		}
+
+		mainStringView.SetText(fmt.Sprintf(" %s", tview.Escape(val))).SetTitle(fmt.Sprintf(" Value: %s ", k))
+	})
+
+	ui.renderKeyContent = func(c keyContent) {
+		key := c.key
+
+		// snapshot of elements, it's used for highlighting the changes in watch mode
+		watching := ui.watch != nil && ui.watch.key == key
+		// the watched key is refreshed in place if its type is not changed, so that the selection,
+		// scrolling and focus of the value panel are kept
+		refreshing := watching && ui.watch.prev != nil && ui.watch.keyType == c.keyType && ui.currentKey == key
+		if watching {
+			ui.watch.reset()
+		}
+		ui.currentKey = key
+
+		snapshot := make(map[string]string)
+		mark := func(id, value, text string) string {
+			snapshot[id] = value
+			text = tview.Escape(text)
+			if !watching {
+				return text
 			}
 
+			return ui.watch.mark(id, value, text)
+		}
+
+		if !refreshing {
 			// 移除主区域的边框，因为展示区域已经带有边框了
 			ui.mainPanel.RemoveItem(ui.welcomeScreen).SetBorder(false)
 
@@ -695,93 +1003,109 @@ func (ui *RedisTUI) createKeySelectedHandler() func(index int, key string) func(
 			ui.mainPanel.RemoveItem(mainListView)
 
 			// 根据不同的kv类型，展示不同的视图
-			switch keyType {
+			switch c.keyType {
 			case "string":
-				result, err := ui.redisClient.Get(key).Result()
-				if err != nil {
-					ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
-					return
-				}
-
-				ui.mainPanel.AddItem(mainStringView.SetText(fmt.Sprintf(" %s", result)), 0, 1, false)
+				ui.mainPanel.AddItem(mainStringView, 0, 1, false)
 				ui.focusPrimitives = append(ui.focusPrimitives, primitiveKey{Primitive: mainStringView, Key: ui.keyBindings.KeyID("key_string_value")})
-			case "list":
-				values, err := ui.redisClient.LRange(key, 0, 1000).Result()
-				if err != nil {
-					ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
-					return
-				}
-
-				for i, v := range values {
-					mainListView.AddItem(fmt.Sprintf(" %3d | %s", i+1, v), "", 0, nil)
-				}
-
+			case "list", "set", "zset":
+				mainListView.ShowSecondaryText(c.keyType == "zset")
 				ui.mainPanel.AddItem(mainListView, 0, 1, false)
 				ui.focusPrimitives = append(ui.focusPrimitives, primitiveKey{Primitive: mainListView, Key: ui.keyBindings.KeyID("key_list_value")})
+			case "hash":
+				ui.mainPanel.AddItem(mainHashView, 0, 3, false).
+					AddItem(mainStringView, 0, 7, false)
 
-			case "set":
-				values, err := ui.redisClient.SMembers(key).Result()
-				if err != nil {
-					ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
-					return
-				}
-
-				for i, v := range values {
-					mainListView.AddItem(fmt.Sprintf(" %3d | %s", i+1, v), "", 0, nil)
-				}
-
-				ui.mainPanel.AddItem(mainListView, 0, 1, false)
-				ui.focusPrimitives = append(ui.focusPrimitives, primitiveKey{Primitive: mainListView, Key: ui.keyBindings.KeyID("key_list_value")})
+				ui.focusPrimitives = append(ui.focusPrimitives, primitiveKey{Primitive: mainHashView, Key: ui.keyBindings.KeyID("key_hash")})
+				ui.focusPrimitives = append(ui.focusPrimitives, primitiveKey{Primitive: mainStringView, Key: ui.keyBindings.KeyID("key_string_value")})
+			}
 
-			case "zset":
-				values, err := ui.redisClient.ZRangeWithScores(key, 0, 1000).Result()
-				if err != nil {
-					ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
-					return
-				}
+			ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("query %s OK, type=%s, ttl=%s", key, c.keyType, c.ttl.String())}
+		}
 
-				mainListView.ShowSecondaryText(true)
-				for i, z := range values {
-					val := fmt.Sprintf(" %3d | %v", i+1, z.Member)
-					score := fmt.Sprintf("    Score: %v", z.Score)
+		switch c.keyType {
+		case "string":
+			mainStringView.SetText(mark("", c.value, fmt.Sprintf(" %s", c.value)))
+		case "list":
+			// elements are identified by value and occurrence instead of index, so that pushing an element
+			// doesn't mark all the others as changed
+			occurrences := make(map[string]int)
+			items := make([]string, len(c.values))
+			for i, v := range c.values {
+				occurrences[v]++
+				items[i] = mark(fmt.Sprintf("%s\x00%d", v, occurrences[v]), "", fmt.Sprintf(" %3d | %s", i+1, v))
+			}
+			setListItems(mainListView, items, nil)
+		case "set":
+			items := make([]string, len(c.values))
+			for i, v := range c.values {
+				items[i] = mark(v, "", fmt.Sprintf(" %3d | %s", i+1, v))
+			}
+			setListItems(mainListView, items, nil)
+		case "zset":
+			items, scores := make([]string, len(c.members)), make([]string, len(c.members))
+			for i, z := range c.members {
+				items[i] = mark(z.Member, fmt.Sprintf("%v", z.Score), fmt.Sprintf(" %3d | %v", i+1, z.Member))
+				scores[i] = fmt.Sprintf("    Score: %v", z.Score)
+			}
+			setListItems(mainListView, items, scores)
+		case "hash":
+			hashKey, hashFields = key, c.values
+			items := make([]string, len(c.values))
+			for i, k := range c.values {
+				items[i] = mark(k, c.hashValues[k], fmt.Sprintf(" %3d | %s", i+1, k))
+			}
+			setListItems(mainHashView, items, nil)
+		}
 
-					mainListView.AddItem(val, score, 0, nil)
-				}
+		meta := fmt.Sprintf("KeyID: %s\nType: %s, TTL: %s", key, c.keyType, c.ttl.String())
+		if ui.config.RDBFile != "" {
+			// keys of RDB file are estimated, TTLs are relative to the creation of snapshot
+			meta += fmt.Sprintf(" (at snapshot), Encoding: %s, Memory: ~%s", c.encoding, formatBytes(float64(c.memory)))
+		}
+		ui.metaPanel.SetText(meta).SetTextAlign(tview.AlignCenter)
 
-				ui.mainPanel.AddItem(mainListView, 0, 1, false)
-				ui.focusPrimitives = append(ui.focusPrimitives, primitiveKey{Primitive: mainListView, Key: ui.keyBindings.KeyID("key_list_value")})
+		if watching {
+			ui.watch.update(c.keyType, c.ttl, snapshot)
+			ui.renderWatchMeta()
+		}
+	}
 
-			case "hash":
-				hashKeys, err := ui.redisClient.HKeys(key).Result()
-				if err != nil {
-					ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
-					return
-				}
+	return func(index int, key string) func() {
+		return func() {
+			if ui.watch != nil && ui.watch.key != key {
+				ui.stopWatch()
+			}
 
-				for i, k := range hashKeys {
-					mainHashView.AddItem(fmt.Sprintf(" %3d | %s", i+1, k), "", 0, (func(k string) func() {
-						return func() {
-							val, err := ui.redisClient.HGet(key, k).Result()
-							if err != nil {
-								ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
-								return
-							}
+			c, err := loadKeyContent(ui.redisClient, key, ui.watch != nil)
+			if err != nil {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				return
+			}
 
-							mainStringView.SetText(fmt.Sprintf(" %s", val)).SetTitle(fmt.Sprintf(" Value: %s ", k))
-						}
-					})(k))
-				}
+			ui.renderKeyContent(c)
+		}
+	}
+}
 
-				ui.mainPanel.AddItem(mainHashView, 0, 3, false).
-					AddItem(mainStringView, 0, 7, false)
+// setListItems replace the items of list by texts, items are updated in place so that the current item
+// and scrolling are kept, secondaries are the secondary texts of items and can be nil
+func setListItems(list *tview.List, texts []string, secondaries []string) {
+	for i, text := range texts {
+		secondary := ""
+		if secondaries != nil {
+			secondary = secondaries[i]
+		}
 
-				ui.focusPrimitives = append(ui.focusPrimitives, primitiveKey{Primitive: mainHashView, Key: ui.keyBindings.KeyID("key_hash")})
-				ui.focusPrimitives = append(ui.focusPrimitives, primitiveKey{Primitive: mainStringView, Key: ui.keyBindings.KeyID("key_string_value")})
-			}
-			ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("query %s OK, type=%s, ttl=%s", key, keyType, ttl.String())}
-			ui.metaPanel.SetText(fmt.Sprintf("KeyID: %s\nType: %s, TTL: %s", key, keyType, ttl.String())).SetTextAlign(tview.AlignCenter)
+		if i < list.GetItemCount() {
+			list.SetItemText(i, text, secondary)
+		} else {
+			list.AddItem(text, secondary, 0, nil)
 		}
 	}
+
+	for list.GetItemCount() > len(texts) {
+		list.RemoveItem(list.GetItemCount() - 1)
+	}
 }
 
 func limit(input []string, maxReturn int) []string {
@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+// keyWatch keeps the state of the key being watched
+type keyWatch struct {
+	key      string
+	interval time.Duration
+	stop     chan struct{}
+
+	keyType     string
+	ttl         time.Duration
+	expireAt    time.Time
+	lastRefresh time.Time
+
+	prev    map[string]string
+	added   int
+	changed int
+	removed int
+}
+
+// mark highlight the text of an element if it's added or changed since last refresh
+func (w *keyWatch) mark(id, value, text string) string {
+	if w.prev == nil {
+		return text
+	}
+
+	old, ok := w.prev[id]
+	if !ok {
+		w.added++
+		return "[green]" + text
+	}
+
+	if old != value {
+		w.changed++
+		return "[yellow]" + text
+	}
+
+	return text
+}
+
+// update record the snapshot of current refresh, it's compared with the next one
+func (w *keyWatch) update(keyType string, ttl time.Duration, snapshot map[string]string) {
+	if w.prev != nil {
+		for id := range w.prev {
+			if _, ok := snapshot[id]; !ok {
+				w.removed++
+			}
+		}
+	}
+
+	w.keyType = keyType
+	w.ttl = ttl
+	w.expireAt = time.Now().Add(ttl)
+	w.lastRefresh = time.Now()
+	w.prev = snapshot
+}
+
+// reset clear the counters of changes, it's called before every refresh of the key
+func (w *keyWatch) reset() {
+	w.added, w.changed, w.removed = 0, 0, 0
+}
+
+// toggleWatch start or stop watching the key displayed in value panel
+func (ui *RedisTUI) toggleWatch() {
+	if ui.watch != nil {
+		key := ui.watch.key
+		ui.stopWatch()
+		ui.itemSelectedHandler(0, key)()
+		ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("stop watching %s", key)}
+		return
+	}
+
+	if ui.currentKey == "" {
+		ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: "errors: select a key before watching"}
+		return
+	}
+
+	interval := ui.config.WatchInterval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	watch := &keyWatch{key: ui.currentKey, interval: interval, stop: make(chan struct{})}
+	ui.watch = watch
+	ui.itemSelectedHandler(0, watch.key)()
+	ui.outputChan <- core.OutputMessage{Color: tcell.ColorGreen, Message: fmt.Sprintf("start watching %s every %s", watch.key, interval)}
+
+	// the watch is stopped when switching db, so the client is released then
+	client, release := ui.retainClient()
+	go func() {
+		defer release()
+
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		lastLoad := time.Now()
+		for {
+			select {
+			case <-watch.stop:
+				return
+			case <-ticker.C:
+			}
+
+			// the value is loaded in background, only the rendering is done in UI goroutine, so
+			// that a large or slow key doesn't freeze the interface
+			var content *keyContent
+			if time.Since(lastLoad) >= watch.interval-100*time.Millisecond {
+				lastLoad = time.Now()
+				c, err := loadKeyContent(client, watch.key, true)
+				if err != nil {
+					ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s", err)}
+				} else {
+					content = &c
+				}
+			}
+
+			ui.uiViewUpdateChan <- func() {
+				if ui.watch != watch {
+					return
+				}
+
+				if content != nil {
+					ui.renderKeyContent(*content)
+				}
+
+				ui.renderWatchMeta()
+			}
+		}
+	}()
+}
+
+// stopWatch stop watching the key
+func (ui *RedisTUI) stopWatch() {
+	if ui.watch == nil {
+		return
+	}
+
+	close(ui.watch.stop)
+	ui.watch = nil
+}
+
+// renderWatchMeta display the TTL countdown and changes of the watched key in meta panel
+func (ui *RedisTUI) renderWatchMeta() {
+	w := ui.watch
+	if w == nil {
+		return
+	}
+
+	ttl := w.ttl.String()
+	if w.ttl > 0 {
+		remain := time.Until(w.expireAt)
+		if remain < 0 {
+			remain = 0
+		}
+
+		ttl = fmt.Sprintf("[orange]%s[white]", remain.Truncate(time.Second))
+	}
+
+	ui.metaPanel.SetText(fmt.Sprintf(
+		"KeyID: %s [green](watching every %s)[white]\nType: %s, TTL: %s, [green]added: %d[white], [yellow]changed: %d[white], [red]removed: %d",
+		tview.Escape(w.key),
+		w.interval,
+		w.keyType,
+		ttl,
+		w.added,
+		w.changed,
+		w.removed,
+	))
+}
@@ -0,0 +1,170 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+var sparkBlocks = []rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// sparkline is a primitive which draws the recent values of a metric as bars
+type sparkline struct {
+	*tview.Box
+
+	label    string
+	values   []float64
+	capacity int
+	color    tcell.Color
+	format   func(v float64) string
+}
+
+// newSparkline create a sparkline which keeps at most capacity values
+func newSparkline(label string, capacity int, color tcell.Color, format func(v float64) string) *sparkline {
+	s := &sparkline{
+		Box:      tview.NewBox(),
+		label:    label,
+		values:   make([]float64, 0, capacity),
+		capacity: capacity,
+		color:    color,
+		format:   format,
+	}
+
+	s.SetBorder(true).SetTitle(fmt.Sprintf(" %s ", label))
+	return s
+}
+
+// Push append a value to the sparkline, the oldest value is dropped when it is full
+func (s *sparkline) Push(v float64) {
+	if len(s.values) >= s.capacity {
+		s.values = s.values[1:]
+	}
+
+	s.values = append(s.values, v)
+	s.SetTitle(fmt.Sprintf(" %s: %s ", s.label, s.format(v)))
+}
+
+// Reset remove all values in the sparkline
+func (s *sparkline) Reset() {
+	s.values = s.values[:0]
+	s.SetTitle(fmt.Sprintf(" %s ", s.label))
+}
+
+// Draw draws this primitive onto the screen
+func (s *sparkline) Draw(screen tcell.Screen) {
+	s.Box.Draw(screen)
+
+	x, y, width, height := s.GetInnerRect()
+	if width <= 0 || height <= 0 || len(s.values) == 0 {
+		return
+	}
+
+	values := s.values
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	min, max := values[0], values[0]
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+
+	// keep some space for the minimum value, so that a flat line is still visible
+	base := min - (max-min)/float64(height*2)
+	if max == min {
+		base = min - 1
+	}
+
+	style := tcell.StyleDefault.Foreground(s.color)
+	offset := width - len(values)
+	for i, v := range values {
+		eighths := int(math.Round((v - base) / (max - base) * float64(height*8)))
+		if max == base {
+			eighths = 0
+		}
+
+		for row := 0; row < height; row++ {
+			level := eighths - row*8
+			if level <= 0 {
+				break
+			}
+			if level > 8 {
+				level = 8
+			}
+
+			screen.SetContent(x+offset+i, y+height-1-row, sparkBlocks[level], nil, style)
+		}
+	}
+}
+
+// gauge is a primitive which draws a ratio as a horizontal bar
+type gauge struct {
+	*tview.Box
+
+	label string
+	ratio float64
+	text  string
+	color tcell.Color
+}
+
+// newGauge create a gauge
+func newGauge(label string, color tcell.Color) *gauge {
+	g := &gauge{Box: tview.NewBox(), label: label, color: color}
+	g.SetBorder(true).SetTitle(fmt.Sprintf(" %s ", label))
+
+	return g
+}
+
+// SetRatio update the ratio (0 ~ 1) of the gauge and the text displayed on it
+func (g *gauge) SetRatio(ratio float64, text string) {
+	g.ratio = math.Max(0, math.Min(1, ratio))
+	g.text = text
+}
+
+// Draw draws this primitive onto the screen
+func (g *gauge) Draw(screen tcell.Screen) {
+	g.Box.Draw(screen)
+
+	x, y, width, height := g.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	filled := int(math.Round(g.ratio * float64(width)))
+	style := tcell.StyleDefault.Foreground(g.color)
+	for row := 0; row < height; row++ {
+		for col := 0; col < width; col++ {
+			if col < filled {
+				screen.SetContent(x+col, y+row, '█', nil, style)
+			} else {
+				screen.SetContent(x+col, y+row, '░', nil, tcell.StyleDefault.Foreground(tcell.ColorGray))
+			}
+		}
+	}
+
+	tview.Print(screen, fmt.Sprintf(" %.1f%% %s ", g.ratio*100, g.text), x, y+height/2, width, tview.AlignCenter, tcell.ColorWhite)
+}
+
+// formatBytes format bytes in human readable form
+func formatBytes(v float64) string {
+	units := []string{"B", "K", "M", "G", "T"}
+	i := 0
+	for v >= 1024 && i < len(units)-1 {
+		v /= 1024
+		i++
+	}
+
+	return fmt.Sprintf("%.2f%s", v, units[i])
+}
+
+// formatNumber format a number without trailing zeros
+func formatNumber(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%d", int64(v))
+	}
+
+	return fmt.Sprintf("%.2f", v)
+}
@@ -0,0 +1,410 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/mylxsw/redis-tui/api"
+	"github.com/mylxsw/redis-tui/config"
+	"github.com/mylxsw/redis-tui/core"
+	"github.com/rivo/tview"
+)
+
+const healthCheckInterval = 5 * time.Second
+
+// connectionHealth is the result of the latest PING to a connection
+type connectionHealth struct {
+	checked bool
+	latency time.Duration
+	err     error
+}
+
+// clientRefs counts the holders of clients. The client of a tab may still be used by background
+// jobs, such as diff and transfer, after the tab switches db or is closed, so it's closed when the
+// last holder releases it
+type clientRefs struct {
+	lock sync.Mutex
+	refs map[api.RedisClient]int
+}
+
+// retain add a holder of client, the returned func releases it and can be called more than once
+func (r *clientRefs) retain(client api.RedisClient) func() {
+	r.lock.Lock()
+	r.refs[client]++
+	r.lock.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { r.release(client) })
+	}
+}
+
+func (r *clientRefs) release(client api.RedisClient) {
+	r.lock.Lock()
+	r.refs[client]--
+	n := r.refs[client]
+	if n <= 0 {
+		delete(r.refs, client)
+	}
+	r.lock.Unlock()
+
+	if n <= 0 {
+		if c, ok := client.(io.Closer); ok {
+			c.Close()
+		}
+	}
+}
+
+// Workspace holds the connections opened in tabs, every connection is a RedisTUI with its own
+// client, key list, histories and output
+type Workspace struct {
+	app    *tview.Application
+	pages  *tview.Pages
+	tabBar *tview.TextView
+	layout *tview.Flex
+
+	tabs    []*RedisTUI
+	current int
+	nextID  int
+	pageIDs map[*RedisTUI]string
+	health  map[*RedisTUI]connectionHealth
+	clients *clientRefs
+
+	maxKeyLimit int
+	version     string
+	gitCommit   string
+	config      config.Config
+	keyBindings core.KeyBindings
+}
+
+// NewWorkspace create a workspace, the connection of conf is opened in the first tab when started
+func NewWorkspace(maxKeyLimit int, version string, gitCommit string, conf config.Config) *Workspace {
+	w := &Workspace{
+		app:         tview.NewApplication(),
+		pages:       tview.NewPages(),
+		tabBar:      tview.NewTextView().SetDynamicColors(true),
+		pageIDs:     make(map[*RedisTUI]string),
+		health:      make(map[*RedisTUI]connectionHealth),
+		clients:     &clientRefs{refs: make(map[api.RedisClient]int)},
+		maxKeyLimit: maxKeyLimit,
+		version:     version,
+		gitCommit:   gitCommit,
+		config:      conf,
+		keyBindings: core.NewKeyBinding(),
+	}
+
+	w.layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(w.tabBar, 1, 0, false).
+		AddItem(w.pages, 0, 1, false)
+
+	w.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch w.keyBindings.SearchKey(event.Key()) {
+		case "next_tab":
+			if !w.pages.HasPage("connections") && len(w.tabs) > 1 {
+				w.switchTo((w.current + 1) % len(w.tabs))
+			}
+			return nil
+		case "connections":
+			w.toggleConnections()
+			return nil
+		case "quit":
+			// Esc closes the dialog of the current tab, such as the export dialog
+			if event.Key() == tcell.KeyEscape && !w.pages.HasPage("connections") && w.tabs[w.current].hasDialog() {
+				return event
+			}
+
+			for _, ui := range w.tabs {
+				ui.restoreKeyspaceEvents()
+			}
+
+			w.app.Stop()
+			return nil
+		}
+
+		// the connections menu is not a part of any tab, key bindings of tabs are disabled when it's displayed
+		if w.pages.HasPage("connections") {
+			return event
+		}
+
+		return w.tabs[w.current].handleKey(event)
+	})
+
+	return w
+}
+
+// Start open the connection in the first tab and start the program
+func (w *Workspace) Start() error {
+	w.open(w.config)
+
+	go func() {
+		ticker := time.NewTicker(healthCheckInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			w.app.QueueUpdate(func() {
+				w.checkHealth(w.tabs)
+			})
+		}
+	}()
+
+	return w.app.SetRoot(w.layout, true).Run()
+}
+
+// open create a tab for the connection of conf and switch to it
+func (w *Workspace) open(conf config.Config) {
+	outputChan := make(chan core.OutputMessage, 100)
+	ui := NewRedisTUI(w.app, api.NewRedisClient(conf, outputChan), w.maxKeyLimit, w.version, w.gitCommit, outputChan, conf)
+
+	ui.workspace = w
+	ui.releaseClient = w.clients.retain(ui.redisClient)
+
+	w.nextID++
+	w.pageIDs[ui] = fmt.Sprintf("tab-%d", w.nextID)
+	w.tabs = append(w.tabs, ui)
+	w.pages.AddPage(w.pageIDs[ui], ui.pages, true, false)
+
+	ui.start()
+	w.switchTo(len(w.tabs) - 1)
+
+	w.checkHealth([]*RedisTUI{ui})
+}
+
+// closeTab close the connection and remove its tab, the last tab can't be closed
+func (w *Workspace) closeTab(ui *RedisTUI) {
+	if len(w.tabs) == 1 {
+		ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: "errors: the last connection can't be closed"}
+		return
+	}
+
+	for i, tab := range w.tabs {
+		if tab != ui {
+			continue
+		}
+
+		ui.close()
+		w.pages.RemovePage(w.pageIDs[ui])
+		delete(w.pageIDs, ui)
+		delete(w.health, ui)
+		w.tabs = append(w.tabs[:i], w.tabs[i+1:]...)
+
+		next := w.current
+		if next >= len(w.tabs) {
+			next = len(w.tabs) - 1
+		}
+		w.switchTo(next)
+		return
+	}
+}
+
+// switchTo display the tab at index
+func (w *Workspace) switchTo(index int) {
+	for _, tab := range w.tabs {
+		tab.active = false
+	}
+
+	ui := w.tabs[index]
+	ui.active = true
+	w.current = index
+
+	w.pages.SwitchToPage(w.pageIDs[ui])
+	w.app.SetFocus(ui.focused())
+	w.renderTabBar()
+}
+
+// checkHealth PING the connections in background and update the tab bar with the results, it's
+// called in the UI goroutine, the clients are retained until the PINGs are finished
+func (w *Workspace) checkHealth(tabs []*RedisTUI) {
+	clients := make(map[*RedisTUI]api.RedisClient, len(tabs))
+	releases := make([]func(), 0, len(tabs))
+	for _, ui := range tabs {
+		client, release := ui.retainClient()
+		clients[ui] = client
+		releases = append(releases, release)
+	}
+
+	go func() {
+		results := make(map[*RedisTUI]connectionHealth, len(clients))
+		for ui, client := range clients {
+			start := time.Now()
+			err := client.Do("PING").Err()
+			results[ui] = connectionHealth{checked: true, latency: time.Since(start), err: err}
+		}
+
+		for _, release := range releases {
+			release()
+		}
+
+		w.app.QueueUpdateDraw(func() {
+			for ui, health := range results {
+				// the result is discarded if the tab is closed or has switched db since
+				if _, ok := w.pageIDs[ui]; ok && ui.redisClient == clients[ui] {
+					w.health[ui] = health
+				}
+			}
+
+			w.renderTabBar()
+		})
+	}()
+}
+
+// tabTitle return the title of tab with a colored health indicator
+func (w *Workspace) tabTitle(index int) string {
+	ui := w.tabs[index]
+
+	status := "[gray]●[-]"
+	if health := w.health[ui]; health.err != nil {
+		status = "[red]●[-]"
+	} else if health.checked {
+		status = "[green]●[-]"
+	}
+
+	return fmt.Sprintf("%d %s %s", index+1, tview.Escape(ui.connectionName()), status)
+}
+
+// renderTabBar display the tabs, the current one is highlighted
+func (w *Workspace) renderTabBar() {
+	titles := make([]string, 0, len(w.tabs))
+	for i := range w.tabs {
+		title := fmt.Sprintf(" %s ", w.tabTitle(i))
+		if i == w.current {
+			title = "[::r]" + title + "[::-]"
+		}
+
+		titles = append(titles, title)
+	}
+
+	w.tabBar.SetText(fmt.Sprintf("%s  [orange]%s - next tab, %s - connections",
+		strings.Join(titles, "│"), w.keyBindings.Name("next_tab"), w.keyBindings.Name("connections")))
+}
+
+// toggleConnections display or hide the menu for switching, opening and closing connections
+func (w *Workspace) toggleConnections() {
+	pageID := "connections"
+	if w.pages.HasPage(pageID) {
+		w.pages.HidePage(pageID).RemovePage(pageID)
+		w.app.SetFocus(w.tabs[w.current].focused())
+		return
+	}
+
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetBorder(true).SetTitle(fmt.Sprintf(" Connections (%s) ", w.keyBindings.Name("connections")))
+	urlInputField := tview.NewInputField().SetLabel(" Open ").
+		SetPlaceholder("redis://[username:password@]host:port[/db][?cluster=true]")
+	tipView := tview.NewTextView().SetDynamicColors(true).
+		SetText(" [orange]Enter - switch, d - close connection, Tab - open new connection")
+
+	closeMenu := func() {
+		w.pages.HidePage(pageID).RemovePage(pageID)
+	}
+
+	for i, ui := range w.tabs {
+		text := w.tabTitle(i)
+		if health := w.health[ui]; health.err != nil {
+			text += fmt.Sprintf(" [red]%s", tview.Escape(health.err.Error()))
+		} else if health.checked {
+			text += fmt.Sprintf(" [gray]%s", health.latency.Round(time.Microsecond))
+		}
+
+		list.AddItem(text, "", 0, nil)
+	}
+	list.SetCurrentItem(w.current)
+
+	list.SetSelectedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
+		closeMenu()
+		w.switchTo(index)
+	})
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyTab:
+			w.app.SetFocus(urlInputField)
+		case event.Key() == tcell.KeyRune && event.Rune() == 'd':
+			index := list.GetCurrentItem()
+			closeMenu()
+			w.switchTo(index)
+
+			ui := w.tabs[index]
+			// a slot migration stopped halfway leaves the slot migrating, so it's stopped by user first
+			if job := ui.busyJob(); job != "" {
+				ui.outputChan <- core.OutputMessage{Color: tcell.ColorRed, Message: fmt.Sprintf("errors: %s is running, stop it before closing the connection", job)}
+				return nil
+			}
+
+			ui.confirm(fmt.Sprintf("Close connection %s?", ui.connectionName()), func() {
+				w.closeTab(ui)
+			})
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	urlInputField.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyTab {
+			w.app.SetFocus(list)
+			return nil
+		}
+
+		return event
+	})
+
+	urlInputField.SetDoneFunc(func(key tcell.Key) {
+		text := strings.TrimSpace(urlInputField.GetText())
+		if key != tcell.KeyEnter || text == "" {
+			return
+		}
+
+		conf, err := config.ParseURL(w.config, text)
+		if err != nil {
+			tipView.SetText(fmt.Sprintf(" [red]errors: %s", tview.Escape(err.Error())))
+			return
+		}
+
+		closeMenu()
+		w.open(conf)
+	})
+
+	menu := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(list, 0, 1, false).
+		AddItem(urlInputField, 1, 0, false).
+		AddItem(tipView, 1, 0, false)
+
+	w.pages.AddPage(pageID, modal(menu, 90, len(w.tabs)+4), true, true)
+	w.app.SetFocus(list)
+}
+
+// resolveConnection return the client of target, which is the number of a tab or a url such as
+// redis://host:port/db. The client of tab is retained and a new client is created for url, both
+// are released by the returned func. It's called in the UI goroutine
+func (w *Workspace) resolveConnection(base config.Config, target string) (api.RedisClient, string, func(), error) {
+	if n, err := strconv.Atoi(target); err == nil {
+		if n < 1 || n > len(w.tabs) {
+			return nil, "", nil, fmt.Errorf("tab %d doesn't exist", n)
+		}
+
+		ui := w.tabs[n-1]
+		client, release := ui.retainClient()
+		return client, ui.connectionName(), release, nil
+	}
+
+	conf, err := config.ParseURL(base, target)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	// commands are not printed for the temporary client, since there is no output panel for it
+	conf.Debug = false
+	client := api.NewRedisClient(conf, nil)
+
+	return client, connectionName(conf), func() {
+		if c, ok := client.(io.Closer); ok {
+			c.Close()
+		}
+	}, nil
+}